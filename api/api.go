@@ -39,6 +39,14 @@ type QueryOptions struct {
 	// If set, used as prefix for resource list searches
 	Prefix string
 
+	// PerPage is the number of entries to be returned by lists that support
+	// pagination. Zero returns every entry.
+	PerPage int32
+
+	// NextToken is the token used to indicate where to start paging, as
+	// returned in the QueryMeta of the previous page.
+	NextToken string
+
 	// Set HTTP parameters on the query.
 	Params map[string]string
 }
@@ -65,6 +73,10 @@ type QueryMeta struct {
 
 	// How long did the request take
 	RequestTime time.Duration
+
+	// NextToken is set by lists that support pagination if there are more
+	// entries to be read. It is passed as the NextToken of the next query.
+	NextToken string
 }
 
 // WriteMeta is used to return meta data about a write
@@ -316,6 +328,12 @@ func (r *request) setQueryOptions(q *QueryOptions) {
 	if q.Prefix != "" {
 		r.params.Set("prefix", q.Prefix)
 	}
+	if q.PerPage != 0 {
+		r.params.Set("per_page", strconv.FormatInt(int64(q.PerPage), 10))
+	}
+	if q.NextToken != "" {
+		r.params.Set("next_token", q.NextToken)
+	}
 	for k, v := range q.Params {
 		r.params.Set(k, v)
 	}
@@ -580,6 +598,9 @@ func parseQueryMeta(resp *http.Response, q *QueryMeta) error {
 	default:
 		q.KnownLeader = false
 	}
+
+	// Parse the X-Nomad-NextToken
+	q.NextToken = header.Get("X-Nomad-NextToken")
 	return nil
 }
 
@@ -185,6 +185,7 @@ func TestParseQueryMeta(t *testing.T) {
 	resp.Header.Set("X-Nomad-Index", "12345")
 	resp.Header.Set("X-Nomad-LastContact", "80")
 	resp.Header.Set("X-Nomad-KnownLeader", "true")
+	resp.Header.Set("X-Nomad-NextToken", "abc")
 
 	qm := &QueryMeta{}
 	if err := parseQueryMeta(resp, qm); err != nil {
@@ -200,6 +201,9 @@ func TestParseQueryMeta(t *testing.T) {
 	if !qm.KnownLeader {
 		t.Fatalf("Bad: %v", qm)
 	}
+	if qm.NextToken != "abc" {
+		t.Fatalf("Bad: %v", qm)
+	}
 }
 
 func TestParseWriteMeta(t *testing.T) {
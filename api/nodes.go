@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"time"
 )
 
 // Nodes is used to query node-related API endpoints
@@ -108,6 +109,45 @@ func (n *Nodes) GC(nodeID string, q *QueryOptions) error {
 	return err
 }
 
+// RefreshFingerprints is used to make the client re-run its fingerprinters and
+// update the node immediately.
+func (n *Nodes) RefreshFingerprints(nodeID string, q *WriteOptions) error {
+	node, _, err := n.client.Nodes().Info(nodeID, nil)
+	if err != nil {
+		return err
+	}
+	if node.HTTPAddr == "" {
+		return fmt.Errorf("http addr of the node %q is running is not advertised", nodeID)
+	}
+	client, err := NewClient(n.client.config.CopyConfig(node.HTTPAddr, node.TLSEnabled))
+	if err != nil {
+		return err
+	}
+	_, err = client.write("/v1/client/fingerprint", nil, nil, q)
+	return err
+}
+
+// FingerprintDurations returns the duration of the last run of each
+// fingerprinter and driver fingerprint of the node by name.
+func (n *Nodes) FingerprintDurations(nodeID string, q *QueryOptions) (map[string]time.Duration, error) {
+	node, _, err := n.client.Nodes().Info(nodeID, q)
+	if err != nil {
+		return nil, err
+	}
+	if node.HTTPAddr == "" {
+		return nil, fmt.Errorf("http addr of the node %q is running is not advertised", nodeID)
+	}
+	client, err := NewClient(n.client.config.CopyConfig(node.HTTPAddr, node.TLSEnabled))
+	if err != nil {
+		return nil, err
+	}
+	var resp map[string]time.Duration
+	if _, err := client.query("/v1/client/fingerprint/durations", &resp, q); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // Node is used to deserialize a node entry.
 type Node struct {
 	ID                string
@@ -2,6 +2,9 @@ package api
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"sort"
 	"strings"
@@ -268,3 +271,44 @@ func TestNodes_Sort(t *testing.T) {
 		t.Fatalf("\n\n%#v\n\n%#v", nodes, expect)
 	}
 }
+
+func TestNodes_FingerprintDurations_QueryOptions(t *testing.T) {
+	var query url.Values
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/node/node1":
+			fmt.Fprintf(w, `{"ID":"node1","HTTPAddr":%q}`, strings.TrimPrefix(srv.URL, "http://"))
+		case "/v1/client/fingerprint/durations":
+			query = r.URL.Query()
+			fmt.Fprint(w, `{"cpu":1000000}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	conf := DefaultConfig()
+	conf.Address = srv.URL
+	c, err := NewClient(conf)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	q := &QueryOptions{Region: "east", AllowStale: true}
+	durations, err := c.Nodes().FingerprintDurations("node1", q)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if durations["cpu"] != time.Millisecond {
+		t.Fatalf("unexpected durations: %v", durations)
+	}
+
+	// The query options are passed on to the client
+	if query.Get("region") != "east" {
+		t.Fatalf("expected region east, got %q", query.Get("region"))
+	}
+	if _, ok := query["stale"]; !ok {
+		t.Fatalf("expected a stale query, got %v", query)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -162,6 +163,19 @@ type Client struct {
 	// garbageCollector is used to garbage collect terminal allocations present
 	// in the node automatically
 	garbageCollector *AllocGarbageCollector
+
+	// fingerprinters are the enabled fingerprinters and drivers by name. It
+	// is populated during initialization and used to refresh the fingerprint.
+	fingerprinters map[string]fingerprint.Fingerprint
+
+	// triggerNodeUpdateCh triggers an immediate check for node updates; see
+	// triggerNodeUpdate
+	triggerNodeUpdateCh chan struct{}
+
+	// fingerprintDurations is the duration of the last run of each
+	// fingerprinter and driver fingerprint by name
+	fingerprintDurations     map[string]time.Duration
+	fingerprintDurationsLock sync.RWMutex
 }
 
 // migrateAllocCtrl indicates whether migration is complete
@@ -213,20 +227,23 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 
 	// Create the client
 	c := &Client{
-		config:              cfg,
-		consulCatalog:       consulCatalog,
-		consulService:       consulService,
-		start:               time.Now(),
-		connPool:            nomad.NewPool(cfg.LogOutput, clientRPCCache, clientMaxStreams, tlsWrap),
-		logger:              logger,
-		allocs:              make(map[string]*AllocRunner),
-		blockedAllocations:  make(map[string]*structs.Allocation),
-		allocUpdates:        make(chan *structs.Allocation, 64),
-		shutdownCh:          make(chan struct{}),
-		migratingAllocs:     make(map[string]*migrateAllocCtrl),
-		servers:             newServerList(),
-		triggerDiscoveryCh:  make(chan struct{}),
-		serversDiscoveredCh: make(chan struct{}),
+		config:               cfg,
+		consulCatalog:        consulCatalog,
+		consulService:        consulService,
+		start:                time.Now(),
+		connPool:             nomad.NewPool(cfg.LogOutput, clientRPCCache, clientMaxStreams, tlsWrap),
+		logger:               logger,
+		allocs:               make(map[string]*AllocRunner),
+		blockedAllocations:   make(map[string]*structs.Allocation),
+		allocUpdates:         make(chan *structs.Allocation, 64),
+		shutdownCh:           make(chan struct{}),
+		migratingAllocs:      make(map[string]*migrateAllocCtrl),
+		servers:              newServerList(),
+		triggerDiscoveryCh:   make(chan struct{}),
+		serversDiscoveredCh:  make(chan struct{}),
+		fingerprinters:       make(map[string]fingerprint.Fingerprint),
+		fingerprintDurations: make(map[string]time.Duration),
+		triggerNodeUpdateCh:  make(chan struct{}, 1),
 	}
 
 	// Initialize the client
@@ -925,7 +942,7 @@ func (c *Client) fingerprint() error {
 		}
 
 		c.configLock.Lock()
-		applies, err := f.Fingerprint(c.config, c.config.Node)
+		applies, err := c.runFingerprint(name, f)
 		c.configLock.Unlock()
 		if err != nil {
 			return err
@@ -933,6 +950,7 @@ func (c *Client) fingerprint() error {
 		if applies {
 			applied = append(applied, name)
 		}
+		c.fingerprinters[name] = f
 		p, period := f.Periodic()
 		if p {
 			// TODO: If more periodic fingerprinters are added, then
@@ -955,7 +973,7 @@ func (c *Client) fingerprintPeriodic(name string, f fingerprint.Fingerprint, d t
 		select {
 		case <-time.After(d):
 			c.configLock.Lock()
-			if _, err := f.Fingerprint(c.config, c.config.Node); err != nil {
+			if _, err := c.runFingerprint(name, f); err != nil {
 				c.logger.Printf("[DEBUG] client: periodic fingerprinting for %v failed: %v", name, err)
 			}
 			c.configLock.Unlock()
@@ -965,6 +983,66 @@ func (c *Client) fingerprintPeriodic(name string, f fingerprint.Fingerprint, d t
 	}
 }
 
+// RefreshFingerprints re-runs every enabled fingerprinter and driver
+// fingerprint immediately and triggers an update of the node if its
+// attributes changed.
+func (c *Client) RefreshFingerprints() error {
+	names := make([]string, 0, len(c.fingerprinters))
+	for name := range c.fingerprinters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var mErr multierror.Error
+	c.configLock.Lock()
+	for _, name := range names {
+		if _, err := c.runFingerprint(name, c.fingerprinters[name]); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("fingerprinting %v failed: %v", name, err))
+		}
+	}
+	c.configLock.Unlock()
+
+	c.logger.Printf("[DEBUG] client: refreshed fingerprints %v", names)
+	c.triggerNodeUpdate()
+	return mErr.ErrorOrNil()
+}
+
+// runFingerprint runs the named fingerprinter against the node and records
+// how long it took. The caller must hold the configLock.
+func (c *Client) runFingerprint(name string, f fingerprint.Fingerprint) (bool, error) {
+	start := time.Now()
+	applies, err := f.Fingerprint(c.config, c.config.Node)
+	elapsed := time.Since(start)
+
+	c.fingerprintDurationsLock.Lock()
+	c.fingerprintDurations[name] = elapsed
+	c.fingerprintDurationsLock.Unlock()
+	return applies, err
+}
+
+// FingerprintDurations returns the duration of the last run of each
+// fingerprinter and driver fingerprint by name.
+func (c *Client) FingerprintDurations() map[string]time.Duration {
+	c.fingerprintDurationsLock.RLock()
+	defer c.fingerprintDurationsLock.RUnlock()
+
+	durations := make(map[string]time.Duration, len(c.fingerprintDurations))
+	for name, d := range c.fingerprintDurations {
+		durations[name] = d
+	}
+	return durations
+}
+
+// triggerNodeUpdate triggers the node update watcher to check for changes to
+// the node immediately.
+func (c *Client) triggerNodeUpdate() {
+	select {
+	case c.triggerNodeUpdateCh <- struct{}{}:
+	default:
+		// An update is already pending
+	}
+}
+
 // setupDrivers is used to find the available drivers
 func (c *Client) setupDrivers() error {
 	// Build the white/blacklists of drivers.
@@ -993,7 +1071,7 @@ func (c *Client) setupDrivers() error {
 			return err
 		}
 		c.configLock.Lock()
-		applies, err := d.Fingerprint(c.config, c.config.Node)
+		applies, err := c.runFingerprint(name, d)
 		c.configLock.Unlock()
 		if err != nil {
 			return err
@@ -1001,6 +1079,7 @@ func (c *Client) setupDrivers() error {
 		if applies {
 			avail = append(avail, name)
 		}
+		c.fingerprinters[name] = d
 
 		p, period := d.Periodic()
 		if p {
@@ -1535,29 +1614,48 @@ func (c *Client) watchNodeUpdates() {
 	c.logger.Printf("[DEBUG] client: periodically checking for node changes at duration %v", nodeUpdateRetryIntv)
 
 	// Initialize the hashes
+	c.updateAllocCountAttributes()
 	_, attrHash, metaHash := c.hasNodeChanged(0, 0)
 	var changed bool
 	for {
 		select {
 		case <-time.After(c.retryIntv(nodeUpdateRetryIntv)):
-			changed, attrHash, metaHash = c.hasNodeChanged(attrHash, metaHash)
-			if changed {
-				c.logger.Printf("[DEBUG] client: state changed, updating node.")
-
-				// Update the config copy.
-				c.configLock.Lock()
-				node := c.config.Node.Copy()
-				c.configCopy.Node = node
-				c.configLock.Unlock()
-
-				c.retryRegisterNode()
-			}
+		case <-c.triggerNodeUpdateCh:
 		case <-c.shutdownCh:
 			return
 		}
+
+		c.updateAllocCountAttributes()
+		changed, attrHash, metaHash = c.hasNodeChanged(attrHash, metaHash)
+		if changed {
+			c.logger.Printf("[DEBUG] client: state changed, updating node.")
+
+			// Update the config copy.
+			c.configLock.Lock()
+			node := c.config.Node.Copy()
+			c.configCopy.Node = node
+			c.configLock.Unlock()
+
+			c.retryRegisterNode()
+		}
 	}
 }
 
+// updateAllocCountAttributes refreshes the node attributes describing how
+// many allocations the client has relative to its configured maximum.
+func (c *Client) updateAllocCountAttributes() {
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+	setAllocCountAttributes(c.config.Node, c, c.config.GCMaxAllocs)
+}
+
+// setAllocCountAttributes sets the number of allocations counted by the
+// counter and the maximum number of allocations as node attributes.
+func setAllocCountAttributes(node *structs.Node, counter AllocCounter, max int) {
+	node.Attributes["unique.nomad.allocs.running"] = strconv.Itoa(counter.NumAllocs())
+	node.Attributes["nomad.allocs.max"] = strconv.Itoa(max)
+}
+
 // runAllocs is invoked when we get an updated set of allocations
 func (c *Client) runAllocs(update *allocUpdates) {
 	// Get the existing allocs
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -202,6 +203,85 @@ func TestClient_HasNodeChanged(t *testing.T) {
 	}
 }
 
+func TestClient_AllocCountAttributes(t *testing.T) {
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+	counter := &MockAllocCounter{}
+
+	var class string
+	for _, allocs := range []int{0, 3, 1} {
+		counter.allocs = allocs
+		setAllocCountAttributes(node, counter, 50)
+
+		// The running count must not change the computed class
+		if err := node.ComputeClass(); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if class != "" && node.ComputedClass != class {
+			t.Fatalf("Expected computed class %s; got %s", class, node.ComputedClass)
+		}
+		class = node.ComputedClass
+
+		if actual := node.Attributes["unique.nomad.allocs.running"]; actual != strconv.Itoa(allocs) {
+			t.Fatalf("Expected %d running allocs; got %s", allocs, actual)
+		}
+		if actual := node.Attributes["nomad.allocs.max"]; actual != "50" {
+			t.Fatalf("Expected max allocs 50; got %s", actual)
+		}
+	}
+}
+
+func TestClient_RefreshFingerprints(t *testing.T) {
+	c := testClient(t, nil)
+	defer c.Shutdown()
+
+	// Drain any pending node update trigger
+	select {
+	case <-c.triggerNodeUpdateCh:
+	default:
+	}
+
+	// Remove attributes set by a fingerprinter and a driver
+	c.configLock.Lock()
+	delete(c.config.Node.Attributes, "kernel.name")
+	delete(c.config.Node.Attributes, "driver.raw_exec")
+	c.configLock.Unlock()
+
+	if err := c.RefreshFingerprints(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	node := c.Node()
+	if node.Attributes["kernel.name"] == "" {
+		t.Fatalf("kernel.name should be set after refresh")
+	}
+	if node.Attributes["driver.raw_exec"] != "1" {
+		t.Fatalf("driver.raw_exec should be set after refresh")
+	}
+
+	select {
+	case <-c.triggerNodeUpdateCh:
+	default:
+		t.Fatalf("expected a node update to be triggered")
+	}
+}
+
+func TestClient_FingerprintDurations(t *testing.T) {
+	c := testClient(t, nil)
+	defer c.Shutdown()
+
+	durations := c.FingerprintDurations()
+	for name := range c.fingerprinters {
+		if _, ok := durations[name]; !ok {
+			t.Fatalf("expected a duration to be recorded for %q: %v", name, durations)
+		}
+	}
+	if len(durations) != len(c.fingerprinters) {
+		t.Fatalf("expected %d durations, got %d: %v", len(c.fingerprinters), len(durations), durations)
+	}
+}
+
 func TestClient_Fingerprint_InWhitelist(t *testing.T) {
 	c := testClient(t, func(c *config.Config) {
 		if c.Options == nil {
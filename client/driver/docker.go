@@ -2,10 +2,14 @@ package driver
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -109,6 +113,31 @@ const (
 	// dockerAuthHelperPrefix is the prefix to attach to the credential helper
 	// and should be found in the $PATH. Example: ${prefix-}${helper-name}
 	dockerAuthHelperPrefix = "docker-credential-"
+
+	// dockerMirrorAttrPrefix is the prefix of the node attributes recording
+	// whether the registry mirrors configured in Docker are reachable.
+	dockerMirrorAttrPrefix = "driver.docker.mirror."
+
+	// dockerMirrorDialTimeout is the length of time to wait when dialing a
+	// registry mirror before considering it unreachable.
+	dockerMirrorDialTimeout = 2 * time.Second
+
+	// dockerImageCacheAttrPrefix is the prefix of the node attributes
+	// describing the images cached by the Docker daemon.
+	dockerImageCacheAttrPrefix = "unique.driver.docker.image-cache."
+
+	// dockerRegistryHostsConfigOption is the key for the comma separated list
+	// of registry hosts whose certificates are checked against the trust
+	// store.
+	dockerRegistryHostsConfigOption = "docker.registry.hosts"
+
+	// dockerRegistryTrustAttrPrefix is the prefix of the node attributes
+	// recording whether the CAs of the configured registries are trusted.
+	dockerRegistryTrustAttrPrefix = "driver.docker.registry."
+
+	// dockerCertsDir is the directory holding the additional CAs Docker
+	// trusts for each registry host.
+	dockerCertsDir = "/etc/docker/certs.d"
 )
 
 type DockerDriver struct {
@@ -120,6 +149,19 @@ type DockerDriver struct {
 	// A tri-state boolean to know if the fingerprinting has happened and
 	// whether it has been successful
 	fingerprintSuccess *bool
+
+	// dialer is used to check if registry mirrors are reachable. It can be
+	// overridden for testing.
+	dialer func(network, address string, timeout time.Duration) (net.Conn, error)
+
+	// listImages is used to list the images cached by the Docker daemon. If
+	// nil the Docker client is used. It can be overridden for testing.
+	listImages func(opts docker.ListImagesOptions) ([]docker.APIImages, error)
+
+	// registryTrusted is used to check if the CA of a registry host is
+	// trusted. A non-nil error means the check could not be completed. It can
+	// be overridden for testing.
+	registryTrusted func(host string) (bool, error)
 }
 
 type DockerDriverAuth struct {
@@ -292,7 +334,11 @@ type DockerHandle struct {
 }
 
 func NewDockerDriver(ctx *DriverContext) Driver {
-	return &DockerDriver{DriverContext: *ctx}
+	return &DockerDriver{
+		DriverContext:   *ctx,
+		dialer:          net.DialTimeout,
+		registryTrusted: registryCATrusted,
+	}
 }
 
 func (d *DockerDriver) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
@@ -351,10 +397,147 @@ func (d *DockerDriver) Fingerprint(cfg *config.Config, node *structs.Node) (bool
 		}
 	}
 
+	// Detect which of the configured registry mirrors are reachable
+	if info, err := client.Info(); err != nil {
+		d.logger.Printf("[WARN] driver.docker: error discovering registry mirrors: %v", err)
+	} else if info.RegistryConfig != nil {
+		d.fingerprintMirrors(info.RegistryConfig.Mirrors, node)
+	}
+
+	// Record the size of the image cache
+	listImages := d.listImages
+	if listImages == nil {
+		listImages = client.ListImages
+	}
+	d.fingerprintImageCache(listImages, node)
+
+	// Detect which of the configured registries have a trusted CA
+	var registries []string
+	for _, host := range strings.Split(d.config.Read(dockerRegistryHostsConfigOption), ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			registries = append(registries, host)
+		}
+	}
+	d.fingerprintRegistryTrust(registries, node)
+
 	d.fingerprintSuccess = helper.BoolToPtr(true)
 	return true, nil
 }
 
+// fingerprintImageCache records the number and total size of the images
+// cached by the Docker daemon. The attributes are removed if the images can
+// not be listed.
+func (d *DockerDriver) fingerprintImageCache(listImages func(docker.ListImagesOptions) ([]docker.APIImages, error), node *structs.Node) {
+	images, err := listImages(docker.ListImagesOptions{})
+	if err != nil {
+		d.logger.Printf("[WARN] driver.docker: error listing images: %v", err)
+		delete(node.Attributes, dockerImageCacheAttrPrefix+"count")
+		delete(node.Attributes, dockerImageCacheAttrPrefix+"size-mb")
+		return
+	}
+
+	var size int64
+	for _, image := range images {
+		size += image.Size
+	}
+
+	node.Attributes[dockerImageCacheAttrPrefix+"count"] = strconv.Itoa(len(images))
+	node.Attributes[dockerImageCacheAttrPrefix+"size-mb"] = strconv.FormatInt(size/1024/1024, 10)
+}
+
+// fingerprintMirrors dials each registry mirror and records whether it is
+// reachable. Attributes for mirrors that are no longer configured are removed.
+func (d *DockerDriver) fingerprintMirrors(mirrors []string, node *structs.Node) {
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, dockerMirrorAttrPrefix) {
+			delete(node.Attributes, k)
+		}
+	}
+
+	for _, mirror := range mirrors {
+		u, err := url.Parse(mirror)
+		if err != nil || u.Host == "" {
+			d.logger.Printf("[WARN] driver.docker: unable to parse registry mirror %q", mirror)
+			continue
+		}
+
+		addr := u.Host
+		if u.Port() == "" {
+			port := "443"
+			if u.Scheme == "http" {
+				port = "80"
+			}
+			addr = net.JoinHostPort(u.Hostname(), port)
+		}
+
+		reachable := false
+		if conn, err := d.dialer("tcp", addr, dockerMirrorDialTimeout); err != nil {
+			d.logger.Printf("[DEBUG] driver.docker: registry mirror %q is unreachable: %v", mirror, err)
+		} else {
+			conn.Close()
+			reachable = true
+		}
+
+		node.Attributes[dockerMirrorAttrPrefix+u.Host+".reachable"] = strconv.FormatBool(reachable)
+	}
+}
+
+// fingerprintRegistryTrust records whether the CA of each registry host is
+// trusted. Attributes for registries that are no longer configured, or whose
+// trust could not be checked, are removed.
+func (d *DockerDriver) fingerprintRegistryTrust(hosts []string, node *structs.Node) {
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, dockerRegistryTrustAttrPrefix) && strings.HasSuffix(k, ".trusted") {
+			delete(node.Attributes, k)
+		}
+	}
+
+	for _, host := range hosts {
+		trusted, err := d.registryTrusted(host)
+		if err != nil {
+			d.logger.Printf("[WARN] driver.docker: unable to check the CA of registry %q: %v", host, err)
+			continue
+		}
+		node.Attributes[dockerRegistryTrustAttrPrefix+host+".trusted"] = strconv.FormatBool(trusted)
+	}
+}
+
+// registryCATrusted does a TLS handshake with the registry host and returns
+// whether its certificate chains to the system trust store or to a CA
+// installed for the host in Docker's certs.d directory. An error is returned
+// if the handshake fails for any reason other than verification.
+func registryCATrusted(host string) (bool, error) {
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		return false, err
+	}
+
+	// Docker also trusts the CAs installed for the registry host
+	certs, _ := filepath.Glob(filepath.Join(dockerCertsDir, host, "*.crt"))
+	for _, cert := range certs {
+		if pem, err := ioutil.ReadFile(cert); err == nil {
+			roots.AppendCertsFromPEM(pem)
+		}
+	}
+
+	addr := host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		addr = net.JoinHostPort(host, "443")
+	}
+
+	dialer := &net.Dialer{Timeout: dockerMirrorDialTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{RootCAs: roots})
+	if err != nil {
+		switch err.(type) {
+		case x509.UnknownAuthorityError, x509.CertificateInvalidError, x509.HostnameError:
+			return false, nil
+		}
+		return false, err
+	}
+	conn.Close()
+	return true, nil
+}
+
 // Validate is used to validate the driver configuration
 func (d *DockerDriver) Validate(config map[string]interface{}) error {
 	fd := &fields.FieldData{
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math/rand"
+	"net"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -212,6 +213,134 @@ func TestDockerDriver_Fingerprint_Bridge(t *testing.T) {
 	t.Logf("docker bridge ip: %q", conf.Node.Attributes["driver.docker.bridge_ip"])
 }
 
+// TestDockerDriver_Fingerprint_Mirrors asserts that the reachability of each
+// registry mirror is recorded as a node attribute.
+func TestDockerDriver_Fingerprint_Mirrors(t *testing.T) {
+	conf := testConfig()
+	conf.Node = mock.Node()
+	dd := NewDockerDriver(NewDriverContext("", "", conf, conf.Node, testLogger(), nil)).(*DockerDriver)
+
+	var dialed []string
+	dd.dialer = func(network, address string, timeout time.Duration) (net.Conn, error) {
+		dialed = append(dialed, address)
+		if strings.HasPrefix(address, "up.example.com") {
+			server, client := net.Pipe()
+			server.Close()
+			return client, nil
+		}
+		return nil, fmt.Errorf("connection refused")
+	}
+
+	conf.Node.Attributes["driver.docker.mirror.stale.example.com.reachable"] = "true"
+	mirrors := []string{
+		"https://up.example.com",
+		"http://down.example.com:5000",
+	}
+	dd.fingerprintMirrors(mirrors, conf.Node)
+
+	expected := []string{"up.example.com:443", "down.example.com:5000"}
+	if !reflect.DeepEqual(dialed, expected) {
+		t.Fatalf("expected dials to %v; got %v", expected, dialed)
+	}
+
+	attrs := map[string]string{
+		"driver.docker.mirror.up.example.com.reachable":        "true",
+		"driver.docker.mirror.down.example.com:5000.reachable": "false",
+	}
+	for k, v := range attrs {
+		if found := conf.Node.Attributes[k]; found != v {
+			t.Fatalf("expected %q to be %q but found: %q", k, v, found)
+		}
+	}
+	if _, ok := conf.Node.Attributes["driver.docker.mirror.stale.example.com.reachable"]; ok {
+		t.Fatalf("expected stale mirror attribute to be removed")
+	}
+}
+
+// TestDockerDriver_Fingerprint_ImageCache asserts that the number and total
+// size of the cached images are recorded as node attributes.
+func TestDockerDriver_Fingerprint_ImageCache(t *testing.T) {
+	conf := testConfig()
+	conf.Node = mock.Node()
+	dd := NewDockerDriver(NewDriverContext("", "", conf, conf.Node, testLogger(), nil)).(*DockerDriver)
+
+	images := []docker.APIImages{
+		{ID: "sha256:1", Size: 100 * 1024 * 1024},
+		{ID: "sha256:2", Size: 50 * 1024 * 1024},
+		{ID: "sha256:3", Size: 512 * 1024},
+	}
+	dd.fingerprintImageCache(func(docker.ListImagesOptions) ([]docker.APIImages, error) {
+		return images, nil
+	}, conf.Node)
+
+	attrs := map[string]string{
+		"unique.driver.docker.image-cache.count":   "3",
+		"unique.driver.docker.image-cache.size-mb": "150",
+	}
+	for k, v := range attrs {
+		if found := conf.Node.Attributes[k]; found != v {
+			t.Fatalf("expected %q to be %q but found: %q", k, v, found)
+		}
+	}
+
+	// A failure to list the images removes the attributes
+	dd.fingerprintImageCache(func(docker.ListImagesOptions) ([]docker.APIImages, error) {
+		return nil, fmt.Errorf("daemon unavailable")
+	}, conf.Node)
+	for k := range attrs {
+		if _, ok := conf.Node.Attributes[k]; ok {
+			t.Fatalf("expected %q to be removed", k)
+		}
+	}
+}
+
+// TestDockerDriver_Fingerprint_RegistryTrust asserts that whether the CA of
+// each configured registry is trusted is recorded as a node attribute.
+func TestDockerDriver_Fingerprint_RegistryTrust(t *testing.T) {
+	conf := testConfig()
+	conf.Node = mock.Node()
+	dd := NewDockerDriver(NewDriverContext("", "", conf, conf.Node, testLogger(), nil)).(*DockerDriver)
+
+	dd.registryTrusted = func(host string) (bool, error) {
+		switch host {
+		case "trusted.example.com":
+			return true, nil
+		case "untrusted.example.com:5000":
+			return false, nil
+		}
+		return false, fmt.Errorf("connection refused")
+	}
+
+	conf.Node.Attributes["driver.docker.registry.stale.example.com.trusted"] = "true"
+	conf.Node.Attributes["driver.docker.registry.down.example.com.trusted"] = "true"
+	hosts := []string{
+		"trusted.example.com",
+		"untrusted.example.com:5000",
+		"down.example.com",
+	}
+	dd.fingerprintRegistryTrust(hosts, conf.Node)
+
+	attrs := map[string]string{
+		"driver.docker.registry.trusted.example.com.trusted":        "true",
+		"driver.docker.registry.untrusted.example.com:5000.trusted": "false",
+	}
+	for k, v := range attrs {
+		if found := conf.Node.Attributes[k]; found != v {
+			t.Fatalf("expected %q to be %q but found: %q", k, v, found)
+		}
+	}
+
+	// Registries no longer configured or that can't be checked are removed
+	for _, k := range []string{
+		"driver.docker.registry.stale.example.com.trusted",
+		"driver.docker.registry.down.example.com.trusted",
+	} {
+		if _, ok := conf.Node.Attributes[k]; ok {
+			t.Fatalf("expected %q to be removed", k)
+		}
+	}
+}
+
 func TestDockerDriver_StartOpen_Wait(t *testing.T) {
 	if !testutil.DockerIsConnected(t) {
 		t.SkipNow()
@@ -0,0 +1,91 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// blockAttrPrefix is the prefix of the attributes describing block
+	// devices.
+	blockAttrPrefix = "storage.block."
+)
+
+// BlockSchedulerFingerprint is used to fingerprint the active IO scheduler of
+// each block device.
+type BlockSchedulerFingerprint struct {
+	StaticFingerprinter
+	logger *log.Logger
+
+	// sysfsDir is the sysfs directory of the block devices
+	sysfsDir string
+}
+
+// NewBlockSchedulerFingerprint is used to create a block device IO scheduler
+// fingerprint
+func NewBlockSchedulerFingerprint(logger *log.Logger) Fingerprint {
+	f := &BlockSchedulerFingerprint{
+		logger:   logger,
+		sysfsDir: "/sys/block",
+	}
+	return f
+}
+
+func (f *BlockSchedulerFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	// Clear any attributes from the previous fingerprint
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, blockAttrPrefix) && strings.HasSuffix(k, ".scheduler") {
+			delete(node.Attributes, k)
+		}
+	}
+
+	devices, err := ioutil.ReadDir(f.sysfsDir)
+	if err != nil {
+		f.logger.Printf("[DEBUG] fingerprint.block_scheduler: Error reading %s: %v", f.sysfsDir, err)
+		return false, nil
+	}
+
+	applies := false
+	for _, device := range devices {
+		// Loop and RAM disks are not backed by a device worth steering on
+		name := device.Name()
+		if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") {
+			continue
+		}
+
+		scheduler := parseBlockScheduler(readSysfsValue(filepath.Join(f.sysfsDir, name, "queue", "scheduler")))
+		if scheduler == "" {
+			continue
+		}
+		node.Attributes[blockAttrPrefix+name+".scheduler"] = scheduler
+		applies = true
+	}
+
+	return applies, nil
+}
+
+// parseBlockScheduler returns the active scheduler from the contents of a
+// queue/scheduler sysfs file, which lists the available schedulers with the
+// active one in brackets, such as:
+//
+//	mq-deadline kyber [bfq] none
+//
+// Devices without a choice of scheduler list only the one in use, without
+// brackets.
+func parseBlockScheduler(s string) string {
+	fields := strings.Fields(s)
+	for _, field := range fields {
+		if strings.HasPrefix(field, "[") && strings.HasSuffix(field, "]") {
+			return strings.Trim(field, "[]")
+		}
+	}
+	if len(fields) == 1 {
+		return fields[0]
+	}
+	return ""
+}
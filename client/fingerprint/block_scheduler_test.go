@@ -0,0 +1,74 @@
+package fingerprint
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestBlockSchedulerFingerprint(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{
+		"sda/queue/scheduler":     "mq-deadline kyber [bfq] none\n",
+		"nvme0n1/queue/scheduler": "[none] mq-deadline\n",
+		"xvda/queue/scheduler":    "none\n",
+		"loop0/queue/scheduler":   "[none] mq-deadline\n",
+		"dm-0/size":               "1024\n",
+	})
+	defer os.RemoveAll(dir)
+
+	f := &BlockSchedulerFingerprint{
+		logger:   testLogger(),
+		sysfsDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"storage.block.sdb.scheduler": "none",
+		},
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "storage.block.sda.scheduler", "bfq")
+	assertNodeAttributeEquals(t, node, "storage.block.nvme0n1.scheduler", "none")
+	assertNodeAttributeEquals(t, node, "storage.block.xvda.scheduler", "none")
+	for _, k := range []string{"storage.block.sdb.scheduler", "storage.block.loop0.scheduler", "storage.block.dm-0.scheduler"} {
+		if a, ok := node.Attributes[k]; ok {
+			t.Fatalf("unexpected attribute %s found, %s", k, a)
+		}
+	}
+}
+
+func TestBlockSchedulerFingerprint_Absent(t *testing.T) {
+	f := &BlockSchedulerFingerprint{
+		logger:   testLogger(),
+		sysfsDir: "/nonexistent/sys/block",
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+}
+
+func TestParseBlockScheduler(t *testing.T) {
+	cases := map[string]string{
+		"[mq-deadline] kyber bfq none": "mq-deadline",
+		"mq-deadline kyber [bfq] none": "bfq",
+		"[none] mq-deadline":           "none",
+		"none":                         "none",
+		"noop deadline cfq":            "",
+		"":                             "",
+	}
+	for in, expected := range cases {
+		if out := parseBlockScheduler(in); out != expected {
+			t.Fatalf("%q: expected %q, got %q", in, expected, out)
+		}
+	}
+}
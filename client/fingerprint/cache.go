@@ -0,0 +1,113 @@
+package fingerprint
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	client "github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// cacheAttrPrefix is the prefix of the attributes recording whether the
+	// configured cache endpoints are reachable.
+	cacheAttrPrefix = "cache."
+
+	// cacheEndpointsOption is the client option listing the cache endpoints
+	// to fingerprint as comma separated name=url pairs.
+	cacheEndpointsOption = "fingerprint.cache.endpoints"
+
+	// cacheTimeout is the timeout of the request made to each endpoint
+	cacheTimeout = 2 * time.Second
+
+	// cacheInterval is the interval at which the cache endpoints are
+	// fingerprinted as they may come and go independently of the client.
+	cacheInterval = time.Minute
+)
+
+// CacheFingerprint is used to fingerprint whether the configured object store
+// or cache endpoints, such as a local MinIO, are reachable from the client.
+type CacheFingerprint struct {
+	logger *log.Logger
+
+	// client is the HTTP client the endpoints are probed with
+	client *http.Client
+}
+
+// NewCacheFingerprint is used to create a cache endpoint fingerprint
+func NewCacheFingerprint(logger *log.Logger) Fingerprint {
+	f := &CacheFingerprint{
+		logger: logger,
+		client: &http.Client{
+			Timeout:   cacheTimeout,
+			Transport: cleanhttp.DefaultTransport(),
+		},
+	}
+	return f
+}
+
+func (f *CacheFingerprint) Fingerprint(cfg *client.Config, node *structs.Node) (bool, error) {
+	// Clear any attributes from the previous fingerprint
+	uniquePrefix := structs.UniqueNamespace(cacheAttrPrefix)
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, cacheAttrPrefix) || strings.HasPrefix(k, uniquePrefix) {
+			delete(node.Attributes, k)
+		}
+	}
+
+	endpoints, err := parseCacheEndpoints(cfg.Read(cacheEndpointsOption))
+	if err != nil {
+		return false, err
+	}
+	if len(endpoints) == 0 {
+		return false, nil
+	}
+
+	for name, url := range endpoints {
+		prefix := cacheAttrPrefix + name
+		latency, err := f.probe(url)
+		if err != nil {
+			f.logger.Printf("[DEBUG] fingerprint.cache: endpoint %q at %s is unreachable: %v", name, url, err)
+			node.Attributes[prefix+".reachable"] = "false"
+			continue
+		}
+
+		node.Attributes[prefix+".reachable"] = "true"
+		node.Attributes[structs.UniqueNamespace(prefix+".latency-ms")] = strconv.FormatInt(int64(latency/time.Millisecond), 10)
+	}
+	return true, nil
+}
+
+// probe makes a HEAD request to the URL and returns how long the response
+// took. Any response other than a server error means the endpoint is
+// reachable as object stores commonly reject unauthenticated requests.
+func (f *CacheFingerprint) probe(url string) (time.Duration, error) {
+	start := time.Now()
+	resp, err := f.client.Head(url)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	latency := time.Since(start)
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return 0, fmt.Errorf("unexpected response code %d", resp.StatusCode)
+	}
+	return latency, nil
+}
+
+// parseCacheEndpoints parses a comma separated list of name=url pairs into a
+// map of the URLs by name.
+func parseCacheEndpoints(value string) (map[string]string, error) {
+	return parseNamedValues(cacheEndpointsOption, value, "url", nil)
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *CacheFingerprint) Periodic() (bool, time.Duration) {
+	return true, cacheInterval
+}
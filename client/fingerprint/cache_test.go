@@ -0,0 +1,112 @@
+package fingerprint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestCacheFingerprint(t *testing.T) {
+	// Object stores reject unauthenticated requests but are still reachable
+	var method string
+	minio := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer minio.Close()
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	f := &CacheFingerprint{
+		logger: testLogger(),
+		client: &http.Client{Timeout: cacheTimeout},
+	}
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"cache.stale.reachable":         "true",
+			"unique.cache.stale.latency-ms": "3",
+		},
+	}
+	cfg := &config.Config{
+		Options: map[string]string{
+			"fingerprint.cache.endpoints": "minio=" + minio.URL + ",broken=" + broken.URL + ",down=" + down.URL,
+		},
+	}
+
+	ok, err := f.Fingerprint(cfg, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("should apply")
+	}
+	if method != http.MethodHead {
+		t.Fatalf("expected a HEAD request, got %q", method)
+	}
+
+	assertNodeAttributeEquals(t, node, "cache.minio.reachable", "true")
+	latency, err := strconv.Atoi(node.Attributes["unique.cache.minio.latency-ms"])
+	if err != nil || latency < 0 {
+		t.Fatalf("invalid latency %q", node.Attributes["unique.cache.minio.latency-ms"])
+	}
+	if a, ok := node.Attributes["cache.minio.latency-ms"]; ok {
+		t.Fatalf("unexpected attribute cache.minio.latency-ms found, %s", a)
+	}
+	for _, name := range []string{"broken", "down"} {
+		assertNodeAttributeEquals(t, node, "cache."+name+".reachable", "false")
+		if _, ok := node.Attributes["unique.cache."+name+".latency-ms"]; ok {
+			t.Fatalf("unexpected latency for unreachable endpoint %q", name)
+		}
+	}
+	if _, ok := node.Attributes["cache.stale.reachable"]; ok {
+		t.Fatalf("expected stale cache attribute to be removed")
+	}
+	if _, ok := node.Attributes["unique.cache.stale.latency-ms"]; ok {
+		t.Fatalf("expected stale cache latency to be removed")
+	}
+
+	// The fingerprinter doesn't apply without any configured endpoints
+	ok, err = f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 0 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
+
+func TestCacheFingerprint_ParseEndpoints(t *testing.T) {
+	endpoints, err := parseCacheEndpoints(" minio=http://127.0.0.1:9000 , s3-local=http://10.0.0.1/bucket?x=1")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(endpoints) != 2 ||
+		endpoints["minio"] != "http://127.0.0.1:9000" ||
+		endpoints["s3-local"] != "http://10.0.0.1/bucket?x=1" {
+		t.Fatalf("unexpected endpoints: %v", endpoints)
+	}
+
+	for _, value := range []string{
+		"http://127.0.0.1:9000",
+		"minio=",
+		"mi.nio=http://127.0.0.1:9000",
+		"minio=http://a,minio=http://b",
+	} {
+		if _, err := parseCacheEndpoints(value); err == nil {
+			t.Fatalf("expected error parsing %q", value)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package fingerprint
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// capabilitiesAttrPrefix is the prefix of the attributes recording the
+	// capabilities of the client process.
+	capabilitiesAttrPrefix = "host.capabilities."
+
+	// selfStatusFile holds the status, including the effective capabilities,
+	// of the process reading it.
+	selfStatusFile = "self/status"
+)
+
+// fingerprintedCapabilities maps the attribute name of the capabilities that
+// are fingerprinted to their bit in the capability mask, as defined in
+// linux/capability.h.
+var fingerprintedCapabilities = map[string]uint{
+	"net_admin": 12,
+	"sys_admin": 21,
+}
+
+// CapabilitiesFingerprint is used to fingerprint whether the client process
+// holds the capabilities drivers rely on, such as CAP_NET_ADMIN.
+type CapabilitiesFingerprint struct {
+	StaticFingerprinter
+	logger *log.Logger
+
+	// procDir is the directory the proc filesystem is mounted at
+	procDir string
+}
+
+// NewCapabilitiesFingerprint is used to create a process capabilities
+// fingerprint
+func NewCapabilitiesFingerprint(logger *log.Logger) Fingerprint {
+	f := &CapabilitiesFingerprint{
+		logger:  logger,
+		procDir: "/proc",
+	}
+	return f
+}
+
+func (f *CapabilitiesFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	mask, err := f.effectiveCapabilities()
+	if err != nil {
+		f.logger.Printf("[DEBUG] fingerprint.capabilities: could not read effective capabilities: %v", err)
+		for name := range fingerprintedCapabilities {
+			delete(node.Attributes, capabilitiesAttrPrefix+name)
+		}
+		return false, nil
+	}
+
+	for name, bit := range fingerprintedCapabilities {
+		node.Attributes[capabilitiesAttrPrefix+name] = strconv.FormatBool(mask&(1<<bit) != 0)
+	}
+	return true, nil
+}
+
+// effectiveCapabilities returns the effective capability mask of the client
+// process from the CapEff line of its status file, which looks like:
+//
+//	CapEff:	0000003fffffffff
+func (f *CapabilitiesFingerprint) effectiveCapabilities() (uint64, error) {
+	file, err := os.Open(filepath.Join(f.procDir, selfStatusFile))
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "CapEff:" {
+			return strconv.ParseUint(fields[1], 16, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("no CapEff line in %s", file.Name())
+}
@@ -0,0 +1,83 @@
+package fingerprint
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// testProcStatus returns the contents of a /proc/self/status fixture with the
+// given effective capability mask.
+func testProcStatus(capEff string) string {
+	return "Name:\tnomad\nUmask:\t0022\nState:\tS (sleeping)\n" +
+		"CapInh:\t0000000000000000\nCapPrm:\t" + capEff + "\nCapEff:\t" + capEff + "\n" +
+		"CapBnd:\t0000003fffffffff\nCapAmb:\t0000000000000000\n"
+}
+
+func TestCapabilitiesFingerprint(t *testing.T) {
+	cases := []struct {
+		name     string
+		capEff   string
+		netAdmin string
+		sysAdmin string
+	}{
+		{"root", "0000003fffffffff", "true", "true"},
+		{"unprivileged", "0000000000000000", "false", "false"},
+		{"net_admin only", "0000000000001000", "true", "false"},
+		{"sys_admin only", "0000000000200000", "false", "true"},
+		{"docker default", "00000000a80425fb", "false", "false"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := writeSysfsTree(t, map[string]string{
+				"self/status": testProcStatus(c.capEff),
+			})
+			defer os.RemoveAll(dir)
+
+			f := &CapabilitiesFingerprint{
+				logger:  testLogger(),
+				procDir: dir,
+			}
+			node := &structs.Node{
+				Attributes: make(map[string]string),
+			}
+
+			assertFingerprintOK(t, f, node)
+			assertNodeAttributeEquals(t, node, "host.capabilities.net_admin", c.netAdmin)
+			assertNodeAttributeEquals(t, node, "host.capabilities.sys_admin", c.sysAdmin)
+		})
+	}
+}
+
+func TestCapabilitiesFingerprint_Unsupported(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{
+		"self/status": "Name:\tnomad\n",
+	})
+	defer os.RemoveAll(dir)
+
+	for _, procDir := range []string{dir, "/nonexistent/proc"} {
+		f := &CapabilitiesFingerprint{
+			logger:  testLogger(),
+			procDir: procDir,
+		}
+		node := &structs.Node{
+			Attributes: map[string]string{
+				"host.capabilities.net_admin": "true",
+			},
+		}
+
+		ok, err := f.Fingerprint(&config.Config{}, node)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if ok {
+			t.Fatalf("should not apply")
+		}
+		if len(node.Attributes) != 0 {
+			t.Fatalf("unexpected attributes: %v", node.Attributes)
+		}
+	}
+}
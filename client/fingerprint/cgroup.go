@@ -13,12 +13,23 @@ const (
 	cgroupAvailable   = "available"
 	cgroupUnavailable = "unavailable"
 	interval          = 15
+
+	// cgroupPidsMaxAttr is the attribute holding the limit enforced by the
+	// pids controller.
+	cgroupPidsMaxAttr = "os.cgroups.pids.max"
+
+	// cgroupSwapAccountingAttr is the attribute recording whether the memory
+	// controller accounts for and limits the swap used by cgroups.
+	cgroupSwapAccountingAttr = "os.cgroups.memory.swap-accounting"
 )
 
 type CGroupFingerprint struct {
 	logger             *log.Logger
 	lastState          string
 	mountPointDetector MountPointDetector
+
+	// procSelfCgroup is the file listing the cgroups of the client process
+	procSelfCgroup string
 }
 
 // An interface to isolate calls to the cgroup library
@@ -43,6 +54,7 @@ func NewCGroupFingerprint(logger *log.Logger) Fingerprint {
 		logger:             logger,
 		lastState:          cgroupUnavailable,
 		mountPointDetector: &DefaultMountPointDetector{},
+		procSelfCgroup:     "/proc/self/cgroup",
 	}
 	return f
 }
@@ -51,6 +63,8 @@ func NewCGroupFingerprint(logger *log.Logger) Fingerprint {
 // have been set in a previous fingerprint run.
 func (f *CGroupFingerprint) clearCGroupAttributes(n *structs.Node) {
 	delete(n.Attributes, "unique.cgroup.mountpoint")
+	delete(n.Attributes, cgroupPidsMaxAttr)
+	delete(n.Attributes, cgroupSwapAccountingAttr)
 }
 
 // Periodic determines the interval at which the periodic fingerprinter will run.
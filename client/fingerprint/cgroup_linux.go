@@ -4,6 +4,11 @@ package fingerprint
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	client "github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -48,6 +53,8 @@ func (f *CGroupFingerprint) Fingerprint(cfg *client.Config, node *structs.Node)
 	}
 
 	node.Attributes["unique.cgroup.mountpoint"] = mount
+	f.fingerprintPidsMax(mount, node)
+	f.fingerprintSwapAccounting(mount, node)
 
 	if f.lastState == cgroupUnavailable {
 		f.logger.Printf("[INFO] fingerprint.cgroups: cgroups are available")
@@ -55,3 +62,62 @@ func (f *CGroupFingerprint) Fingerprint(cfg *client.Config, node *structs.Node)
 	f.lastState = cgroupAvailable
 	return true, nil
 }
+
+// fingerprintPidsMax records the process limit the pids controller enforces
+// on the cgroup of the client, as the root cgroup has no limit. Both the v1
+// hierarchy, where the controller has its own mount, and the v2 unified
+// hierarchy are checked. The attribute is removed if the controller isn't
+// mounted or the client is in the root cgroup.
+func (f *CGroupFingerprint) fingerprintPidsMax(mount string, node *structs.Node) {
+	content, err := ioutil.ReadFile(f.procSelfCgroup)
+	if err != nil {
+		f.logger.Printf("[DEBUG] fingerprint.cgroups: Error reading %s: %v", f.procSelfCgroup, err)
+		delete(node.Attributes, cgroupPidsMaxAttr)
+		return
+	}
+
+	var paths []string
+	groups := parseProcCgroup(string(content))
+	if group, ok := groups["pids"]; ok {
+		paths = append(paths, filepath.Join(mount, "pids", group, "pids.max"))
+	}
+	if group, ok := groups[""]; ok {
+		paths = append(paths, filepath.Join(mount, group, "pids.max"))
+	}
+
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		node.Attributes[cgroupPidsMaxAttr] = strings.TrimSpace(string(content))
+		return
+	}
+
+	delete(node.Attributes, cgroupPidsMaxAttr)
+}
+
+// fingerprintSwapAccounting records whether swap accounting is enabled, which
+// is the case if the memory controller exposes the swap limit of cgroups. On
+// the v1 hierarchy this is the memory.memsw.limit_in_bytes file of the memory
+// mount. On the v2 unified hierarchy it is memory.swap.max, which only exists
+// in the cgroups below the root, so the cgroups directly below it are checked.
+func (f *CGroupFingerprint) fingerprintSwapAccounting(mount string, node *structs.Node) {
+	paths := []string{
+		filepath.Join(mount, "memory", "memory.memsw.limit_in_bytes"),
+		filepath.Join(mount, "memory.swap.max"),
+	}
+	if matches, err := filepath.Glob(filepath.Join(mount, "*", "memory.swap.max")); err == nil {
+		paths = append(paths, matches...)
+	}
+
+	enabled := false
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			enabled = true
+			break
+		}
+	}
+	node.Attributes[cgroupSwapAccountingAttr] = strconv.FormatBool(enabled)
+}
@@ -4,6 +4,8 @@ package fingerprint
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/nomad/client/config"
@@ -38,6 +40,15 @@ func (m *MountPointDetectorEmptyMountPoint) MountPoint() (string, error) {
 	return "", nil
 }
 
+// A fake mount point detector that returns the configured path
+type MountPointDetectorPath struct {
+	path string
+}
+
+func (m *MountPointDetectorPath) MountPoint() (string, error) {
+	return m.path, nil
+}
+
 func TestCGroupFingerprint(t *testing.T) {
 	f := &CGroupFingerprint{
 		logger:             testLogger(),
@@ -100,3 +111,143 @@ func TestCGroupFingerprint(t *testing.T) {
 		t.Fatalf("unexpected attribute found, %s", a)
 	}
 }
+
+func TestCGroupFingerprint_PidsMax(t *testing.T) {
+	cases := []struct {
+		name   string
+		cgroup string
+		files  map[string]string
+		want   string
+	}{
+		{
+			name:   "v1",
+			cgroup: "9:name=systemd:/system.slice/nomad.service\n8:pids:/system.slice/nomad.service\n",
+			files: map[string]string{
+				"pids/system.slice/nomad.service/pids.max": "4096\n",
+			},
+			want: "4096",
+		},
+		{
+			name:   "v2",
+			cgroup: "0::/system.slice/nomad.service\n",
+			files: map[string]string{
+				"system.slice/nomad.service/pids.max": "max\n",
+			},
+			want: "max",
+		},
+		{
+			name:   "root cgroup",
+			cgroup: "8:pids:/\n0::/\n",
+			files: map[string]string{
+				"pids/system.slice/pids.max": "4096\n",
+			},
+		},
+		{
+			name:   "unmounted",
+			cgroup: "9:name=systemd:/system.slice/nomad.service\n",
+			files: map[string]string{
+				"memory/memory.limit_in_bytes": "9223372036854771712\n",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		dir := writeSysfsTree(t, c.files)
+		defer os.RemoveAll(dir)
+
+		proc := writeSysfsTree(t, map[string]string{"cgroup": c.cgroup})
+		defer os.RemoveAll(proc)
+
+		f := &CGroupFingerprint{
+			logger:             testLogger(),
+			lastState:          cgroupUnavailable,
+			mountPointDetector: &MountPointDetectorPath{path: dir},
+			procSelfCgroup:     filepath.Join(proc, "cgroup"),
+		}
+		node := &structs.Node{
+			Attributes: map[string]string{
+				cgroupPidsMaxAttr: "stale",
+			},
+		}
+
+		ok, err := f.Fingerprint(&config.Config{}, node)
+		if err != nil {
+			t.Fatalf("%s: unexpected error, %s", c.name, err)
+		}
+		if !ok {
+			t.Fatalf("%s: should apply", c.name)
+		}
+
+		if c.want == "" {
+			if a, ok := node.Attributes[cgroupPidsMaxAttr]; ok {
+				t.Fatalf("%s: unexpected attribute found, %s", c.name, a)
+			}
+			continue
+		}
+		assertNodeAttributeEquals(t, node, cgroupPidsMaxAttr, c.want)
+	}
+}
+
+func TestCGroupFingerprint_SwapAccounting(t *testing.T) {
+	cases := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name: "v1 enabled",
+			files: map[string]string{
+				"memory/memory.limit_in_bytes":       "9223372036854771712\n",
+				"memory/memory.memsw.limit_in_bytes": "9223372036854771712\n",
+			},
+			want: "true",
+		},
+		{
+			name: "v1 disabled",
+			files: map[string]string{
+				"memory/memory.limit_in_bytes": "9223372036854771712\n",
+			},
+			want: "false",
+		},
+		{
+			name: "v2 enabled",
+			files: map[string]string{
+				"cgroup.controllers":           "cpu io memory pids\n",
+				"system.slice/memory.max":      "max\n",
+				"system.slice/memory.swap.max": "max\n",
+			},
+			want: "true",
+		},
+		{
+			name: "v2 disabled",
+			files: map[string]string{
+				"cgroup.controllers":      "cpu io memory pids\n",
+				"system.slice/memory.max": "max\n",
+			},
+			want: "false",
+		},
+	}
+
+	for _, c := range cases {
+		dir := writeSysfsTree(t, c.files)
+		defer os.RemoveAll(dir)
+
+		f := &CGroupFingerprint{
+			logger:             testLogger(),
+			lastState:          cgroupUnavailable,
+			mountPointDetector: &MountPointDetectorPath{path: dir},
+		}
+		node := &structs.Node{
+			Attributes: make(map[string]string),
+		}
+
+		ok, err := f.Fingerprint(&config.Config{}, node)
+		if err != nil {
+			t.Fatalf("%s: unexpected error, %s", c.name, err)
+		}
+		if !ok {
+			t.Fatalf("%s: should apply", c.name)
+		}
+		assertNodeAttributeEquals(t, node, cgroupSwapAccountingAttr, c.want)
+	}
+}
@@ -0,0 +1,47 @@
+package fingerprint
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	clocksourceAttr = "time.clocksource"
+
+	// currentClocksourceFile holds the name of the clock source in use, such
+	// as tsc, hpet or kvm-clock.
+	currentClocksourceFile = "clocksource0/current_clocksource"
+)
+
+// ClocksourceFingerprint is used to fingerprint the clock source the kernel
+// keeps time with, as clock sources other than the TSC are slower to read.
+type ClocksourceFingerprint struct {
+	StaticFingerprinter
+	logger *log.Logger
+
+	// sysfsDir is the sysfs directory of the clock sources
+	sysfsDir string
+}
+
+// NewClocksourceFingerprint is used to create a clock source fingerprint
+func NewClocksourceFingerprint(logger *log.Logger) Fingerprint {
+	f := &ClocksourceFingerprint{
+		logger:   logger,
+		sysfsDir: "/sys/devices/system/clocksource",
+	}
+	return f
+}
+
+func (f *ClocksourceFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	clocksource := readSysfsValue(filepath.Join(f.sysfsDir, currentClocksourceFile))
+	if clocksource == "" {
+		delete(node.Attributes, clocksourceAttr)
+		return false, nil
+	}
+
+	node.Attributes[clocksourceAttr] = clocksource
+	return true, nil
+}
@@ -0,0 +1,55 @@
+package fingerprint
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestClocksourceFingerprint(t *testing.T) {
+	for _, clocksource := range []string{"tsc", "hpet", "kvm-clock"} {
+		t.Run(clocksource, func(t *testing.T) {
+			dir := writeSysfsTree(t, map[string]string{
+				"clocksource0/current_clocksource":   clocksource + "\n",
+				"clocksource0/available_clocksource": "tsc hpet acpi_pm \n",
+			})
+			defer os.RemoveAll(dir)
+
+			f := &ClocksourceFingerprint{
+				logger:   testLogger(),
+				sysfsDir: dir,
+			}
+			node := &structs.Node{
+				Attributes: make(map[string]string),
+			}
+
+			assertFingerprintOK(t, f, node)
+			assertNodeAttributeEquals(t, node, "time.clocksource", clocksource)
+		})
+	}
+}
+
+func TestClocksourceFingerprint_Absent(t *testing.T) {
+	f := &ClocksourceFingerprint{
+		logger:   testLogger(),
+		sysfsDir: "/nonexistent/sys/devices/system/clocksource",
+	}
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"time.clocksource": "tsc",
+		},
+	}
+
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 0 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
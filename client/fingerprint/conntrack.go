@@ -0,0 +1,69 @@
+package fingerprint
+
+import (
+	"log"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// conntrackInterval is the interval at which the connection tracking
+	// table is fingerprinted as the number of tracked connections changes
+	// continuously.
+	conntrackInterval = 15 * time.Second
+
+	conntrackMaxAttr   = "network.conntrack.max"
+	conntrackCountAttr = "unique.network.conntrack.count"
+
+	// conntrackMaxSysctl and conntrackCountSysctl are the paths of the
+	// sysctls under /proc/sys. They only exist once the nf_conntrack module
+	// is loaded.
+	conntrackMaxSysctl   = "net/netfilter/nf_conntrack_max"
+	conntrackCountSysctl = "net/netfilter/nf_conntrack_count"
+)
+
+// ConntrackFingerprint is used to fingerprint the size of the connection
+// tracking table and the number of connections it tracks, which NAT-heavy
+// bridge networking can exhaust.
+type ConntrackFingerprint struct {
+	logger *log.Logger
+
+	// procSysDir is the directory the sysctls are read from
+	procSysDir string
+}
+
+// NewConntrackFingerprint is used to create a connection tracking fingerprint
+func NewConntrackFingerprint(logger *log.Logger) Fingerprint {
+	f := &ConntrackFingerprint{
+		logger:     logger,
+		procSysDir: "/proc/sys",
+	}
+	return f
+}
+
+func (f *ConntrackFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	max, err := strconv.ParseUint(readSysfsValue(filepath.Join(f.procSysDir, conntrackMaxSysctl)), 10, 64)
+	if err != nil {
+		// Connections aren't tracked without the nf_conntrack module
+		delete(node.Attributes, conntrackMaxAttr)
+		delete(node.Attributes, conntrackCountAttr)
+		return false, nil
+	}
+	node.Attributes[conntrackMaxAttr] = strconv.FormatUint(max, 10)
+
+	if count, err := strconv.ParseUint(readSysfsValue(filepath.Join(f.procSysDir, conntrackCountSysctl)), 10, 64); err == nil {
+		node.Attributes[conntrackCountAttr] = strconv.FormatUint(count, 10)
+	} else {
+		delete(node.Attributes, conntrackCountAttr)
+	}
+	return true, nil
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *ConntrackFingerprint) Periodic() (bool, time.Duration) {
+	return true, conntrackInterval
+}
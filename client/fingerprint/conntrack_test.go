@@ -0,0 +1,65 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestConntrackFingerprint(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{
+		"net/netfilter/nf_conntrack_max":   "262144\n",
+		"net/netfilter/nf_conntrack_count": "1523\n",
+	})
+	defer os.RemoveAll(dir)
+
+	f := &ConntrackFingerprint{
+		logger:     testLogger(),
+		procSysDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "network.conntrack.max", "262144")
+	assertNodeAttributeEquals(t, node, "unique.network.conntrack.count", "1523")
+
+	// The next poll picks up the new count
+	if err := ioutil.WriteFile(filepath.Join(dir, "net/netfilter/nf_conntrack_count"), []byte("260001\n"), 0644); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "unique.network.conntrack.count", "260001")
+}
+
+func TestConntrackFingerprint_NotLoaded(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{})
+	defer os.RemoveAll(dir)
+
+	f := &ConntrackFingerprint{
+		logger:     testLogger(),
+		procSysDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"network.conntrack.max":          "262144",
+			"unique.network.conntrack.count": "12",
+		},
+	}
+
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 0 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
@@ -3,10 +3,13 @@ package fingerprint
 import (
 	"fmt"
 	"log"
+	"net"
 	"strconv"
+	"strings"
 	"time"
 
 	consul "github.com/hashicorp/consul/api"
+	"github.com/miekg/dns"
 
 	client "github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -15,6 +18,10 @@ import (
 const (
 	consulAvailable   = "available"
 	consulUnavailable = "unavailable"
+
+	// consulDNSTimeout is the length of time to wait for the Consul agent to
+	// answer a DNS query before considering its DNS interface unreachable.
+	consulDNSTimeout = 2 * time.Second
 )
 
 // ConsulFingerprint is used to fingerprint for Consul
@@ -22,11 +29,19 @@ type ConsulFingerprint struct {
 	logger    *log.Logger
 	client    *consul.Client
 	lastState string
+
+	// dnsProbe is used to check whether the Consul agent answers DNS queries
+	// on the given address. It can be overridden for testing.
+	dnsProbe func(addr string) error
 }
 
 // NewConsulFingerprint is used to create a Consul fingerprint
 func NewConsulFingerprint(logger *log.Logger) Fingerprint {
-	return &ConsulFingerprint{logger: logger, lastState: consulUnavailable}
+	return &ConsulFingerprint{
+		logger:    logger,
+		lastState: consulUnavailable,
+		dnsProbe:  probeConsulDNS,
+	}
 }
 
 func (f *ConsulFingerprint) Fingerprint(config *client.Config, node *structs.Node) (bool, error) {
@@ -75,6 +90,8 @@ func (f *ConsulFingerprint) Fingerprint(config *client.Config, node *structs.Nod
 		node.Attributes["consul.datacenter"],
 		node.Attributes["unique.consul.name"])
 
+	f.fingerprintDNS(config, info, node)
+
 	// If the Consul Agent was previously unavailable print a message to
 	// indicate the Agent is available now
 	if f.lastState == consulUnavailable {
@@ -84,6 +101,56 @@ func (f *ConsulFingerprint) Fingerprint(config *client.Config, node *structs.Nod
 	return true, nil
 }
 
+// fingerprintDNS records the port of the Consul agent's DNS interface and
+// whether it answers queries. The attributes are removed if the DNS interface
+// is disabled.
+func (f *ConsulFingerprint) fingerprintDNS(config *client.Config, info map[string]map[string]interface{}, node *structs.Node) {
+	ports, _ := info["Config"]["Ports"].(map[string]interface{})
+	port, ok := ports["DNS"].(float64)
+	if !ok || port <= 0 {
+		delete(node.Attributes, "consul.dns.port")
+		delete(node.Attributes, "consul.dns.reachable")
+		return
+	}
+
+	// The DNS interface is probed on the host the agent's HTTP API is reached
+	// on, as both are bound to the agent's client address by default.
+	host := "127.0.0.1"
+	addr := config.ConsulConfig.Addr
+	if i := strings.Index(addr, "://"); i != -1 {
+		addr = addr[i+3:]
+	}
+	if h, _, err := net.SplitHostPort(addr); err == nil && h != "" {
+		host = h
+	}
+
+	dnsAddr := net.JoinHostPort(host, strconv.Itoa(int(port)))
+	reachable := true
+	if err := f.dnsProbe(dnsAddr); err != nil {
+		f.logger.Printf("[DEBUG] fingerprint.consul: consul DNS at %s is unreachable: %v", dnsAddr, err)
+		reachable = false
+	}
+
+	node.Attributes["consul.dns.port"] = strconv.Itoa(int(port))
+	node.Attributes["consul.dns.reachable"] = strconv.FormatBool(reachable)
+}
+
+// probeConsulDNS queries the Consul agent's DNS interface at the address for
+// the SOA record of its domain. Any answer, including an error response,
+// means the interface is reachable.
+func probeConsulDNS(addr string) error {
+	m := new(dns.Msg)
+	m.SetQuestion("consul.", dns.TypeSOA)
+
+	c := &dns.Client{
+		DialTimeout:  consulDNSTimeout,
+		ReadTimeout:  consulDNSTimeout,
+		WriteTimeout: consulDNSTimeout,
+	}
+	_, _, err := c.Exchange(m, addr)
+	return err
+}
+
 // clearConsulAttributes removes consul attributes and links from the passed
 // Node.
 func (f *ConsulFingerprint) clearConsulAttributes(n *structs.Node) {
@@ -92,6 +159,8 @@ func (f *ConsulFingerprint) clearConsulAttributes(n *structs.Node) {
 	delete(n.Attributes, "consul.revision")
 	delete(n.Attributes, "unique.consul.name")
 	delete(n.Attributes, "consul.datacenter")
+	delete(n.Attributes, "consul.dns.port")
+	delete(n.Attributes, "consul.dns.reachable")
 	delete(n.Links, "consul")
 }
 
@@ -12,7 +12,8 @@ import (
 )
 
 func TestConsulFingerprint(t *testing.T) {
-	fp := NewConsulFingerprint(testLogger())
+	fp := NewConsulFingerprint(testLogger()).(*ConsulFingerprint)
+	fp.dnsProbe = func(string) error { return nil }
 	node := &structs.Node{
 		Attributes: make(map[string]string),
 	}
@@ -45,6 +46,90 @@ func TestConsulFingerprint(t *testing.T) {
 	}
 }
 
+func TestConsulFingerprint_AgentIdentity(t *testing.T) {
+	var path string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, mockConsulResponse)
+	}))
+	defer ts.Close()
+
+	config := config.DefaultConfig()
+	config.ConsulConfig.Addr = strings.TrimPrefix(ts.URL, "http://")
+
+	fp := NewConsulFingerprint(testLogger()).(*ConsulFingerprint)
+	fp.dnsProbe = func(string) error { return nil }
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	ok, err := fp.Fingerprint(config, node)
+	if err != nil {
+		t.Fatalf("Failed to fingerprint: %s", err)
+	}
+	if !ok {
+		t.Fatalf("Failed to apply node attributes")
+	}
+	if path != "/v1/agent/self" {
+		t.Fatalf("expected the agent self endpoint to be queried, got %q", path)
+	}
+
+	assertNodeAttributeEquals(t, node, "consul.datacenter", "vagrant")
+	assertNodeAttributeEquals(t, node, "unique.consul.name", "consul2")
+	if link := node.Links["consul"]; link != "vagrant.consul2" {
+		t.Fatalf("expected consul link vagrant.consul2, got %q", link)
+	}
+}
+
+func TestConsulFingerprint_DNS(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, mockConsulResponse)
+	}))
+	defer ts.Close()
+
+	config := config.DefaultConfig()
+	config.ConsulConfig.Addr = strings.TrimPrefix(ts.URL, "http://")
+
+	cases := []struct {
+		name      string
+		err       error
+		reachable string
+	}{
+		{"reachable", nil, "true"},
+		{"unreachable", fmt.Errorf("i/o timeout"), "false"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var probed string
+			fp := NewConsulFingerprint(testLogger()).(*ConsulFingerprint)
+			fp.dnsProbe = func(addr string) error {
+				probed = addr
+				return c.err
+			}
+			node := &structs.Node{
+				Attributes: make(map[string]string),
+			}
+
+			ok, err := fp.Fingerprint(config, node)
+			if err != nil {
+				t.Fatalf("Failed to fingerprint: %s", err)
+			}
+			if !ok {
+				t.Fatalf("Failed to apply node attributes")
+			}
+
+			if probed != "127.0.0.1:8600" {
+				t.Fatalf("expected DNS probe of 127.0.0.1:8600, got %q", probed)
+			}
+			assertNodeAttributeEquals(t, node, "consul.dns.port", "8600")
+			assertNodeAttributeEquals(t, node, "consul.dns.reachable", c.reachable)
+		})
+	}
+}
+
 // Taken from tryconsul using consul release 0.5.2
 const mockConsulResponse = `
 {
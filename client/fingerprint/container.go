@@ -0,0 +1,121 @@
+package fingerprint
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// dockerEnvFile and containerEnvFile are created at the root of Docker
+	// and Podman containers respectively.
+	dockerEnvFile    = ".dockerenv"
+	containerEnvFile = "run/.containerenv"
+
+	// initCGroupFile lists the cgroups of the init process, which are nested
+	// under the container's cgroup when running inside a container.
+	initCGroupFile = "proc/1/cgroup"
+)
+
+// containerCGroupRuntimes maps substrings of cgroup paths to the container
+// runtime that created them. Kubernetes is checked first as its pods are also
+// created by another runtime.
+var containerCGroupRuntimes = []struct {
+	pattern string
+	runtime string
+}{
+	{"kubepods", "kubernetes"},
+	{"docker", "docker"},
+	{"libpod", "podman"},
+	{"lxc", "lxc"},
+	{"containerd", "containerd"},
+}
+
+// ContainerFingerprint is used to fingerprint whether the client is running
+// inside a container.
+type ContainerFingerprint struct {
+	StaticFingerprinter
+	logger *log.Logger
+
+	// root is the filesystem root the container markers are looked up under
+	root string
+}
+
+// NewContainerFingerprint is used to create a container fingerprint
+func NewContainerFingerprint(logger *log.Logger) Fingerprint {
+	f := &ContainerFingerprint{
+		logger: logger,
+		root:   "/",
+	}
+	return f
+}
+
+func (f *ContainerFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	containerized, runtime := f.detect()
+
+	if !containerized {
+		node.Attributes["host.containerized"] = "false"
+		delete(node.Attributes, "host.container.runtime")
+		return true, nil
+	}
+
+	node.Attributes["host.containerized"] = "true"
+	if runtime != "" {
+		node.Attributes["host.container.runtime"] = runtime
+		f.logger.Printf("[DEBUG] fingerprint.container: running inside a %s container", runtime)
+	} else {
+		delete(node.Attributes, "host.container.runtime")
+		f.logger.Printf("[DEBUG] fingerprint.container: running inside a container")
+	}
+	return true, nil
+}
+
+// detect returns whether the client is running inside a container and the
+// container runtime if it could be determined.
+func (f *ContainerFingerprint) detect() (bool, string) {
+	// The cgroup paths are checked first as they identify the runtime more
+	// precisely than the marker files.
+	if runtime := f.cgroupRuntime(); runtime != "" {
+		return true, runtime
+	}
+
+	if _, err := os.Stat(filepath.Join(f.root, dockerEnvFile)); err == nil {
+		return true, "docker"
+	}
+	if _, err := os.Stat(filepath.Join(f.root, containerEnvFile)); err == nil {
+		return true, "podman"
+	}
+	return false, ""
+}
+
+// cgroupRuntime returns the container runtime indicated by the cgroup paths of
+// the init process, or an empty string if they are not nested in a container.
+func (f *ContainerFingerprint) cgroupRuntime() string {
+	file, err := os.Open(filepath.Join(f.root, initCGroupFile))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	// Lines look something like:
+	//	4:pids:/docker/8f2cb2b5c2b4a0d4c5e1a2f3b4c5d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0a1b2c3
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+
+		for _, r := range containerCGroupRuntimes {
+			if strings.Contains(parts[2], r.pattern) {
+				return r.runtime
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestContainerFingerprint(t *testing.T) {
+	cases := []struct {
+		name          string
+		files         map[string]string
+		containerized string
+		runtime       string
+	}{
+		{
+			name: "bare host",
+			files: map[string]string{
+				initCGroupFile: "4:pids:/init.scope\n1:name=systemd:/init.scope\n0::/init.scope\n",
+			},
+			containerized: "false",
+		},
+		{
+			name: "docker cgroup",
+			files: map[string]string{
+				initCGroupFile: "4:pids:/docker/8f2cb2b5c2b4\n1:name=systemd:/docker/8f2cb2b5c2b4\n",
+			},
+			containerized: "true",
+			runtime:       "docker",
+		},
+		{
+			name: "kubernetes cgroup",
+			files: map[string]string{
+				initCGroupFile: "4:pids:/kubepods/besteffort/pod1234/8f2cb2b5c2b4\n",
+			},
+			containerized: "true",
+			runtime:       "kubernetes",
+		},
+		{
+			name: "dockerenv",
+			files: map[string]string{
+				dockerEnvFile:  "",
+				initCGroupFile: "0::/\n",
+			},
+			containerized: "true",
+			runtime:       "docker",
+		},
+		{
+			name: "containerenv",
+			files: map[string]string{
+				containerEnvFile: "engine=\"podman-4.0.0\"\n",
+			},
+			containerized: "true",
+			runtime:       "podman",
+		},
+	}
+
+	for _, c := range cases {
+		root, err := ioutil.TempDir("", "container")
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		defer os.RemoveAll(root)
+
+		for name, content := range c.files {
+			path := filepath.Join(root, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatalf("err: %v", err)
+			}
+			if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("err: %v", err)
+			}
+		}
+
+		f := &ContainerFingerprint{
+			logger: testLogger(),
+			root:   root,
+		}
+		node := &structs.Node{
+			Attributes: map[string]string{
+				"host.container.runtime": "stale",
+			},
+		}
+
+		assertFingerprintOK(t, f, node)
+		if actual := node.Attributes["host.containerized"]; actual != c.containerized {
+			t.Fatalf("%s: expected host.containerized %q, got %q", c.name, c.containerized, actual)
+		}
+		if actual := node.Attributes["host.container.runtime"]; actual != c.runtime {
+			t.Fatalf("%s: expected host.container.runtime %q, got %q", c.name, c.runtime, actual)
+		}
+	}
+}
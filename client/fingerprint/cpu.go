@@ -1,23 +1,54 @@
 package fingerprint
 
 import (
+	"bufio"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/helper/stats"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
+const (
+	// cpuInfoPath and cpuMicrocodePath are the files the microcode version
+	// is read from on Linux.
+	cpuInfoPath      = "/proc/cpuinfo"
+	cpuMicrocodePath = "/sys/devices/system/cpu/cpu0/microcode/version"
+
+	// cpuCgroupPath is the mount point of the cgroup hierarchies the CPU quota
+	// is read from. When running in a container it holds the container's own
+	// cgroup.
+	cpuCgroupPath = "/sys/fs/cgroup"
+
+	// cpuProcSelfCgroup is the file listing the cgroups of the client process
+	cpuProcSelfCgroup = "/proc/self/cgroup"
+)
+
 // CPUFingerprint is used to fingerprint the CPU
 type CPUFingerprint struct {
 	StaticFingerprinter
-	logger *log.Logger
+	logger        *log.Logger
+	cpuInfoFile   string
+	microcodeFile string
+	cgroupDir     string
+	procCgroup    string
 }
 
 // NewCPUFingerprint is used to create a CPU fingerprint
 func NewCPUFingerprint(logger *log.Logger) Fingerprint {
-	f := &CPUFingerprint{logger: logger}
+	f := &CPUFingerprint{
+		logger:        logger,
+		cpuInfoFile:   cpuInfoPath,
+		microcodeFile: cpuMicrocodePath,
+		cgroupDir:     cpuCgroupPath,
+		procCgroup:    cpuProcSelfCgroup,
+	}
 	return f
 }
 
@@ -53,6 +84,11 @@ func (f *CPUFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bo
 		f.logger.Printf("[DEBUG] fingerprint.cpu: core count: %d", numCores)
 	}
 
+	if microcode := f.microcode(); microcode != "" {
+		node.Attributes["cpu.microcode"] = microcode
+		f.logger.Printf("[DEBUG] fingerprint.cpu: microcode: %s", microcode)
+	}
+
 	tt := int(stats.TotalTicksAvailable())
 	if cfg.CpuCompute > 0 {
 		f.logger.Printf("[DEBUG] fingerprint.cpu: Using specified cpu compute %d", cfg.CpuCompute)
@@ -67,6 +103,20 @@ func (f *CPUFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bo
 			"cpu_total_compute")
 	}
 
+	// Limit the compute to the cgroup CPU quota the client is running under
+	// so it doesn't advertise more compute than it can use.
+	delete(node.Attributes, "cpu.quota-mhz")
+	if cores, ok := f.cgroupQuota(); ok {
+		if mhz := stats.CPUMHzPerCore(); mhz > 0 {
+			quota := int(cores * mhz)
+			node.Attributes["cpu.quota-mhz"] = strconv.Itoa(quota)
+			f.logger.Printf("[DEBUG] fingerprint.cpu: cgroup quota of %.2f cores: %d MHz", cores, quota)
+			if quota < tt {
+				tt = quota
+			}
+		}
+	}
+
 	node.Attributes["cpu.totalcompute"] = fmt.Sprintf("%d", tt)
 
 	if node.Resources == nil {
@@ -76,3 +126,102 @@ func (f *CPUFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bo
 	node.Resources.CPU = tt
 	return true, nil
 }
+
+// microcode returns the microcode version of the CPU, read from the microcode
+// field of the cpuinfo file or from sysfs. An empty string is returned where
+// the version is unavailable.
+func (f *CPUFingerprint) microcode() string {
+	if file, err := os.Open(f.cpuInfoFile); err == nil {
+		defer file.Close()
+
+		// The field looks something like:
+		//	microcode	: 0xb4
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			parts := strings.SplitN(scanner.Text(), ":", 2)
+			if len(parts) == 2 && strings.TrimSpace(parts[0]) == "microcode" {
+				if v := strings.TrimSpace(parts[1]); v != "" {
+					return v
+				}
+			}
+		}
+	}
+
+	if out, err := ioutil.ReadFile(f.microcodeFile); err == nil {
+		return strings.TrimSpace(string(out))
+	}
+	return ""
+}
+
+// cgroupQuota returns the number of cores the CPU quota of the cgroup of the
+// client allows. ok is false if no quota is set.
+func (f *CPUFingerprint) cgroupQuota() (cores float64, ok bool) {
+	var groups map[string]string
+	if content, err := ioutil.ReadFile(f.procCgroup); err == nil {
+		groups = parseProcCgroup(string(content))
+	}
+	if cores, ok := cgroupCPUQuota(f.cgroupDir, groups); ok {
+		return cores, true
+	}
+
+	// Containers without a cgroup namespace list the cgroup of the container
+	// on the host, while their own cgroup is mounted at the root.
+	return cgroupCPUQuota(f.cgroupDir, nil)
+}
+
+// cgroupCPUQuota returns the number of cores the CPU quota of the cgroup given
+// by controller in groups allows, read from cpu.max on cgroup v2 or
+// cpu.cfs_quota_us and cpu.cfs_period_us on cgroup v1 under the mount point.
+// A controller missing from groups is read from the root cgroup. ok is false
+// if no quota is set.
+func cgroupCPUQuota(mount string, groups map[string]string) (cores float64, ok bool) {
+	var quota, period string
+	if max := readSysfsValue(filepath.Join(mount, groups[""], "cpu.max")); max != "" {
+		// The file looks something like:
+		//	200000 100000
+		fields := strings.Fields(max)
+		if len(fields) != 2 {
+			return 0, false
+		}
+		quota, period = fields[0], fields[1]
+	} else {
+		dir := filepath.Join(mount, "cpu", groups["cpu"])
+		quota = readSysfsValue(filepath.Join(dir, "cpu.cfs_quota_us"))
+		period = readSysfsValue(filepath.Join(dir, "cpu.cfs_period_us"))
+	}
+
+	// An unlimited quota is "max" on v2 and -1 on v1
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0, false
+	}
+	return q / p, true
+}
+
+// parseProcCgroup parses the content of /proc/<pid>/cgroup into the cgroup of
+// the process by controller. The cgroup in the v2 unified hierarchy is keyed
+// by the empty string.
+func parseProcCgroup(content string) map[string]string {
+	// Lines look something like:
+	//	8:pids:/system.slice/nomad.service
+	//	0::/system.slice/nomad.service
+	groups := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		if fields[1] == "" {
+			groups[""] = fields[2]
+			continue
+		}
+		for _, controller := range strings.Split(fields[1], ",") {
+			groups[controller] = fields[2]
+		}
+	}
+	return groups
+}
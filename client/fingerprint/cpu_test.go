@@ -1,6 +1,10 @@
 package fingerprint
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 
 	"github.com/hashicorp/nomad/client/config"
@@ -76,3 +80,164 @@ func TestCPUFingerprint_OverrideCompute(t *testing.T) {
 		t.Fatalf("expected override cpu of %d but found %d", cfg.CpuCompute, node.Resources.CPU)
 	}
 }
+
+const cpuInfoFixture = `processor	: 0
+vendor_id	: GenuineIntel
+cpu family	: 6
+model		: 79
+model name	: Intel(R) Xeon(R) CPU E5-2686 v4 @ 2.30GHz
+stepping	: 1
+microcode	: 0xb000038
+cpu MHz		: 2300.000
+
+processor	: 1
+vendor_id	: GenuineIntel
+microcode	: 0xb000038
+`
+
+func TestCPUFingerprint_Microcode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cpu")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cpuInfo := filepath.Join(dir, "cpuinfo")
+	if err := ioutil.WriteFile(cpuInfo, []byte(cpuInfoFixture), 0644); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	sysfs := filepath.Join(dir, "version")
+	if err := ioutil.WriteFile(sysfs, []byte("0xde\n"), 0644); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	cases := []struct {
+		name          string
+		cpuInfoFile   string
+		microcodeFile string
+		expected      string
+	}{
+		{"cpuinfo", cpuInfo, sysfs, "0xb000038"},
+		{"sysfs", missing, sysfs, "0xde"},
+		{"unavailable", missing, missing, ""},
+	}
+
+	for _, c := range cases {
+		f := &CPUFingerprint{
+			logger:        testLogger(),
+			cpuInfoFile:   c.cpuInfoFile,
+			microcodeFile: c.microcodeFile,
+		}
+		if actual := f.microcode(); actual != c.expected {
+			t.Fatalf("%s: expected %q, got %q", c.name, c.expected, actual)
+		}
+	}
+}
+
+func TestCPUFingerprint_CgroupQuota(t *testing.T) {
+	cases := []struct {
+		name   string
+		files  map[string]string
+		groups map[string]string
+		cores  float64
+		ok     bool
+	}{
+		{
+			name:  "v2 limited",
+			files: map[string]string{"cpu.max": "150000 100000\n"},
+			cores: 1.5,
+			ok:    true,
+		},
+		{
+			name:  "v2 unlimited",
+			files: map[string]string{"cpu.max": "max 100000\n"},
+		},
+		{
+			name: "v1 limited",
+			files: map[string]string{
+				"cpu/cpu.cfs_quota_us":  "200000\n",
+				"cpu/cpu.cfs_period_us": "100000\n",
+			},
+			cores: 2,
+			ok:    true,
+		},
+		{
+			name: "v1 unlimited",
+			files: map[string]string{
+				"cpu/cpu.cfs_quota_us":  "-1\n",
+				"cpu/cpu.cfs_period_us": "100000\n",
+			},
+		},
+		{
+			name: "v2 nested",
+			files: map[string]string{
+				"cpu.max":                            "max 100000\n",
+				"system.slice/nomad.service/cpu.max": "50000 100000\n",
+			},
+			groups: map[string]string{"": "/system.slice/nomad.service"},
+			cores:  0.5,
+			ok:     true,
+		},
+		{
+			name: "v1 nested",
+			files: map[string]string{
+				"cpu/cpu.cfs_quota_us":                             "-1\n",
+				"cpu/cpu.cfs_period_us":                            "100000\n",
+				"cpu/system.slice/nomad.service/cpu.cfs_quota_us":  "300000\n",
+				"cpu/system.slice/nomad.service/cpu.cfs_period_us": "100000\n",
+			},
+			groups: map[string]string{"cpu": "/system.slice/nomad.service", "cpuacct": "/system.slice/nomad.service"},
+			cores:  3,
+			ok:     true,
+		},
+		{
+			name: "no cgroups",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := writeSysfsTree(t, c.files)
+			defer os.RemoveAll(dir)
+
+			cores, ok := cgroupCPUQuota(dir, c.groups)
+			if ok != c.ok || cores != c.cores {
+				t.Fatalf("expected %v cores (%v), got %v (%v)", c.cores, c.ok, cores, ok)
+			}
+		})
+	}
+}
+
+func TestCPUFingerprint_CgroupQuotaLimitsCompute(t *testing.T) {
+	// A quota of a tenth of a core is below the compute of any host
+	dir := writeSysfsTree(t, map[string]string{
+		"system.slice/nomad.service/cpu.max": "10000 100000\n",
+		"proc/self/cgroup":                   "0::/system.slice/nomad.service\n",
+	})
+	defer os.RemoveAll(dir)
+
+	f := NewCPUFingerprint(testLogger()).(*CPUFingerprint)
+	f.cgroupDir = dir
+	f.procCgroup = filepath.Join(dir, "proc", "self", "cgroup")
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+	assertFingerprintOK(t, f, node)
+
+	mhz, err := strconv.ParseFloat(node.Attributes["cpu.frequency"], 64)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	quota, err := strconv.Atoi(node.Attributes["cpu.quota-mhz"])
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if expected := int(mhz / 10); quota < expected-1 || quota > expected+1 {
+		t.Fatalf("expected a quota of about %d MHz, got %d", expected, quota)
+	}
+	if node.Resources.CPU != quota {
+		t.Fatalf("expected compute to be limited to %d, got %d", quota, node.Resources.CPU)
+	}
+	assertNodeAttributeEquals(t, node, "cpu.totalcompute", strconv.Itoa(quota))
+}
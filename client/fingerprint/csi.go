@@ -0,0 +1,85 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	client "github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// csiAttrPrefix is the prefix of the attributes recording which CSI
+	// plugins are present.
+	csiAttrPrefix = "csi.plugin."
+
+	// csiPluginDirOption is the client option setting the directory CSI
+	// plugins create their sockets in, one subdirectory per plugin.
+	csiPluginDirOption = "fingerprint.csi.plugin_dir"
+
+	// csiSocketName is the name of the socket a CSI plugin listens on within
+	// its directory.
+	csiSocketName = "csi.sock"
+
+	// csiInterval is the interval at which CSI plugins are fingerprinted as
+	// their sockets are created and removed when the plugins start and stop.
+	csiInterval = 30 * time.Second
+)
+
+// CSIFingerprint is used to fingerprint the CSI plugins whose sockets are
+// present on the host.
+type CSIFingerprint struct {
+	logger *log.Logger
+}
+
+// NewCSIFingerprint is used to create a CSI plugin fingerprint
+func NewCSIFingerprint(logger *log.Logger) Fingerprint {
+	f := &CSIFingerprint{logger: logger}
+	return f
+}
+
+func (f *CSIFingerprint) Fingerprint(cfg *client.Config, node *structs.Node) (bool, error) {
+	// Clear any attributes from the previous fingerprint
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, csiAttrPrefix) {
+			delete(node.Attributes, k)
+		}
+	}
+
+	dir := cfg.Read(csiPluginDirOption)
+	if dir == "" {
+		return false, nil
+	}
+
+	// The directory looks something like:
+	//	/var/lib/csi/plugins/ebs.csi.aws.com/csi.sock
+	plugins, err := ioutil.ReadDir(dir)
+	if err != nil {
+		f.logger.Printf("[DEBUG] fingerprint.csi: Error reading plugin dir %s: %v", dir, err)
+		return false, nil
+	}
+
+	for _, plugin := range plugins {
+		if !plugin.IsDir() {
+			continue
+		}
+
+		present := false
+		sock := filepath.Join(dir, plugin.Name(), csiSocketName)
+		if fi, err := os.Stat(sock); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			present = true
+		}
+		node.Attributes[csiAttrPrefix+plugin.Name()+".present"] = strconv.FormatBool(present)
+	}
+	return true, nil
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *CSIFingerprint) Periodic() (bool, time.Duration) {
+	return true, csiInterval
+}
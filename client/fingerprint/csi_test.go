@@ -0,0 +1,90 @@
+package fingerprint
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestCSIFingerprint(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix sockets are not supported")
+	}
+
+	// The plugin without a socket has only left its directory behind
+	dir := writeSysfsTree(t, map[string]string{
+		"hostpath.csi.k8s.io/registration": "",
+		"README":                           "not a plugin",
+	})
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "ebs.csi.aws.com"), 0755); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l, err := net.Listen("unix", filepath.Join(dir, "ebs.csi.aws.com", "csi.sock"))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer l.Close()
+
+	f := NewCSIFingerprint(testLogger())
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"csi.plugin.removed.present": "true",
+		},
+	}
+	cfg := &config.Config{
+		Options: map[string]string{
+			"fingerprint.csi.plugin_dir": dir,
+		},
+	}
+
+	ok, err := f.Fingerprint(cfg, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("should apply")
+	}
+
+	assertNodeAttributeEquals(t, node, "csi.plugin.ebs.csi.aws.com.present", "true")
+	assertNodeAttributeEquals(t, node, "csi.plugin.hostpath.csi.k8s.io.present", "false")
+	for _, k := range []string{"csi.plugin.README.present", "csi.plugin.removed.present"} {
+		if a, ok := node.Attributes[k]; ok {
+			t.Fatalf("unexpected attribute %s found, %s", k, a)
+		}
+	}
+}
+
+func TestCSIFingerprint_NoPluginDir(t *testing.T) {
+	f := NewCSIFingerprint(testLogger())
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"csi.plugin.ebs.csi.aws.com.present": "true",
+		},
+	}
+
+	for _, dir := range []string{"", filepath.Join(os.TempDir(), "nomad-csi-missing")} {
+		cfg := &config.Config{
+			Options: map[string]string{
+				"fingerprint.csi.plugin_dir": dir,
+			},
+		}
+
+		ok, err := f.Fingerprint(cfg, node)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if ok {
+			t.Fatalf("should not apply")
+		}
+		if len(node.Attributes) != 0 {
+			t.Fatalf("unexpected attributes: %v", node.Attributes)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package fingerprint
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	client "github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// dataDirDedicatedAttr is the attribute recording whether the data
+	// directory is on a different filesystem than the root filesystem.
+	dataDirDedicatedAttr = "storage.data-dir.dedicated-fs"
+)
+
+// DataDirFingerprint is used to fingerprint whether the data directory of the
+// allocations is on a filesystem dedicated to it rather than sharing the root
+// filesystem, where filling it up affects the whole host.
+type DataDirFingerprint struct {
+	StaticFingerprinter
+	logger     *log.Logger
+	mountsFile string
+}
+
+// NewDataDirFingerprint is used to create a data directory fingerprint
+func NewDataDirFingerprint(logger *log.Logger) Fingerprint {
+	f := &DataDirFingerprint{
+		logger:     logger,
+		mountsFile: procMounts,
+	}
+	return f
+}
+
+func (f *DataDirFingerprint) Fingerprint(cfg *client.Config, node *structs.Node) (bool, error) {
+	delete(node.Attributes, dataDirDedicatedAttr)
+
+	dir := cfg.AllocDir
+	if dir == "" {
+		return false, nil
+	}
+
+	// Compare the mount the directory resolves to, if it exists yet
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+
+	mounts, err := f.mounts()
+	if err != nil {
+		f.logger.Printf("[WARN] fingerprint.data_dir: Error reading %s: %v", f.mountsFile, err)
+		return false, nil
+	}
+
+	rootDevice, ok := mounts["/"]
+	if !ok {
+		f.logger.Printf("[WARN] fingerprint.data_dir: No root filesystem found in %s", f.mountsFile)
+		return false, nil
+	}
+
+	// The directory is on the filesystem mounted at its longest parent
+	mountPoint := "/"
+	for path := range mounts {
+		if len(path) > len(mountPoint) && (dir == path || strings.HasPrefix(dir, path+"/")) {
+			mountPoint = path
+		}
+	}
+
+	dedicated := mounts[mountPoint] != rootDevice
+	node.Attributes[dataDirDedicatedAttr] = strconv.FormatBool(dedicated)
+	return true, nil
+}
+
+// mounts returns the devices of the mounted filesystems by mount point. When
+// filesystems are stacked on the same mount point the last one is returned.
+func (f *DataDirFingerprint) mounts() (map[string]string, error) {
+	file, err := os.Open(f.mountsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Lines look something like:
+	//	/dev/nvme0n1p1 / ext4 rw,relatime 0 0
+	mounts := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		mounts[unescapeMountPath(fields[1])] = fields[0]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse mounts: %v", err)
+	}
+	return mounts, nil
+}
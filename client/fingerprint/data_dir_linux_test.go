@@ -0,0 +1,74 @@
+package fingerprint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const dataDirMountsFixture = `rootfs / rootfs rw 0 0
+proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0
+/dev/sda1 / ext4 rw,relatime,errors=remount-ro 0 0
+/dev/sdb1 /var/lib/nomad-data ext4 rw,relatime 0 0
+/dev/sda1 /srv/nomad-bind ext4 rw,relatime 0 0
+`
+
+func TestDataDirFingerprint(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{
+		"mounts": dataDirMountsFixture,
+	})
+	defer os.RemoveAll(dir)
+
+	f := &DataDirFingerprint{
+		logger:     testLogger(),
+		mountsFile: filepath.Join(dir, "mounts"),
+	}
+
+	cases := []struct {
+		allocDir  string
+		dedicated string
+	}{
+		{"/var/lib/nomad-data/alloc", "true"},
+		{"/var/lib/nomad-data", "true"},
+		{"/var/lib/nomad-database/alloc", "false"},
+		{"/opt/nomad/alloc", "false"},
+		{"/srv/nomad-bind/alloc", "false"},
+	}
+
+	for _, c := range cases {
+		node := &structs.Node{
+			Attributes: make(map[string]string),
+		}
+		cfg := &config.Config{AllocDir: c.allocDir}
+
+		ok, err := f.Fingerprint(cfg, node)
+		if err != nil {
+			t.Fatalf("%s: err: %v", c.allocDir, err)
+		}
+		if !ok {
+			t.Fatalf("%s: should apply", c.allocDir)
+		}
+		assertNodeAttributeEquals(t, node, "storage.data-dir.dedicated-fs", c.dedicated)
+	}
+
+	// No-op where the mounts can't be read
+	f.mountsFile = filepath.Join(dir, "missing")
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"storage.data-dir.dedicated-fs": "true",
+		},
+	}
+	ok, err := f.Fingerprint(&config.Config{AllocDir: "/opt/nomad/alloc"}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 0 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
@@ -0,0 +1,85 @@
+package fingerprint
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// dmiAttributes maps the files of the DMI sysfs directory to the attributes
+// they are fingerprinted as.
+var dmiAttributes = map[string]string{
+	"bios_vendor":  "hardware.bios.vendor",
+	"bios_version": "hardware.bios.version",
+	"product_name": "hardware.product.name",
+}
+
+const (
+	// bootModeAttr is the attribute the firmware the node booted with is
+	// fingerprinted as.
+	bootModeAttr = "hardware.boot-mode"
+
+	// Boot modes of the node
+	bootModeUEFI = "uefi"
+	bootModeBIOS = "bios"
+)
+
+// DMIFingerprint is used to fingerprint the firmware and product details
+// reported by DMI.
+type DMIFingerprint struct {
+	StaticFingerprinter
+	logger *log.Logger
+
+	// sysfsDir is the sysfs directory of the DMI identification data
+	sysfsDir string
+
+	// firmwareDir is the sysfs directory of the firmware interfaces. It
+	// contains an efi directory only when the node was booted with UEFI.
+	firmwareDir string
+}
+
+// NewDMIFingerprint is used to create a DMI fingerprint
+func NewDMIFingerprint(logger *log.Logger) Fingerprint {
+	f := &DMIFingerprint{
+		logger:      logger,
+		sysfsDir:    "/sys/class/dmi/id",
+		firmwareDir: "/sys/firmware",
+	}
+	return f
+}
+
+func (f *DMIFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	// Virtual machines may not populate every field so each is best effort
+	applies := false
+	for file, attr := range dmiAttributes {
+		if value := readSysfsValue(filepath.Join(f.sysfsDir, file)); value != "" {
+			node.Attributes[attr] = value
+			applies = true
+		} else {
+			delete(node.Attributes, attr)
+		}
+	}
+
+	if mode := f.bootMode(); mode != "" {
+		node.Attributes[bootModeAttr] = mode
+		applies = true
+	} else {
+		delete(node.Attributes, bootModeAttr)
+	}
+	return applies, nil
+}
+
+// bootMode returns whether the node was booted with UEFI or a legacy BIOS, or
+// an empty string if the firmware directory is not available.
+func (f *DMIFingerprint) bootMode() string {
+	if _, err := os.Stat(f.firmwareDir); err != nil {
+		return ""
+	}
+	if _, err := os.Stat(filepath.Join(f.firmwareDir, "efi")); err == nil {
+		return bootModeUEFI
+	}
+	return bootModeBIOS
+}
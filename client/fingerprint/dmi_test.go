@@ -0,0 +1,97 @@
+package fingerprint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestDMIFingerprint(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{
+		"bios_vendor":  "Dell Inc.\n",
+		"bios_version": "2.11.0\n",
+		"product_name": "PowerEdge R640\n",
+	})
+	defer os.RemoveAll(dir)
+
+	f := &DMIFingerprint{
+		logger:   testLogger(),
+		sysfsDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "hardware.bios.vendor", "Dell Inc.")
+	assertNodeAttributeEquals(t, node, "hardware.bios.version", "2.11.0")
+	assertNodeAttributeEquals(t, node, "hardware.product.name", "PowerEdge R640")
+}
+
+func TestDMIFingerprint_MissingFields(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{
+		"bios_vendor":  "SeaBIOS\n",
+		"product_name": "\n",
+	})
+	defer os.RemoveAll(dir)
+
+	f := &DMIFingerprint{
+		logger:   testLogger(),
+		sysfsDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "hardware.bios.vendor", "SeaBIOS")
+	for _, k := range []string{"hardware.bios.version", "hardware.product.name"} {
+		if a, ok := node.Attributes[k]; ok {
+			t.Fatalf("unexpected attribute %s found, %s", k, a)
+		}
+	}
+
+	// No-op where DMI is absent
+	f.sysfsDir = dir + "-missing"
+	f.firmwareDir = dir + "-missing"
+	node.Attributes = make(map[string]string)
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 0 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
+
+func TestDMIFingerprint_BootMode(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{
+		"efi/fw_platform_size": "64\n",
+	})
+	defer os.RemoveAll(dir)
+
+	f := &DMIFingerprint{
+		logger:      testLogger(),
+		sysfsDir:    dir + "-missing",
+		firmwareDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "hardware.boot-mode", "uefi")
+
+	// Without the efi directory the node booted with a legacy BIOS
+	if err := os.RemoveAll(filepath.Join(dir, "efi")); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "hardware.boot-mode", "bios")
+}
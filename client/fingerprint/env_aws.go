@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -121,6 +122,13 @@ func (f *EnvAWSFingerprint) Fingerprint(cfg *config.Config, node *structs.Node)
 		node.Attributes[key] = strings.Trim(string(resp), "\n")
 	}
 
+	// Derive the resources of the instance type
+	instanceType := node.Attributes["platform.aws.instance-type"]
+	if cpus, memoryMB, ok := parseInstanceType(providerAWS, instanceType); ok {
+		node.Attributes["platform.aws.instance-type.cpu"] = strconv.Itoa(cpus)
+		node.Attributes["platform.aws.instance-type.memory-mb"] = strconv.Itoa(memoryMB)
+	}
+
 	// copy over network specific information
 	if val := node.Attributes["unique.platform.aws.local-ipv4"]; val != "" {
 		node.Attributes["unique.network.ip-address"] = val
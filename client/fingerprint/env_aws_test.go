@@ -77,6 +77,13 @@ func TestEnvAWSFingerprint_aws(t *testing.T) {
 		assertNodeAttributeContains(t, node, k)
 	}
 
+	// The resources of previous generation instance types are not derived
+	for _, k := range []string{"platform.aws.instance-type.cpu", "platform.aws.instance-type.memory-mb"} {
+		if a, ok := node.Attributes[k]; ok {
+			t.Fatalf("unexpected attribute %s found, %s", k, a)
+		}
+	}
+
 	if len(node.Links) == 0 {
 		t.Fatalf("Empty links for Node in AWS Fingerprint test")
 	}
@@ -87,6 +94,47 @@ func TestEnvAWSFingerprint_aws(t *testing.T) {
 	}
 }
 
+func TestEnvAWSFingerprint_instanceType(t *testing.T) {
+	f := NewEnvAWSFingerprint(testLogger())
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	// configure mock server with fixture routes, serving a current generation
+	// instance type
+	routes := routes{}
+	if err := json.Unmarshal([]byte(aws_routes), &routes); err != nil {
+		t.Fatalf("Failed to unmarshal JSON in AWS ENV test: %s", err)
+	}
+	for _, e := range routes.Endpoints {
+		if e.Uri == "/latest/meta-data/instance-type" {
+			e.Body = "m5.2xlarge"
+		}
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, e := range routes.Endpoints {
+			if r.RequestURI == e.Uri {
+				w.Header().Set("Content-Type", e.ContentType)
+				fmt.Fprintln(w, e.Body)
+			}
+		}
+	}))
+	defer ts.Close()
+	os.Setenv("AWS_ENV_URL", ts.URL+"/latest/meta-data/")
+
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("Expected AWS attributes and Links")
+	}
+
+	assertNodeAttributeEquals(t, node, "platform.aws.instance-type", "m5.2xlarge")
+	assertNodeAttributeEquals(t, node, "platform.aws.instance-type.cpu", "8")
+	assertNodeAttributeEquals(t, node, "platform.aws.instance-type.memory-mb", "32768")
+}
+
 type routes struct {
 	Endpoints []*endpoint `json:"endpoints"`
 }
@@ -22,6 +22,13 @@ import (
 // "instance" path as well since it's the only one we access here.
 const DEFAULT_GCE_URL = "http://169.254.169.254/computeMetadata/v1/instance/"
 
+const (
+	// gceAttrAllowlistOption and gceAttrDenylistOption are the client options
+	// listing the custom metadata keys that are and are not fingerprinted.
+	gceAttrAllowlistOption = "fingerprint.gce.attribute_allowlist"
+	gceAttrDenylistOption  = "fingerprint.gce.attribute_denylist"
+)
+
 type GCEMetadataNetworkInterface struct {
 	AccessConfigs []struct {
 		ExternalIp string
@@ -29,6 +36,7 @@ type GCEMetadataNetworkInterface struct {
 	}
 	ForwardedIps []string
 	Ip           string
+	Ipv6s        []string
 	Network      string
 }
 
@@ -177,6 +185,16 @@ func (f *EnvGCEFingerprint) Fingerprint(cfg *config.Config, node *structs.Node)
 		node.Attributes[key] = strings.Trim(lastToken(value), "\n")
 	}
 
+	// Derive the resources of the machine type, which for custom machine types
+	// are encoded in the name.
+	machineType := node.Attributes["platform.gce.machine-type"]
+	if cpus, memoryMB, ok := parseInstanceType(providerGCE, machineType); ok {
+		node.Attributes["platform.gce.machine-type.cpu"] = strconv.Itoa(cpus)
+		if memoryMB != 0 {
+			node.Attributes["platform.gce.machine-type.memory-mb"] = strconv.Itoa(memoryMB)
+		}
+	}
+
 	// Get internal and external IPs (if they exist)
 	value, err := f.Get("network-interfaces/", true)
 	var interfaces []GCEMetadataNetworkInterface
@@ -192,6 +210,17 @@ func (f *EnvGCEFingerprint) Fingerprint(cfg *config.Config, node *structs.Node)
 		for index, accessConfig := range intf.AccessConfigs {
 			node.Attributes[uniquePrefix+".external-ip."+strconv.Itoa(index)] = accessConfig.ExternalIp
 		}
+		for index, ip := range intf.Ipv6s {
+			node.Attributes[uniquePrefix+".ipv6."+strconv.Itoa(index)] = strings.Trim(ip, "\n")
+		}
+	}
+
+	// Prefer the IPv6 address assigned by GCE over the one detected on the host
+	for _, intf := range interfaces {
+		if len(intf.Ipv6s) != 0 {
+			node.Attributes["unique.network.ip-address-v6"] = strings.Trim(intf.Ipv6s[0], "\n")
+			break
+		}
 	}
 
 	var tagList []string
@@ -226,7 +255,7 @@ func (f *EnvGCEFingerprint) Fingerprint(cfg *config.Config, node *structs.Node)
 	if err := json.Unmarshal([]byte(value), &attrDict); err != nil {
 		f.logger.Printf("[WARN] fingerprint.env_gce: Error decoding instance attributes: %s", err.Error())
 	}
-	for k, v := range attrDict {
+	for k, v := range filterGCEAttributes(cfg, attrDict) {
 		attr := "platform.gce.attr."
 		var key string
 
@@ -248,6 +277,30 @@ func (f *EnvGCEFingerprint) Fingerprint(cfg *config.Config, node *structs.Node)
 	return true, nil
 }
 
+// filterGCEAttributes returns the custom metadata attributes permitted by the
+// allowlist and denylist client options. An empty allowlist permits every key
+// not in the denylist. Keys are matched without their unique namespace.
+func filterGCEAttributes(cfg *config.Config, attrs map[string]string) map[string]string {
+	allow := cfg.ReadStringListToMap(gceAttrAllowlistOption)
+	deny := cfg.ReadStringListToMap(gceAttrDenylistOption)
+	if len(allow) == 0 && len(deny) == 0 {
+		return attrs
+	}
+
+	filtered := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		name := strings.TrimPrefix(k, structs.NodeUniqueNamespace)
+		if _, ok := deny[name]; ok {
+			continue
+		}
+		if _, ok := allow[name]; len(allow) != 0 && !ok {
+			continue
+		}
+		filtered[k] = v
+	}
+	return filtered
+}
+
 func (f *EnvGCEFingerprint) isGCE() bool {
 	// TODO: better way to detect GCE?
 
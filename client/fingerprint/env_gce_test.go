@@ -44,7 +44,7 @@ func testFingerprint_GCE(t *testing.T, withExternalIp bool) {
 		ContentType: "application/json",
 	}
 	if withExternalIp {
-		networkEndpoint.Body = `[{"accessConfigs":[{"externalIp":"104.44.55.66","type":"ONE_TO_ONE_NAT"},{"externalIp":"104.44.55.67","type":"ONE_TO_ONE_NAT"}],"forwardedIps":[],"ip":"10.240.0.5","network":"projects/555555/networks/default"}]`
+		networkEndpoint.Body = `[{"accessConfigs":[{"externalIp":"104.44.55.66","type":"ONE_TO_ONE_NAT"},{"externalIp":"104.44.55.67","type":"ONE_TO_ONE_NAT"}],"forwardedIps":[],"ip":"10.240.0.5","ipv6s":["2600:1900:4000:1::5"],"network":"projects/555555/networks/default"}]`
 	} else {
 		networkEndpoint.Body = `[{"accessConfigs":[],"forwardedIps":[],"ip":"10.240.0.5","network":"projects/555555/networks/default"}]`
 	}
@@ -116,13 +116,22 @@ func testFingerprint_GCE(t *testing.T, withExternalIp bool) {
 	assertNodeAttributeEquals(t, node, "unique.platform.gce.hostname", "instance-1.c.project.internal")
 	assertNodeAttributeEquals(t, node, "platform.gce.zone", "us-central1-f")
 	assertNodeAttributeEquals(t, node, "platform.gce.machine-type", "n1-standard-1")
+	assertNodeAttributeEquals(t, node, "platform.gce.machine-type.cpu", "1")
+	assertNodeAttributeEquals(t, node, "platform.gce.machine-type.memory-mb", "3840")
 	assertNodeAttributeEquals(t, node, "platform.gce.network.default", "true")
 	assertNodeAttributeEquals(t, node, "unique.platform.gce.network.default.ip", "10.240.0.5")
 	if withExternalIp {
 		assertNodeAttributeEquals(t, node, "unique.platform.gce.network.default.external-ip.0", "104.44.55.66")
 		assertNodeAttributeEquals(t, node, "unique.platform.gce.network.default.external-ip.1", "104.44.55.67")
-	} else if _, ok := node.Attributes["unique.platform.gce.network.default.external-ip.0"]; ok {
-		t.Fatal("unique.platform.gce.network.default.external-ip is set without an external IP")
+		assertNodeAttributeEquals(t, node, "unique.platform.gce.network.default.ipv6.0", "2600:1900:4000:1::5")
+		assertNodeAttributeEquals(t, node, "unique.network.ip-address-v6", "2600:1900:4000:1::5")
+	} else {
+		if _, ok := node.Attributes["unique.platform.gce.network.default.external-ip.0"]; ok {
+			t.Fatal("unique.platform.gce.network.default.external-ip is set without an external IP")
+		}
+		if _, ok := node.Attributes["unique.network.ip-address-v6"]; ok {
+			t.Fatal("unique.network.ip-address-v6 is set without an IPv6 address")
+		}
 	}
 
 	assertNodeAttributeEquals(t, node, "platform.gce.scheduling.automatic-restart", "TRUE")
@@ -195,3 +204,57 @@ func TestFingerprint_GCEWithExternalIp(t *testing.T) {
 func TestFingerprint_GCEWithoutExternalIp(t *testing.T) {
 	testFingerprint_GCE(t, false)
 }
+
+func TestGCEFingerprint_FilterAttributes(t *testing.T) {
+	attrs := map[string]string{
+		"ghi":        "111",
+		"jkl":        "222",
+		"unique.bar": "333",
+		"ssh-keys":   "secret",
+	}
+
+	cases := []struct {
+		name     string
+		options  map[string]string
+		expected []string
+	}{
+		{
+			name:     "unfiltered",
+			expected: []string{"ghi", "jkl", "unique.bar", "ssh-keys"},
+		},
+		{
+			name: "allowlist",
+			options: map[string]string{
+				gceAttrAllowlistOption: "ghi, bar",
+			},
+			expected: []string{"ghi", "unique.bar"},
+		},
+		{
+			name: "denylist",
+			options: map[string]string{
+				gceAttrDenylistOption: "ssh-keys",
+			},
+			expected: []string{"ghi", "jkl", "unique.bar"},
+		},
+		{
+			name: "both",
+			options: map[string]string{
+				gceAttrAllowlistOption: "ghi,ssh-keys",
+				gceAttrDenylistOption:  "ssh-keys",
+			},
+			expected: []string{"ghi"},
+		},
+	}
+
+	for _, c := range cases {
+		filtered := filterGCEAttributes(&config.Config{Options: c.options}, attrs)
+		if len(filtered) != len(c.expected) {
+			t.Fatalf("%s: expected %v, got %v", c.name, c.expected, filtered)
+		}
+		for _, k := range c.expected {
+			if filtered[k] != attrs[k] {
+				t.Fatalf("%s: expected %q to be %q, got %v", c.name, k, attrs[k], filtered)
+			}
+		}
+	}
+}
@@ -26,16 +26,24 @@ var (
 	// hostFingerprinters contains the host fingerprints which are available for a
 	// given platform.
 	hostFingerprinters = map[string]Factory{
-		"arch":    NewArchFingerprint,
-		"consul":  NewConsulFingerprint,
-		"cpu":     NewCPUFingerprint,
-		"host":    NewHostFingerprint,
-		"memory":  NewMemoryFingerprint,
-		"network": NewNetworkFingerprint,
-		"nomad":   NewNomadFingerprint,
-		"signal":  NewSignalFingerprint,
-		"storage": NewStorageFingerprint,
-		"vault":   NewVaultFingerprint,
+		"arch":      NewArchFingerprint,
+		"cache":     NewCacheFingerprint,
+		"consul":    NewConsulFingerprint,
+		"cpu":       NewCPUFingerprint,
+		"csi":       NewCSIFingerprint,
+		"host":      NewHostFingerprint,
+		"host_dir":  NewHostDirFingerprint,
+		"hsm":       NewHSMFingerprint,
+		"memory":    NewMemoryFingerprint,
+		"network":   NewNetworkFingerprint,
+		"nomad":     NewNomadFingerprint,
+		"signal":    NewSignalFingerprint,
+		"socket":    NewSocketFingerprint,
+		"storage":   NewStorageFingerprint,
+		"telemetry": NewTelemetryFingerprint,
+		"timezone":  NewTimezoneFingerprint,
+		"vault":     NewVaultFingerprint,
+		"windows":   NewWindowsFingerprint,
 	}
 
 	// envFingerprinters contains the fingerprints that are environment specific.
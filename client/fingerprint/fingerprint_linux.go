@@ -1,5 +1,28 @@
 package fingerprint
 
 func initPlatformFingerprints(fps map[string]Factory) {
+	fps["block_scheduler"] = NewBlockSchedulerFingerprint
+	fps["capabilities"] = NewCapabilitiesFingerprint
 	fps["cgroup"] = NewCGroupFingerprint
+	fps["clocksource"] = NewClocksourceFingerprint
+	fps["conntrack"] = NewConntrackFingerprint
+	fps["container"] = NewContainerFingerprint
+	fps["data_dir"] = NewDataDirFingerprint
+	fps["dmi"] = NewDMIFingerprint
+	fps["gpu_device"] = NewGPUDeviceFingerprint
+	fps["host_volume"] = NewHostVolumeFingerprint
+	fps["hugepage"] = NewHugePageFingerprint
+	fps["inotify"] = NewInotifyFingerprint
+	fps["kernel_cmdline"] = NewKernelCmdlineFingerprint
+	fps["locale"] = NewLocaleFingerprint
+	fps["memory_overcommit"] = NewMemoryOvercommitFingerprint
+	fps["nvidia"] = NewNvidiaFingerprint
+	fps["nvme"] = NewNVMeFingerprint
+	fps["ports"] = NewPortsFingerprint
+	fps["power"] = NewPowerFingerprint
+	fps["rocm"] = NewROCmFingerprint
+	fps["sysctl"] = NewSysctlFingerprint
+	fps["sysctl_network"] = NewSysctlNetworkFingerprint
+	fps["tmpfs"] = NewTmpfsFingerprint
+	fps["wireguard"] = NewWireGuardFingerprint
 }
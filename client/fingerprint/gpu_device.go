@@ -0,0 +1,74 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// gpuDeviceAttrPrefix is the prefix of the attributes describing GPU
+	// device files.
+	gpuDeviceAttrPrefix = "gpu.device."
+)
+
+var (
+	// nvidiaDeviceRe matches the device files of individual NVIDIA GPUs, which
+	// excludes control devices such as nvidiactl and nvidia-uvm.
+	nvidiaDeviceRe = regexp.MustCompile(`^nvidia\d+$`)
+)
+
+// GPUDeviceFingerprint is used to fingerprint the presence of GPUs from their
+// device files, without relying on vendor tooling.
+type GPUDeviceFingerprint struct {
+	StaticFingerprinter
+	logger *log.Logger
+
+	// devDir is the directory containing the device files
+	devDir string
+}
+
+// NewGPUDeviceFingerprint is used to create a GPU device fingerprint
+func NewGPUDeviceFingerprint(logger *log.Logger) Fingerprint {
+	f := &GPUDeviceFingerprint{
+		logger: logger,
+		devDir: "/dev",
+	}
+	return f
+}
+
+func (f *GPUDeviceFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	// Clear any attributes from the previous fingerprint
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, gpuDeviceAttrPrefix) {
+			delete(node.Attributes, k)
+		}
+	}
+
+	files, err := ioutil.ReadDir(f.devDir)
+	if err != nil {
+		f.logger.Printf("[DEBUG] fingerprint.gpu_device: Error reading %s: %v", f.devDir, err)
+		return false, nil
+	}
+
+	count := 0
+	for _, file := range files {
+		if !nvidiaDeviceRe.MatchString(file.Name()) {
+			continue
+		}
+		node.Attributes[gpuDeviceAttrPrefix+file.Name()+".present"] = "true"
+		count++
+	}
+
+	if count == 0 {
+		return false, nil
+	}
+
+	node.Attributes[gpuDeviceAttrPrefix+"count"] = strconv.Itoa(count)
+	return true, nil
+}
@@ -0,0 +1,73 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func testDevDir(t *testing.T, files ...string) string {
+	dir, err := ioutil.TempDir("", "dev")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for _, name := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestGPUDeviceFingerprint(t *testing.T) {
+	dir := testDevDir(t, "null", "nvidia0", "nvidia1", "nvidiactl", "nvidia-uvm")
+	defer os.RemoveAll(dir)
+
+	f := &GPUDeviceFingerprint{
+		logger: testLogger(),
+		devDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"gpu.device.nvidia2.present": "true",
+		},
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "gpu.device.count", "2")
+	assertNodeAttributeEquals(t, node, "gpu.device.nvidia0.present", "true")
+	assertNodeAttributeEquals(t, node, "gpu.device.nvidia1.present", "true")
+	for _, k := range []string{"gpu.device.nvidia2.present", "gpu.device.nvidiactl.present"} {
+		if a, ok := node.Attributes[k]; ok {
+			t.Fatalf("unexpected attribute %s found, %s", k, a)
+		}
+	}
+}
+
+func TestGPUDeviceFingerprint_NoDevices(t *testing.T) {
+	dir := testDevDir(t, "null", "zero")
+	defer os.RemoveAll(dir)
+
+	f := &GPUDeviceFingerprint{
+		logger: testLogger(),
+		devDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 0 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
@@ -1,8 +1,12 @@
 package fingerprint
 
 import (
+	"fmt"
 	"log"
+	"net"
+	"os/exec"
 	"runtime"
+	"strings"
 
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -12,12 +16,50 @@ import (
 // HostFingerprint is used to fingerprint the host
 type HostFingerprint struct {
 	StaticFingerprinter
-	logger *log.Logger
+	logger       *log.Logger
+	fqdnResolver FQDNResolver
+}
+
+// An interface to isolate the resolution of the host's fully-qualified domain
+// name. This facilitates testing where we can return canned names.
+type FQDNResolver interface {
+	FQDN(hostname string) (string, error)
+}
+
+// Implements the resolver using "hostname -f", falling back to a reverse DNS
+// lookup of the host's addresses.
+type DefaultFQDNResolver struct {
+}
+
+func (d *DefaultFQDNResolver) FQDN(hostname string) (string, error) {
+	if path, err := exec.LookPath("hostname"); err == nil {
+		if out, err := exec.Command(path, "-f").Output(); err == nil {
+			if fqdn := strings.TrimSpace(string(out)); fqdn != "" {
+				return fqdn, nil
+			}
+		}
+	}
+
+	addrs, err := net.LookupHost(hostname)
+	if err != nil {
+		return "", err
+	}
+	for _, addr := range addrs {
+		names, err := net.LookupAddr(addr)
+		if err != nil || len(names) == 0 {
+			continue
+		}
+		return strings.TrimSuffix(names[0], "."), nil
+	}
+	return "", fmt.Errorf("no reverse DNS entry found for %q", hostname)
 }
 
 // NewHostFingerprint is used to create a Host fingerprint
 func NewHostFingerprint(logger *log.Logger) Fingerprint {
-	f := &HostFingerprint{logger: logger}
+	f := &HostFingerprint{
+		logger:       logger,
+		fqdnResolver: &DefaultFQDNResolver{},
+	}
 	return f
 }
 
@@ -35,6 +77,18 @@ func (f *HostFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (b
 	node.Attributes["kernel.version"] = hostInfo.KernelVersion
 
 	node.Attributes["unique.hostname"] = hostInfo.Hostname
+	node.Attributes["unique.hostname.fqdn"] = f.fqdn(hostInfo.Hostname)
 
 	return true, nil
 }
+
+// fqdn returns the fully-qualified domain name of the host, or the short
+// hostname if it can't be resolved.
+func (f *HostFingerprint) fqdn(hostname string) string {
+	fqdn, err := f.fqdnResolver.FQDN(hostname)
+	if err != nil || fqdn == "" || fqdn == "localhost" {
+		f.logger.Printf("[DEBUG] fingerprint.host: Unable to resolve FQDN of %q, using hostname: %v", hostname, err)
+		return hostname
+	}
+	return fqdn
+}
@@ -1,6 +1,7 @@
 package fingerprint
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/hashicorp/nomad/client/config"
@@ -21,7 +22,38 @@ func TestHostFingerprint(t *testing.T) {
 	}
 
 	// Host info
-	for _, key := range []string{"os.name", "os.version", "unique.hostname", "kernel.name"} {
+	for _, key := range []string{"os.name", "os.version", "unique.hostname", "unique.hostname.fqdn", "kernel.name"} {
 		assertNodeAttributeContains(t, node, key)
 	}
 }
+
+// A fake FQDN resolver that returns a canned name
+type FQDNResolverMock struct {
+	fqdn string
+	err  error
+}
+
+func (r *FQDNResolverMock) FQDN(hostname string) (string, error) {
+	return r.fqdn, r.err
+}
+
+func TestHostFingerprint_FQDN(t *testing.T) {
+	f := &HostFingerprint{
+		logger:       testLogger(),
+		fqdnResolver: &FQDNResolverMock{fqdn: "client-1.dc1.example.com"},
+	}
+	if fqdn := f.fqdn("client-1"); fqdn != "client-1.dc1.example.com" {
+		t.Fatalf("bad fqdn: %q", fqdn)
+	}
+
+	// Fall back to the short hostname when resolution fails
+	f.fqdnResolver = &FQDNResolverMock{err: fmt.Errorf("no such host")}
+	if fqdn := f.fqdn("client-1"); fqdn != "client-1" {
+		t.Fatalf("expected fallback to hostname; got %q", fqdn)
+	}
+
+	f.fqdnResolver = &FQDNResolverMock{}
+	if fqdn := f.fqdn("client-1"); fqdn != "client-1" {
+		t.Fatalf("expected fallback to hostname; got %q", fqdn)
+	}
+}
@@ -0,0 +1,88 @@
+package fingerprint
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	client "github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// hostVolumeAttrPrefix is the prefix of the attributes describing host
+	// volumes
+	hostVolumeAttrPrefix = "storage.host-volume."
+
+	// hostVolumePathsOption is the client option listing the host volumes to
+	// fingerprint as comma separated name=path pairs.
+	hostVolumePathsOption = "fingerprint.host_volume.paths"
+
+	// hostVolumeInterval is the interval at which host volumes are
+	// fingerprinted so that the free space stays current.
+	hostVolumeInterval = 15 * time.Second
+)
+
+// HostVolumeFingerprint is used to fingerprint the free space of the host
+// volumes configured on the client, which can fill independently of the data
+// dir.
+type HostVolumeFingerprint struct {
+	logger  *log.Logger
+	statter FilesystemStatter
+}
+
+// NewHostVolumeFingerprint is used to create a host volume fingerprint
+func NewHostVolumeFingerprint(logger *log.Logger) Fingerprint {
+	f := &HostVolumeFingerprint{
+		logger:  logger,
+		statter: &DefaultFilesystemStatter{},
+	}
+	return f
+}
+
+func (f *HostVolumeFingerprint) Fingerprint(cfg *client.Config, node *structs.Node) (bool, error) {
+	// Clear any attributes from the previous fingerprint
+	uniquePrefix := structs.UniqueNamespace(hostVolumeAttrPrefix)
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, hostVolumeAttrPrefix) || strings.HasPrefix(k, uniquePrefix) {
+			delete(node.Attributes, k)
+		}
+	}
+
+	volumes, err := parseHostVolumes(cfg.Read(hostVolumePathsOption))
+	if err != nil {
+		return false, err
+	}
+
+	applies := false
+	for name, path := range volumes {
+		_, free, err := f.statter.Statfs(path)
+		if err != nil {
+			f.logger.Printf("[WARN] fingerprint.host_volume: Error calling statfs on %s for volume %s: %v", path, name, err)
+			continue
+		}
+
+		node.Attributes[structs.UniqueNamespace(hostVolumeAttrPrefix+name+".free-mb")] = strconv.FormatUint(free/bytesPerMegabyte, 10)
+		applies = true
+	}
+
+	return applies, nil
+}
+
+// parseHostVolumes parses the value of the host volume paths option into the
+// path of each volume by name.
+func parseHostVolumes(value string) (map[string]string, error) {
+	return parseNamedValues(hostVolumePathsOption, value, "path", func(path string) error {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("%q is not absolute", path)
+		}
+		return nil
+	})
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *HostVolumeFingerprint) Periodic() (bool, time.Duration) {
+	return true, hostVolumeInterval
+}
@@ -0,0 +1,81 @@
+package fingerprint
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestHostVolumeFingerprint(t *testing.T) {
+	f := &HostVolumeFingerprint{
+		logger: testLogger(),
+		statter: FilesystemStatterMock{
+			"/opt/data":    {100 * bytesPerMegabyte, 40 * bytesPerMegabyte},
+			"/var/lib/app": {2048 * bytesPerMegabyte, 0},
+		},
+	}
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"unique.storage.host-volume.removed.free-mb": "10",
+		},
+	}
+	cfg := &config.Config{
+		Options: map[string]string{
+			"fingerprint.host_volume.paths": "data=/opt/data, app=/var/lib/app, missing=/srv/missing",
+		},
+	}
+
+	ok, err := f.Fingerprint(cfg, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("should apply")
+	}
+	assertNodeAttributeEquals(t, node, "unique.storage.host-volume.data.free-mb", "40")
+	assertNodeAttributeEquals(t, node, "unique.storage.host-volume.app.free-mb", "0")
+	for _, key := range []string{"unique.storage.host-volume.missing.free-mb", "unique.storage.host-volume.removed.free-mb"} {
+		if a, ok := node.Attributes[key]; ok {
+			t.Fatalf("unexpected attribute %s found, %s", key, a)
+		}
+	}
+
+	// Without any volumes configured the fingerprinter doesn't apply
+	ok, err = f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 0 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
+
+func TestHostVolumeFingerprint_InvalidPaths(t *testing.T) {
+	f := &HostVolumeFingerprint{
+		logger:  testLogger(),
+		statter: FilesystemStatterMock{},
+	}
+
+	for _, paths := range []string{
+		"data",
+		"my data=/opt/data",
+		"data=opt/data",
+		"data=/opt/data,data=/srv/data",
+	} {
+		node := &structs.Node{
+			Attributes: make(map[string]string),
+		}
+		cfg := &config.Config{
+			Options: map[string]string{
+				"fingerprint.host_volume.paths": paths,
+			},
+		}
+		if _, err := f.Fingerprint(cfg, node); err == nil {
+			t.Fatalf("expected error for paths %q", paths)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	client "github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// hostDirAttrPrefix is the prefix of the attributes recording whether the
+	// configured host directories are present and writable.
+	hostDirAttrPrefix = "host.dir."
+
+	// hostDirPathsOption is the client option listing the host directories to
+	// fingerprint.
+	hostDirPathsOption = "fingerprint.host_dir.paths"
+
+	// hostDirInterval is the interval at which the directories are
+	// fingerprinted as they are created, removed and remounted independently
+	// of the client.
+	hostDirInterval = 30 * time.Second
+)
+
+// HostDirFingerprint is used to fingerprint whether the configured host
+// directories, such as those bind mounted into tasks, are present and
+// writable.
+type HostDirFingerprint struct {
+	logger *log.Logger
+}
+
+// NewHostDirFingerprint is used to create a host directory fingerprint
+func NewHostDirFingerprint(logger *log.Logger) Fingerprint {
+	f := &HostDirFingerprint{logger: logger}
+	return f
+}
+
+func (f *HostDirFingerprint) Fingerprint(cfg *client.Config, node *structs.Node) (bool, error) {
+	// Clear any attributes from the previous fingerprint
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, hostDirAttrPrefix) {
+			delete(node.Attributes, k)
+		}
+	}
+
+	paths := cfg.ReadStringListToMap(hostDirPathsOption)
+	if len(paths) == 0 {
+		return false, nil
+	}
+
+	for path := range paths {
+		present, writable := false, false
+		if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+			present = true
+			writable = f.writable(path)
+		}
+
+		prefix := hostDirAttrPrefix + sanitizeHostPath(path)
+		node.Attributes[prefix+".present"] = strconv.FormatBool(present)
+		node.Attributes[prefix+".writable"] = strconv.FormatBool(writable)
+	}
+	return true, nil
+}
+
+// writable returns whether a file can be created in the directory. Creating a
+// file catches read-only mounts and ownership alike, which checking the
+// permission bits of the directory doesn't.
+func (f *HostDirFingerprint) writable(path string) bool {
+	file, err := ioutil.TempFile(path, ".nomad-fingerprint")
+	if err != nil {
+		f.logger.Printf("[DEBUG] fingerprint.host_dir: %s is not writable: %v", path, err)
+		return false
+	}
+	file.Close()
+	if err := os.Remove(file.Name()); err != nil {
+		f.logger.Printf("[WARN] fingerprint.host_dir: failed to remove %s: %v", file.Name(), err)
+	}
+	return true
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *HostDirFingerprint) Periodic() (bool, time.Duration) {
+	return true, hostDirInterval
+}
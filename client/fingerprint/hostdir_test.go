@@ -0,0 +1,101 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestHostDirFingerprint(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Directory permissions are not supported")
+	}
+
+	dir, err := ioutil.TempDir("", "hostdir")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := filepath.Join(dir, "opt", "data")
+	if err := os.MkdirAll(data, 0755); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	readOnly := filepath.Join(dir, "var", "lib", "app")
+	if err := os.MkdirAll(readOnly, 0755); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := os.Chmod(readOnly, 0555); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.Chmod(readOnly, 0755)
+
+	// A regular file is not a directory
+	file := filepath.Join(dir, "opt", "file")
+	if err := ioutil.WriteFile(file, nil, 0644); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	missing := filepath.Join(dir, "srv")
+
+	f := NewHostDirFingerprint(testLogger())
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"host.dir.stale.present": "true",
+		},
+	}
+	cfg := &config.Config{
+		Options: map[string]string{
+			"fingerprint.host_dir.paths": strings.Join([]string{data, readOnly, file, missing}, ","),
+		},
+	}
+
+	ok, err := f.Fingerprint(cfg, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("should apply")
+	}
+	assertNodeAttributeEquals(t, node, "host.dir."+sanitizeHostPath(data)+".present", "true")
+	assertNodeAttributeEquals(t, node, "host.dir."+sanitizeHostPath(data)+".writable", "true")
+	assertNodeAttributeEquals(t, node, "host.dir."+sanitizeHostPath(readOnly)+".present", "true")
+	assertNodeAttributeEquals(t, node, "host.dir."+sanitizeHostPath(file)+".present", "false")
+	assertNodeAttributeEquals(t, node, "host.dir."+sanitizeHostPath(file)+".writable", "false")
+	assertNodeAttributeEquals(t, node, "host.dir."+sanitizeHostPath(missing)+".present", "false")
+	assertNodeAttributeEquals(t, node, "host.dir."+sanitizeHostPath(missing)+".writable", "false")
+	if _, ok := node.Attributes["host.dir.stale.present"]; ok {
+		t.Fatalf("expected stale directory attribute to be removed")
+	}
+
+	// Root bypasses the permissions of the read-only directory
+	if os.Geteuid() != 0 {
+		assertNodeAttributeEquals(t, node, "host.dir."+sanitizeHostPath(readOnly)+".writable", "false")
+	}
+
+	// Probing writability leaves nothing behind
+	files, err := ioutil.ReadDir(data)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("unexpected files left in %s: %v", data, files)
+	}
+
+	// The fingerprinter doesn't apply without any configured directories
+	ok, err = f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 0 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
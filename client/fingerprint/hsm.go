@@ -0,0 +1,89 @@
+package fingerprint
+
+import (
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// hsmAttrPrefix is the prefix of the HSM attributes
+	hsmAttrPrefix = "hardware.hsm."
+
+	// hsmPathsOption is the client option listing the PKCS#11 modules and HSM
+	// device files to fingerprint.
+	hsmPathsOption = "fingerprint.hsm.paths"
+
+	// hsmInterval is the interval at which the HSMs are fingerprinted as USB
+	// and network HSMs can be attached and detached at runtime.
+	hsmInterval = 30 * time.Second
+)
+
+// HSMFingerprint is used to fingerprint whether the node has a hardware
+// security module, detected by the presence of the configured PKCS#11 modules,
+// such as /usr/lib/softhsm/libsofthsm2.so, or HSM device files, such as
+// /dev/tpm0.
+type HSMFingerprint struct {
+	logger *log.Logger
+
+	// stat returns the file info of the path, as os.Stat does
+	stat func(path string) (os.FileInfo, error)
+}
+
+// NewHSMFingerprint is used to create an HSM fingerprint
+func NewHSMFingerprint(logger *log.Logger) Fingerprint {
+	f := &HSMFingerprint{
+		logger: logger,
+		stat:   os.Stat,
+	}
+	return f
+}
+
+func (f *HSMFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	// Clear any attributes from the previous fingerprint
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, hsmAttrPrefix) {
+			delete(node.Attributes, k)
+		}
+	}
+
+	paths := cfg.ReadStringListToMap(hsmPathsOption)
+	if len(paths) == 0 {
+		return false, nil
+	}
+
+	var present []string
+	for path := range paths {
+		fi, err := f.stat(path)
+		if err != nil {
+			f.logger.Printf("[DEBUG] fingerprint.hsm: %s not found: %v", path, err)
+			continue
+		}
+
+		// PKCS#11 modules are shared libraries and HSMs are character devices
+		if fi.IsDir() {
+			f.logger.Printf("[WARN] fingerprint.hsm: %s is a directory", path)
+			continue
+		}
+		present = append(present, path)
+	}
+	sort.Strings(present)
+
+	node.Attributes[hsmAttrPrefix+"present"] = strconv.FormatBool(len(present) != 0)
+	node.Attributes[hsmAttrPrefix+"count"] = strconv.Itoa(len(present))
+	if len(present) != 0 {
+		node.Attributes[hsmAttrPrefix+"paths"] = strings.Join(present, ",")
+	}
+	return true, nil
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *HSMFingerprint) Periodic() (bool, time.Duration) {
+	return true, hsmInterval
+}
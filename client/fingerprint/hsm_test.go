@@ -0,0 +1,91 @@
+package fingerprint
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// testFileInfo is a fake file info of the given mode
+type testFileInfo struct {
+	name string
+	mode os.FileMode
+}
+
+func (fi *testFileInfo) Name() string       { return fi.name }
+func (fi *testFileInfo) Size() int64        { return 0 }
+func (fi *testFileInfo) Mode() os.FileMode  { return fi.mode }
+func (fi *testFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi *testFileInfo) IsDir() bool        { return fi.mode.IsDir() }
+func (fi *testFileInfo) Sys() interface{}   { return nil }
+
+// testStat returns a stat function finding only the given files
+func testStat(files map[string]os.FileMode) func(string) (os.FileInfo, error) {
+	return func(path string) (os.FileInfo, error) {
+		mode, ok := files[path]
+		if !ok {
+			return nil, &os.PathError{Op: "stat", Path: path, Err: os.ErrNotExist}
+		}
+		return &testFileInfo{name: path, mode: mode}, nil
+	}
+}
+
+func TestHSMFingerprint(t *testing.T) {
+	f := &HSMFingerprint{
+		logger: testLogger(),
+		stat: testStat(map[string]os.FileMode{
+			"/usr/lib/softhsm/libsofthsm2.so": 0755,
+			"/dev/tpm0":                       os.ModeDevice | os.ModeCharDevice | 0660,
+			"/opt/hsm":                        os.ModeDir | 0755,
+		}),
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+	cfg := &config.Config{
+		Options: map[string]string{
+			"fingerprint.hsm.paths": "/dev/tpm0,/usr/lib/softhsm/libsofthsm2.so,/dev/hsm0,/opt/hsm",
+		},
+	}
+
+	ok, err := f.Fingerprint(cfg, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("should apply")
+	}
+	assertNodeAttributeEquals(t, node, "hardware.hsm.present", "true")
+	assertNodeAttributeEquals(t, node, "hardware.hsm.count", "2")
+	assertNodeAttributeEquals(t, node, "hardware.hsm.paths", "/dev/tpm0,/usr/lib/softhsm/libsofthsm2.so")
+
+	// Once the HSM is detached it is no longer present
+	f.stat = testStat(map[string]os.FileMode{})
+	ok, err = f.Fingerprint(cfg, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("should apply")
+	}
+	assertNodeAttributeEquals(t, node, "hardware.hsm.present", "false")
+	assertNodeAttributeEquals(t, node, "hardware.hsm.count", "0")
+	if a, ok := node.Attributes["hardware.hsm.paths"]; ok {
+		t.Fatalf("unexpected attribute hardware.hsm.paths found, %s", a)
+	}
+
+	// Without any paths configured the fingerprinter doesn't apply
+	ok, err = f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if a, ok := node.Attributes["hardware.hsm.present"]; ok {
+		t.Fatalf("unexpected attribute hardware.hsm.present found, %s", a)
+	}
+}
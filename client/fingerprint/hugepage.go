@@ -0,0 +1,58 @@
+package fingerprint
+
+import (
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// hugePageInterval is the interval at which the transparent huge page
+	// setting is fingerprinted as it can be changed at runtime.
+	hugePageInterval = 30 * time.Second
+
+	hugePageAttr = "memory.transparent-hugepage"
+)
+
+var (
+	// hugePageModeRe extracts the selected mode from the sysfs setting, which
+	// brackets it among the available modes: always [madvise] never
+	hugePageModeRe = regexp.MustCompile(`\[(\w+)\]`)
+)
+
+// HugePageFingerprint is used to fingerprint the transparent huge page setting
+type HugePageFingerprint struct {
+	logger *log.Logger
+
+	// enabledFile is the sysfs file holding the transparent huge page setting
+	enabledFile string
+}
+
+// NewHugePageFingerprint is used to create a transparent huge page fingerprint
+func NewHugePageFingerprint(logger *log.Logger) Fingerprint {
+	f := &HugePageFingerprint{
+		logger:      logger,
+		enabledFile: "/sys/kernel/mm/transparent_hugepage/enabled",
+	}
+	return f
+}
+
+func (f *HugePageFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	m := hugePageModeRe.FindStringSubmatch(readSysfsValue(f.enabledFile))
+	if m == nil {
+		// The kernel may be built without transparent huge page support
+		delete(node.Attributes, hugePageAttr)
+		return false, nil
+	}
+
+	node.Attributes[hugePageAttr] = m[1]
+	return true, nil
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *HugePageFingerprint) Periodic() (bool, time.Duration) {
+	return true, hugePageInterval
+}
@@ -0,0 +1,60 @@
+package fingerprint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestHugePageFingerprint(t *testing.T) {
+	cases := []struct {
+		content  string
+		expected string
+	}{
+		{"[always] madvise never\n", "always"},
+		{"always [madvise] never\n", "madvise"},
+		{"always madvise [never]\n", "never"},
+	}
+
+	for _, c := range cases {
+		dir := writeSysfsTree(t, map[string]string{"enabled": c.content})
+		defer os.RemoveAll(dir)
+
+		f := &HugePageFingerprint{
+			logger:      testLogger(),
+			enabledFile: filepath.Join(dir, "enabled"),
+		}
+		node := &structs.Node{
+			Attributes: make(map[string]string),
+		}
+
+		assertFingerprintOK(t, f, node)
+		assertNodeAttributeEquals(t, node, "memory.transparent-hugepage", c.expected)
+	}
+}
+
+func TestHugePageFingerprint_Unsupported(t *testing.T) {
+	f := &HugePageFingerprint{
+		logger:      testLogger(),
+		enabledFile: filepath.Join(os.TempDir(), "nomad-thp-missing"),
+	}
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"memory.transparent-hugepage": "always",
+		},
+	}
+
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if a, ok := node.Attributes["memory.transparent-hugepage"]; ok {
+		t.Fatalf("unexpected attribute found, %s", a)
+	}
+}
@@ -0,0 +1,61 @@
+package fingerprint
+
+import (
+	"log"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// inotifyInterval is the interval at which the inotify limit is
+	// fingerprinted as it can be changed at runtime.
+	inotifyInterval = 30 * time.Second
+
+	inotifyMaxUserWatchesAttr = "kernel.inotify.max-user-watches"
+
+	// inotifyMaxUserWatchesSysctl is the path of the sysctl under /proc/sys
+	inotifyMaxUserWatchesSysctl = "fs/inotify/max_user_watches"
+)
+
+// InotifyFingerprint is used to fingerprint the maximum number of inotify
+// watches per user, which file watching tasks such as log shippers can
+// exhaust.
+type InotifyFingerprint struct {
+	logger *log.Logger
+
+	// procSysDir is the directory the sysctls are read from
+	procSysDir string
+}
+
+// NewInotifyFingerprint is used to create an inotify fingerprint
+func NewInotifyFingerprint(logger *log.Logger) Fingerprint {
+	f := &InotifyFingerprint{
+		logger:     logger,
+		procSysDir: "/proc/sys",
+	}
+	return f
+}
+
+func (f *InotifyFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	value := readSysfsValue(filepath.Join(f.procSysDir, inotifyMaxUserWatchesSysctl))
+	max, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		if value != "" {
+			f.logger.Printf("[WARN] fingerprint.inotify: Unknown fs.inotify.max_user_watches value %q", value)
+		}
+		delete(node.Attributes, inotifyMaxUserWatchesAttr)
+		return false, nil
+	}
+
+	node.Attributes[inotifyMaxUserWatchesAttr] = strconv.FormatUint(max, 10)
+	return true, nil
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *InotifyFingerprint) Periodic() (bool, time.Duration) {
+	return true, inotifyInterval
+}
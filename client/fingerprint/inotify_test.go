@@ -0,0 +1,55 @@
+package fingerprint
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestInotifyFingerprint(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{
+		"fs/inotify/max_user_watches": "524288\n",
+	})
+	defer os.RemoveAll(dir)
+
+	f := &InotifyFingerprint{
+		logger:     testLogger(),
+		procSysDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "kernel.inotify.max-user-watches", "524288")
+}
+
+func TestInotifyFingerprint_Unsupported(t *testing.T) {
+	for _, files := range []map[string]string{{}, {"fs/inotify/max_user_watches": "lots\n"}} {
+		dir := writeSysfsTree(t, files)
+		defer os.RemoveAll(dir)
+
+		f := &InotifyFingerprint{
+			logger:     testLogger(),
+			procSysDir: dir,
+		}
+		node := &structs.Node{
+			Attributes: map[string]string{
+				"kernel.inotify.max-user-watches": "8192",
+			},
+		}
+
+		ok, err := f.Fingerprint(&config.Config{}, node)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if ok {
+			t.Fatalf("should not apply")
+		}
+		if a, ok := node.Attributes["kernel.inotify.max-user-watches"]; ok {
+			t.Fatalf("unexpected attribute kernel.inotify.max-user-watches found, %s", a)
+		}
+	}
+}
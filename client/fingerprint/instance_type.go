@@ -0,0 +1,155 @@
+package fingerprint
+
+import (
+	"regexp"
+	"strconv"
+)
+
+const (
+	// Cloud providers whose instance types can be parsed
+	providerAWS   = "aws"
+	providerAzure = "azure"
+	providerGCE   = "gce"
+)
+
+var (
+	// gceCustomTypeRe matches GCE custom machine types such as custom-4-8192
+	// or n2-custom-4-8192-ext, capturing the vCPUs and memory in MB.
+	gceCustomTypeRe = regexp.MustCompile(`^(?:[a-z0-9]+-)?custom-(\d+)-(\d+)(?:-ext)?$`)
+
+	// gcePredefinedTypeRe matches GCE predefined machine types such as
+	// n1-standard-4, capturing the family, class and vCPUs.
+	gcePredefinedTypeRe = regexp.MustCompile(`^([a-z0-9]+)-(standard|highmem|highcpu)-(\d+)$`)
+
+	// awsTypeRe matches AWS instance types such as m5.large or c5d.4xlarge,
+	// capturing the family, the size multiplier and the size.
+	awsTypeRe = regexp.MustCompile(`^([a-z]+\d+[a-z]*)\.(\d*)(large|xlarge)$`)
+
+	// azureTypeRe matches Azure VM sizes such as Standard_D4s_v3, capturing
+	// the family letters, the vCPUs and the version.
+	azureTypeRe = regexp.MustCompile(`^(?:Standard|Basic)_([A-Z]+)(\d+)[a-z]*(_v\d+)?$`)
+
+	// gceMemoryPerCPU is the memory in MB per vCPU of the n1 machine classes
+	gceMemoryPerCPU = map[string]int{
+		"standard": 3840,
+		"highmem":  6656,
+		"highcpu":  921,
+	}
+
+	// awsMemoryPerCPU is the memory in MB per vCPU of the AWS families whose
+	// large size has 2 vCPUs, every multiple of xlarge has 4 and whose memory
+	// scales linearly with their size. Families that don't, such as the older
+	// generations or c5n, are not listed.
+	awsMemoryPerCPU = map[string]int{
+		"c5":  2048,
+		"c5a": 2048,
+		"c5d": 2048,
+		"c6a": 2048,
+		"c6g": 2048,
+		"c6i": 2048,
+		"c7g": 2048,
+		"c7i": 2048,
+		"m4":  4096,
+		"m5":  4096,
+		"m5a": 4096,
+		"m5d": 4096,
+		"m5n": 4096,
+		"m6a": 4096,
+		"m6g": 4096,
+		"m6i": 4096,
+		"m7g": 4096,
+		"m7i": 4096,
+		"r5":  8192,
+		"r5a": 8192,
+		"r5d": 8192,
+		"r5n": 8192,
+		"r6a": 8192,
+		"r6g": 8192,
+		"r6i": 8192,
+		"r7g": 8192,
+		"r7i": 8192,
+	}
+
+	// azureMemoryPerCPU is the memory in MB per vCPU of the Azure families
+	// and versions whose memory scales linearly with their size.
+	azureMemoryPerCPU = map[string]int{
+		"D_v3": 4096,
+		"D_v4": 4096,
+		"D_v5": 4096,
+		"E_v3": 8192,
+		"E_v4": 8192,
+		"E_v5": 8192,
+		"F_v2": 2048,
+	}
+)
+
+// parseInstanceType returns the vCPUs and memory in MB of an instance type of
+// the given provider. Values that can not be derived from the name are
+// returned as zero and ok is false if the name was not recognized at all.
+func parseInstanceType(provider, name string) (cpus, memoryMB int, ok bool) {
+	switch provider {
+	case providerGCE:
+		return parseGCEMachineType(name)
+	case providerAWS:
+		return parseAWSInstanceType(name)
+	case providerAzure:
+		return parseAzureVMSize(name)
+	default:
+		return 0, 0, false
+	}
+}
+
+func parseGCEMachineType(name string) (int, int, bool) {
+	if m := gceCustomTypeRe.FindStringSubmatch(name); m != nil {
+		cpus, _ := strconv.Atoi(m[1])
+		memoryMB, _ := strconv.Atoi(m[2])
+		return cpus, memoryMB, true
+	}
+
+	if m := gcePredefinedTypeRe.FindStringSubmatch(name); m != nil {
+		cpus, _ := strconv.Atoi(m[3])
+
+		// Only the n1 family has a fixed amount of memory per vCPU
+		if m[1] != "n1" {
+			return cpus, 0, true
+		}
+		return cpus, cpus * gceMemoryPerCPU[m[2]], true
+	}
+
+	return 0, 0, false
+}
+
+func parseAWSInstanceType(name string) (int, int, bool) {
+	m := awsTypeRe.FindStringSubmatch(name)
+	if m == nil {
+		return 0, 0, false
+	}
+	memoryPerCPU, ok := awsMemoryPerCPU[m[1]]
+	if !ok {
+		return 0, 0, false
+	}
+
+	// A large instance has 2 vCPUs and every multiple of xlarge has 4
+	cpus := 2
+	if m[3] == "xlarge" {
+		multiplier := 1
+		if m[2] != "" {
+			multiplier, _ = strconv.Atoi(m[2])
+		}
+		cpus = 4 * multiplier
+	} else if m[2] != "" {
+		return 0, 0, false
+	}
+
+	return cpus, cpus * memoryPerCPU, true
+}
+
+func parseAzureVMSize(name string) (int, int, bool) {
+	m := azureTypeRe.FindStringSubmatch(name)
+	if m == nil {
+		return 0, 0, false
+	}
+
+	cpus, _ := strconv.Atoi(m[2])
+	return cpus, cpus * azureMemoryPerCPU[m[1]+m[3]], true
+}
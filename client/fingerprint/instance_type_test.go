@@ -0,0 +1,42 @@
+package fingerprint
+
+import "testing"
+
+func TestParseInstanceType(t *testing.T) {
+	cases := []struct {
+		provider string
+		name     string
+		cpus     int
+		memoryMB int
+		ok       bool
+	}{
+		{providerGCE, "custom-4-8192", 4, 8192, true},
+		{providerGCE, "n2-custom-8-65536-ext", 8, 65536, true},
+		{providerGCE, "n1-standard-4", 4, 15360, true},
+		{providerGCE, "n2-highmem-8", 8, 0, true},
+		{providerGCE, "f1-micro", 0, 0, false},
+		{providerAWS, "m5.large", 2, 8192, true},
+		{providerAWS, "c5d.4xlarge", 16, 32768, true},
+		{providerAWS, "m4.10xlarge", 40, 163840, true},
+		{providerAWS, "r6g.2xlarge", 8, 65536, true},
+		{providerAWS, "t3.xlarge", 0, 0, false},
+		{providerAWS, "t2.micro", 0, 0, false},
+		{providerAWS, "m1.large", 0, 0, false},
+		{providerAWS, "m3.2xlarge", 0, 0, false},
+		{providerAWS, "c4.8xlarge", 0, 0, false},
+		{providerAWS, "c5n.large", 0, 0, false},
+		{providerAzure, "Standard_D4s_v3", 4, 16384, true},
+		{providerAzure, "Standard_E16_v4", 16, 131072, true},
+		{providerAzure, "Standard_A2", 2, 0, true},
+		{providerAzure, "Large", 0, 0, false},
+		{"openstack", "m1.large", 0, 0, false},
+	}
+
+	for _, c := range cases {
+		cpus, memoryMB, ok := parseInstanceType(c.provider, c.name)
+		if cpus != c.cpus || memoryMB != c.memoryMB || ok != c.ok {
+			t.Fatalf("%s %s: expected (%d, %d, %v), got (%d, %d, %v)",
+				c.provider, c.name, c.cpus, c.memoryMB, c.ok, cpus, memoryMB, ok)
+		}
+	}
+}
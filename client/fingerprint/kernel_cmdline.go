@@ -0,0 +1,85 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// cmdlineAttr is the prefix of the attributes of the parameters of the
+	// kernel command line. The raw command line is fingerprinted as its unique
+	// attribute.
+	cmdlineAttr = "kernel.cmdline"
+
+	// cmdlineFile holds the parameters the kernel was booted with
+	cmdlineFile = "cmdline"
+)
+
+var (
+	// cmdlineParamSanitizeRe matches the characters of a parameter name that
+	// are replaced to form its attribute name.
+	cmdlineParamSanitizeRe = regexp.MustCompile(`[^a-zA-Z0-9_.\-]+`)
+)
+
+// KernelCmdlineFingerprint is used to fingerprint the parameters the kernel
+// was booted with, such as isolcpus or hugepages.
+type KernelCmdlineFingerprint struct {
+	StaticFingerprinter
+	logger *log.Logger
+
+	// procDir is the directory the proc filesystem is mounted at
+	procDir string
+}
+
+// NewKernelCmdlineFingerprint is used to create a kernel command line
+// fingerprint
+func NewKernelCmdlineFingerprint(logger *log.Logger) Fingerprint {
+	f := &KernelCmdlineFingerprint{
+		logger:  logger,
+		procDir: "/proc",
+	}
+	return f
+}
+
+func (f *KernelCmdlineFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	// Clear any attributes from the previous fingerprint
+	for k := range node.Attributes {
+		if k == structs.UniqueNamespace(cmdlineAttr) || strings.HasPrefix(k, cmdlineAttr+".") {
+			delete(node.Attributes, k)
+		}
+	}
+
+	raw, err := ioutil.ReadFile(filepath.Join(f.procDir, cmdlineFile))
+	if err != nil {
+		f.logger.Printf("[DEBUG] fingerprint.kernel_cmdline: could not read kernel command line: %v", err)
+		return false, nil
+	}
+
+	cmdline := strings.TrimSpace(string(raw))
+	node.Attributes[structs.UniqueNamespace(cmdlineAttr)] = cmdline
+	for _, param := range parseKernelCmdline(cmdline) {
+		node.Attributes[cmdlineAttr+"."+param+".present"] = "true"
+	}
+	return true, nil
+}
+
+// parseKernelCmdline returns the names of the parameters of the kernel command
+// line, sanitized for use in an attribute name. Values are dropped so that
+// "hugepages=16" and "quiet" are returned as "hugepages" and "quiet".
+func parseKernelCmdline(cmdline string) []string {
+	var params []string
+	for _, field := range strings.Fields(cmdline) {
+		name := strings.SplitN(field, "=", 2)[0]
+		if name == "" {
+			continue
+		}
+		params = append(params, cmdlineParamSanitizeRe.ReplaceAllString(name, "_"))
+	}
+	return params
+}
@@ -0,0 +1,63 @@
+package fingerprint
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestKernelCmdlineFingerprint(t *testing.T) {
+	cmdline := "BOOT_IMAGE=/vmlinuz-5.15.0 root=UUID=1234 ro quiet isolcpus=2-7 nohz_full=2-7 hugepages=16"
+	dir := writeSysfsTree(t, map[string]string{
+		"cmdline": cmdline + "\n",
+	})
+	defer os.RemoveAll(dir)
+
+	f := &KernelCmdlineFingerprint{
+		logger:  testLogger(),
+		procDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"kernel.cmdline.stale.present": "true",
+			"kernel.name":                  "linux",
+		},
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "unique.kernel.cmdline", cmdline)
+	if _, ok := node.Attributes["kernel.cmdline"]; ok {
+		t.Fatalf("expected the raw command line to only be a unique attribute")
+	}
+	for _, param := range []string{"BOOT_IMAGE", "root", "ro", "quiet", "isolcpus", "nohz_full", "hugepages"} {
+		assertNodeAttributeEquals(t, node, "kernel.cmdline."+param+".present", "true")
+	}
+	if _, ok := node.Attributes["kernel.cmdline.stale.present"]; ok {
+		t.Fatalf("expected stale parameter attribute to be removed")
+	}
+	assertNodeAttributeEquals(t, node, "kernel.name", "linux")
+
+	// No-op where the command line can't be read
+	f.procDir = dir + "-missing"
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 1 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
+
+func TestKernelCmdlineFingerprint_Parse(t *testing.T) {
+	params := parseKernelCmdline(`  console=ttyS0,115200  rd.lvm.lv=vg/root  =orphan  a:b  `)
+	expected := []string{"console", "rd.lvm.lv", "a_b"}
+	if !reflect.DeepEqual(params, expected) {
+		t.Fatalf("expected %v, got %v", expected, params)
+	}
+}
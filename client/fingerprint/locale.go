@@ -0,0 +1,105 @@
+package fingerprint
+
+import (
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	localeAttr  = "os.locale"
+	charsetAttr = "os.charset"
+
+	// defaultLocale is the locale used when none of the locale environment
+	// variables are set.
+	defaultLocale = "C"
+)
+
+// localeEnvVars are the environment variables selecting the character set of
+// the locale, in order of precedence.
+var localeEnvVars = []string{"LC_ALL", "LC_CTYPE", "LANG"}
+
+// LocaleFingerprint is used to fingerprint the locale of the client and its
+// character set, which the tasks inherit.
+type LocaleFingerprint struct {
+	StaticFingerprinter
+	logger *log.Logger
+	locale LocaleQuerier
+
+	// getenv is used to look up the locale environment variables
+	getenv func(key string) string
+}
+
+// An interface to isolate calls to locale
+// This facilitates testing where we can return canned output
+type LocaleQuerier interface {
+	// Charmap returns the output of locale charmap
+	Charmap() ([]byte, error)
+}
+
+// Implements the querier which calls locale found in the $PATH
+type DefaultLocaleQuerier struct {
+}
+
+func (d *DefaultLocaleQuerier) Charmap() ([]byte, error) {
+	path, err := exec.LookPath("locale")
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command(path, "charmap").Output()
+}
+
+// NewLocaleFingerprint is used to create a locale fingerprint
+func NewLocaleFingerprint(logger *log.Logger) Fingerprint {
+	f := &LocaleFingerprint{
+		logger: logger,
+		locale: &DefaultLocaleQuerier{},
+		getenv: os.Getenv,
+	}
+	return f
+}
+
+func (f *LocaleFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	locale := defaultLocale
+	for _, key := range localeEnvVars {
+		if v := f.getenv(key); v != "" {
+			locale = v
+			break
+		}
+	}
+	node.Attributes[localeAttr] = locale
+
+	if charset := f.charset(locale); charset != "" {
+		node.Attributes[charsetAttr] = charset
+	} else {
+		delete(node.Attributes, charsetAttr)
+	}
+	return true, nil
+}
+
+// charset returns the character set of the locale, normalized so that UTF-8
+// is always reported as "UTF-8". The codeset of locale names such as
+// en_US.utf8@euro is used if present, otherwise locale charmap is asked.
+func (f *LocaleFingerprint) charset(locale string) string {
+	charset := ""
+	if i := strings.Index(locale, "."); i != -1 {
+		charset = locale[i+1:]
+		if j := strings.Index(charset, "@"); j != -1 {
+			charset = charset[:j]
+		}
+	} else if out, err := f.locale.Charmap(); err == nil {
+		charset = strings.TrimSpace(string(out))
+	} else if _, ok := err.(*exec.Error); !ok {
+		f.logger.Printf("[WARN] fingerprint.locale: Error calling locale: %v", err)
+	}
+
+	switch strings.ToUpper(charset) {
+	case "UTF-8", "UTF8":
+		return "UTF-8"
+	}
+	return charset
+}
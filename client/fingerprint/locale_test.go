@@ -0,0 +1,85 @@
+package fingerprint
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// A fake locale querier that returns canned output
+type LocaleQuerierMock struct {
+	out []byte
+	err error
+}
+
+func (l *LocaleQuerierMock) Charmap() ([]byte, error) {
+	return l.out, l.err
+}
+
+func TestLocaleFingerprint(t *testing.T) {
+	cases := []struct {
+		name    string
+		env     map[string]string
+		charmap *LocaleQuerierMock
+		locale  string
+		charset string
+	}{
+		{
+			name:    "UTF-8",
+			env:     map[string]string{"LANG": "en_US.UTF-8"},
+			charmap: &LocaleQuerierMock{out: []byte("UTF-8\n")},
+			locale:  "en_US.UTF-8",
+			charset: "UTF-8",
+		},
+		{
+			name:    "utf8 codeset with modifier",
+			env:     map[string]string{"LANG": "de_DE.utf8@euro"},
+			charmap: &LocaleQuerierMock{out: []byte("UTF-8\n")},
+			locale:  "de_DE.utf8@euro",
+			charset: "UTF-8",
+		},
+		{
+			name:    "LC_ALL overrides LANG",
+			env:     map[string]string{"LC_ALL": "C", "LANG": "en_US.UTF-8"},
+			charmap: &LocaleQuerierMock{out: []byte("ANSI_X3.4-1968\n")},
+			locale:  "C",
+			charset: "ANSI_X3.4-1968",
+		},
+		{
+			name:    "C locale by default",
+			charmap: &LocaleQuerierMock{out: []byte("ANSI_X3.4-1968\n")},
+			locale:  "C",
+			charset: "ANSI_X3.4-1968",
+		},
+		{
+			name:    "missing locale binary",
+			env:     map[string]string{"LC_CTYPE": "POSIX"},
+			charmap: &LocaleQuerierMock{err: &exec.Error{Name: "locale", Err: exec.ErrNotFound}},
+			locale:  "POSIX",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := &LocaleFingerprint{
+				logger: testLogger(),
+				locale: c.charmap,
+				getenv: func(key string) string { return c.env[key] },
+			}
+			node := &structs.Node{
+				Attributes: map[string]string{
+					"os.charset": "ISO-8859-1",
+				},
+			}
+
+			assertFingerprintOK(t, f, node)
+			assertNodeAttributeEquals(t, node, "os.locale", c.locale)
+			if c.charset != "" {
+				assertNodeAttributeEquals(t, node, "os.charset", c.charset)
+			} else if a, ok := node.Attributes["os.charset"]; ok {
+				t.Fatalf("unexpected attribute os.charset found, %s", a)
+			}
+		})
+	}
+}
@@ -1,8 +1,13 @@
 package fingerprint
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
 	"log"
+	"os/exec"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -12,13 +17,35 @@ import (
 // MemoryFingerprint is used to fingerprint the available memory on the node
 type MemoryFingerprint struct {
 	StaticFingerprinter
-	logger *log.Logger
+	logger    *log.Logger
+	dmidecode DMIDecodeQuerier
+}
+
+// An interface to isolate calls to dmidecode
+// This facilitates testing where we can return canned output
+type DMIDecodeQuerier interface {
+	// QueryMemory returns the output of dmidecode describing the memory
+	// devices.
+	QueryMemory() ([]byte, error)
+}
+
+// Implements the querier which calls dmidecode found in the $PATH
+type DefaultDMIDecodeQuerier struct {
+}
+
+func (d *DefaultDMIDecodeQuerier) QueryMemory() ([]byte, error) {
+	path, err := exec.LookPath("dmidecode")
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command(path, "--type", "memory").Output()
 }
 
 // NewMemoryFingerprint is used to create a Memory fingerprint
 func NewMemoryFingerprint(logger *log.Logger) Fingerprint {
 	f := &MemoryFingerprint{
-		logger: logger,
+		logger:    logger,
+		dmidecode: &DefaultDMIDecodeQuerier{},
 	}
 	return f
 }
@@ -39,5 +66,112 @@ func (f *MemoryFingerprint) Fingerprint(cfg *config.Config, node *structs.Node)
 		node.Resources.MemoryMB = int(memInfo.Total / 1024 / 1024)
 	}
 
+	f.fingerprintMemoryDevices(node)
 	return true, nil
 }
+
+// fingerprintMemoryDevices records the type and speed of the installed memory
+// as reported by dmidecode. dmidecode requires root to read the DMI tables, so
+// the attributes are only set when it is installed and readable.
+func (f *MemoryFingerprint) fingerprintMemoryDevices(node *structs.Node) {
+	delete(node.Attributes, "memory.type")
+	delete(node.Attributes, "memory.speed-mhz")
+
+	if f.dmidecode == nil {
+		return
+	}
+	out, err := f.dmidecode.QueryMemory()
+	if err != nil {
+		if _, ok := err.(*exec.Error); !ok {
+			f.logger.Printf("[DEBUG] fingerprint.memory: Error calling dmidecode: %v", err)
+		}
+		return
+	}
+
+	memType, speed := parseDMIDecodeMemory(out)
+	if memType != "" {
+		node.Attributes["memory.type"] = memType
+	}
+	if speed != 0 {
+		node.Attributes["memory.speed-mhz"] = strconv.Itoa(speed)
+	}
+}
+
+// parseDMIDecodeMemory returns the type and speed of the installed memory
+// devices in the output of dmidecode. The speed is the lowest configured
+// speed of the devices, as the memory runs at the speed of the slowest
+// module, falling back to their rated speed.
+func parseDMIDecodeMemory(out []byte) (string, int) {
+	// Devices look something like:
+	//	Memory Device
+	//		Size: 16 GB
+	//		Type: DDR4
+	//		Speed: 3200 MT/s
+	//		Configured Memory Speed: 2933 MT/s
+	// Empty slots report "No Module Installed" as their size.
+	var memType string
+	var speed int
+
+	var installed bool
+	var devType string
+	var devSpeed, devConfigured int
+	endDevice := func() {
+		if installed {
+			if memType == "" && devType != "" {
+				memType = devType
+			}
+			s := devConfigured
+			if s == 0 {
+				s = devSpeed
+			}
+			if s != 0 && (speed == 0 || s < speed) {
+				speed = s
+			}
+		}
+		installed, devType, devSpeed, devConfigured = false, "", 0, 0
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "Memory Device" {
+			endDevice()
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, value := parts[0], strings.TrimSpace(parts[1])
+		switch key {
+		case "Size":
+			installed = value != "" && !strings.HasPrefix(value, "No Module Installed")
+		case "Type":
+			if value != "Unknown" && value != "Other" {
+				devType = value
+			}
+		case "Speed":
+			devSpeed = parseDMIDecodeSpeed(value)
+		case "Configured Memory Speed", "Configured Clock Speed":
+			devConfigured = parseDMIDecodeSpeed(value)
+		}
+	}
+	endDevice()
+
+	return memType, speed
+}
+
+// parseDMIDecodeSpeed parses a speed such as "3200 MT/s" or "2400 MHz",
+// returning zero if the speed is unknown.
+func parseDMIDecodeSpeed(value string) int {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return 0
+	}
+	speed, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0
+	}
+	return speed
+}
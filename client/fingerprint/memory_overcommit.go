@@ -0,0 +1,68 @@
+package fingerprint
+
+import (
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// memoryOvercommitInterval is the interval at which the overcommit policy
+	// is fingerprinted as it can be changed at runtime.
+	memoryOvercommitInterval = 30 * time.Second
+
+	memoryOvercommitAttr = "memory.overcommit"
+
+	// memoryOvercommitSysctl is the path of the sysctl under /proc/sys
+	memoryOvercommitSysctl = "vm/overcommit_memory"
+)
+
+// memoryOvercommitPolicies maps the values of vm.overcommit_memory to the
+// policies they select.
+var memoryOvercommitPolicies = map[string]string{
+	"0": "heuristic",
+	"1": "always",
+	"2": "never",
+}
+
+// MemoryOvercommitFingerprint is used to fingerprint the kernel's memory
+// overcommit policy.
+type MemoryOvercommitFingerprint struct {
+	logger *log.Logger
+
+	// procSysDir is the directory the sysctls are read from
+	procSysDir string
+}
+
+// NewMemoryOvercommitFingerprint is used to create a memory overcommit
+// fingerprint
+func NewMemoryOvercommitFingerprint(logger *log.Logger) Fingerprint {
+	f := &MemoryOvercommitFingerprint{
+		logger:     logger,
+		procSysDir: "/proc/sys",
+	}
+	return f
+}
+
+func (f *MemoryOvercommitFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	value := readSysfsValue(filepath.Join(f.procSysDir, memoryOvercommitSysctl))
+	policy, ok := memoryOvercommitPolicies[value]
+	if !ok {
+		if value != "" {
+			f.logger.Printf("[WARN] fingerprint.memory_overcommit: Unknown vm.overcommit_memory value %q", value)
+		}
+		delete(node.Attributes, memoryOvercommitAttr)
+		return false, nil
+	}
+
+	node.Attributes[memoryOvercommitAttr] = policy
+	return true, nil
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *MemoryOvercommitFingerprint) Periodic() (bool, time.Duration) {
+	return true, memoryOvercommitInterval
+}
@@ -0,0 +1,65 @@
+package fingerprint
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestMemoryOvercommitFingerprint(t *testing.T) {
+	cases := map[string]string{
+		"0\n": "heuristic",
+		"1\n": "always",
+		"2\n": "never",
+	}
+
+	for value, policy := range cases {
+		t.Run(policy, func(t *testing.T) {
+			dir := writeSysfsTree(t, map[string]string{
+				"vm/overcommit_memory": value,
+			})
+			defer os.RemoveAll(dir)
+
+			f := &MemoryOvercommitFingerprint{
+				logger:     testLogger(),
+				procSysDir: dir,
+			}
+			node := &structs.Node{
+				Attributes: make(map[string]string),
+			}
+
+			assertFingerprintOK(t, f, node)
+			assertNodeAttributeEquals(t, node, "memory.overcommit", policy)
+		})
+	}
+}
+
+func TestMemoryOvercommitFingerprint_Unsupported(t *testing.T) {
+	for _, files := range []map[string]string{{}, {"vm/overcommit_memory": "7\n"}} {
+		dir := writeSysfsTree(t, files)
+		defer os.RemoveAll(dir)
+
+		f := &MemoryOvercommitFingerprint{
+			logger:     testLogger(),
+			procSysDir: dir,
+		}
+		node := &structs.Node{
+			Attributes: map[string]string{
+				"memory.overcommit": "always",
+			},
+		}
+
+		ok, err := f.Fingerprint(&config.Config{}, node)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if ok {
+			t.Fatalf("should not apply")
+		}
+		if a, ok := node.Attributes["memory.overcommit"]; ok {
+			t.Fatalf("unexpected attribute memory.overcommit found, %s", a)
+		}
+	}
+}
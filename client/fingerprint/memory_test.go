@@ -1,14 +1,63 @@
 package fingerprint
 
 import (
+	"fmt"
+	"os/exec"
 	"testing"
 
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
+// A fake dmidecode querier that returns canned output
+type DMIDecodeQuerierMock struct {
+	out []byte
+	err error
+}
+
+func (d *DMIDecodeQuerierMock) QueryMemory() ([]byte, error) {
+	return d.out, d.err
+}
+
+const dmidecodeMemoryOutput = `# dmidecode 3.3
+Getting SMBIOS data from sysfs.
+SMBIOS 3.2.0 present.
+
+Handle 0x0040, DMI type 17, 84 bytes
+Memory Device
+	Array Handle: 0x003F
+	Size: 16 GB
+	Form Factor: DIMM
+	Locator: DIMM_A1
+	Type: DDR4
+	Type Detail: Synchronous Unbuffered (Unregistered)
+	Speed: 3200 MT/s
+	Configured Memory Speed: 2933 MT/s
+
+Handle 0x0041, DMI type 17, 84 bytes
+Memory Device
+	Array Handle: 0x003F
+	Size: No Module Installed
+	Form Factor: DIMM
+	Locator: DIMM_A2
+	Type: Unknown
+	Speed: Unknown
+	Configured Memory Speed: Unknown
+
+Handle 0x0042, DMI type 17, 84 bytes
+Memory Device
+	Array Handle: 0x003F
+	Size: 16 GB
+	Form Factor: DIMM
+	Locator: DIMM_B1
+	Type: DDR4
+	Speed: 2666 MT/s
+	Configured Memory Speed: 2666 MT/s
+`
+
 func TestMemoryFingerprint(t *testing.T) {
 	f := NewMemoryFingerprint(testLogger())
+	f.(*MemoryFingerprint).dmidecode = &DMIDecodeQuerierMock{out: []byte(dmidecodeMemoryOutput)}
 	node := &structs.Node{
 		Attributes: make(map[string]string),
 	}
@@ -29,4 +78,47 @@ func TestMemoryFingerprint(t *testing.T) {
 		t.Errorf("Expected node.Resources.MemoryMB to be non-zero")
 	}
 
+	assertNodeAttributeEquals(t, node, "memory.type", "DDR4")
+	assertNodeAttributeEquals(t, node, "memory.speed-mhz", "2666")
+}
+
+func TestMemoryFingerprint_NoDMIAccess(t *testing.T) {
+	errs := []error{
+		&exec.Error{Name: "dmidecode", Err: exec.ErrNotFound},
+		fmt.Errorf("/sys/firmware/dmi/tables/smbios_entry_point: Permission denied"),
+	}
+
+	for _, err := range errs {
+		f := &MemoryFingerprint{
+			logger:    testLogger(),
+			dmidecode: &DMIDecodeQuerierMock{err: err},
+		}
+		node := &structs.Node{
+			Attributes: map[string]string{
+				"memory.type":      "DDR3",
+				"memory.speed-mhz": "1600",
+			},
+		}
+
+		assertFingerprintOK(t, f, node)
+		assertNodeAttributeContains(t, node, "memory.totalbytes")
+		for _, k := range []string{"memory.type", "memory.speed-mhz"} {
+			if v, ok := node.Attributes[k]; ok {
+				t.Fatalf("unexpected attribute %s: %s", k, v)
+			}
+		}
+	}
+}
+
+func TestMemoryFingerprint_ParseDMIDecodeLegacySpeed(t *testing.T) {
+	out := `Memory Device
+	Size: 8192 MB
+	Type: DDR3
+	Speed: 1600 MHz
+	Configured Clock Speed: 1333 MHz
+`
+	memType, speed := parseDMIDecodeMemory([]byte(out))
+	if memType != "DDR3" || speed != 1333 {
+		t.Fatalf("expected DDR3 at 1333, got %s at %d", memType, speed)
+	}
 }
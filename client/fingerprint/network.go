@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"strconv"
 
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -13,6 +14,13 @@ const (
 	// defaultNetworkSpeed is the speed set if the network link speed could not
 	// be detected.
 	defaultNetworkSpeed = 1000
+
+	// preferredAddressFamilyOption is the client option selecting the address
+	// family of the address fingerprinted as the node's IP address.
+	preferredAddressFamilyOption = "fingerprint.network.preferred_address_family"
+
+	addressFamilyIPv4 = "ipv4"
+	addressFamilyIPv6 = "ipv6"
 )
 
 // NetworkFingerprint is used to fingerprint the Network capabilities of a node
@@ -20,6 +28,10 @@ type NetworkFingerprint struct {
 	StaticFingerprinter
 	logger            *log.Logger
 	interfaceDetector NetworkInterfaceDetector
+
+	// measureThroughput is used to measure the network throughput when
+	// enabled. If nil, the throughput is measured over TCP.
+	measureThroughput ThroughputMeasurer
 }
 
 // An interface to isolate calls to various api in net package
@@ -50,11 +62,23 @@ func (b *DefaultNetworkInterfaceDetector) Addrs(intf *net.Interface) ([]net.Addr
 // NewNetworkFingerprint returns a new NetworkFingerprinter with the given
 // logger
 func NewNetworkFingerprint(logger *log.Logger) Fingerprint {
-	f := &NetworkFingerprint{logger: logger, interfaceDetector: &DefaultNetworkInterfaceDetector{}}
+	f := &NetworkFingerprint{
+		logger:            logger,
+		interfaceDetector: &DefaultNetworkInterfaceDetector{},
+		measureThroughput: measureTCPThroughput,
+	}
 	return f
 }
 
 func (f *NetworkFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	family := cfg.ReadDefault(preferredAddressFamilyOption, "")
+	switch family {
+	case "", addressFamilyIPv4, addressFamilyIPv6:
+	default:
+		return false, fmt.Errorf("Invalid %s %q; must be %q or %q",
+			preferredAddressFamilyOption, family, addressFamilyIPv4, addressFamilyIPv6)
+	}
+
 	if node.Resources == nil {
 		node.Resources = &structs.Resources{}
 	}
@@ -95,15 +119,53 @@ func (f *NetworkFingerprint) Fingerprint(cfg *config.Config, node *structs.Node)
 		f.logger.Printf("[DEBUG] fingerprint.network: Detected interface %v with IP: %v", intf.Name, nwResource.IP)
 	}
 
-	// Deprecated, setting the first IP as unique IP for the node
-	if len(nwResources) > 0 {
-		node.Attributes["unique.network.ip-address"] = nwResources[0].IP
+	// Deprecated, setting the first IP of the preferred family as unique IP
+	// for the node
+	if ip := primaryIPAddress(nwResources, family); ip != "" {
+		node.Attributes["unique.network.ip-address"] = ip
+	}
+
+	// Record the first global IPv6 address so IPv6-only jobs can bind to it
+	if ip := globalIPv6Address(nwResources); ip != "" {
+		node.Attributes["unique.network.ip-address-v6"] = ip
+	}
+
+	// Measure the throughput as virtualized interfaces may misreport their
+	// link speed
+	if cfg.ReadBoolDefault(measureThroughputOption, false) {
+		f.fingerprintThroughput(cfg, node)
+	} else {
+		delete(node.Attributes, measuredThroughputAttr)
 	}
 
 	// return true, because we have a network connection
 	return true, nil
 }
 
+// fingerprintThroughput measures the network throughput for the configured
+// duration, capped at maxMeasureDuration, and records it as an attribute.
+func (f *NetworkFingerprint) fingerprintThroughput(cfg *config.Config, node *structs.Node) {
+	duration := cfg.ReadDurationDefault(measureDurationOption, defaultMeasureDuration)
+	if duration <= 0 || duration > maxMeasureDuration {
+		duration = maxMeasureDuration
+	}
+
+	measure := f.measureThroughput
+	if measure == nil {
+		measure = measureTCPThroughput
+	}
+
+	mbits, err := measure(cfg.Read(measureTargetOption), duration)
+	if err != nil {
+		f.logger.Printf("[WARN] fingerprint.network: failed to measure throughput: %v", err)
+		delete(node.Attributes, measuredThroughputAttr)
+		return
+	}
+
+	f.logger.Printf("[DEBUG] fingerprint.network: measured throughput of %d Mb/s", mbits)
+	node.Attributes[measuredThroughputAttr] = strconv.Itoa(mbits)
+}
+
 // createNetworkResources creates network resources for every IP
 func (f *NetworkFingerprint) createNetworkResources(throughput int, intf *net.Interface) ([]*structs.NetworkResource, error) {
 	// Find the interface with the name
@@ -144,6 +206,44 @@ func (f *NetworkFingerprint) createNetworkResources(throughput int, intf *net.In
 	return nwResources, nil
 }
 
+// primaryIPAddress returns the first IP address of the network resources in
+// the given address family. If there is none or no family is given, the first
+// address of any family is returned.
+func primaryIPAddress(nwResources []*structs.NetworkResource, family string) string {
+	if len(nwResources) == 0 {
+		return ""
+	}
+
+	switch family {
+	case addressFamilyIPv4:
+		for _, nwResource := range nwResources {
+			if ip := net.ParseIP(nwResource.IP); ip != nil && ip.To4() != nil {
+				return nwResource.IP
+			}
+		}
+	case addressFamilyIPv6:
+		if ip := globalIPv6Address(nwResources); ip != "" {
+			return ip
+		}
+	}
+	return nwResources[0].IP
+}
+
+// globalIPv6Address returns the first global unicast IPv6 address of the
+// network resources, or an empty string if there is none.
+func globalIPv6Address(nwResources []*structs.NetworkResource) string {
+	for _, nwResource := range nwResources {
+		ip := net.ParseIP(nwResource.IP)
+		if ip == nil || ip.To4() != nil {
+			continue
+		}
+		if ip.IsGlobalUnicast() {
+			return nwResource.IP
+		}
+	}
+	return ""
+}
+
 // Checks if the device is marked UP by the operator
 func (f *NetworkFingerprint) isDeviceEnabled(intf *net.Interface) bool {
 	return intf.Flags&net.FlagUp != 0
@@ -5,6 +5,7 @@ import (
 	"net"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -143,6 +144,33 @@ func (n *NetworkInterfaceDetectorMultipleInterfaces) Addrs(intf *net.Interface)
 	return nil, fmt.Errorf("Can't find addresses for device: %v", intf.Name)
 }
 
+// A fake network detector which simulates an interface with only IPv4
+// addresses and an IPv6 link-local address
+type NetworkInterfaceDetectorIPv4Only struct {
+}
+
+func (n *NetworkInterfaceDetectorIPv4Only) Interfaces() ([]net.Interface, error) {
+	return []net.Interface{eth0}, nil
+}
+
+func (n *NetworkInterfaceDetectorIPv4Only) InterfaceByName(name string) (*net.Interface, error) {
+	if name == "eth0" {
+		return &eth0, nil
+	}
+
+	return nil, fmt.Errorf("No device with name %v found", name)
+}
+
+func (n *NetworkInterfaceDetectorIPv4Only) Addrs(intf *net.Interface) ([]net.Addr, error) {
+	if intf.Name == "eth0" {
+		_, ipnet1, _ := net.ParseCIDR("100.64.0.11/10")
+		ipAddr, _ := net.ResolveIPAddr("ip6", "fe80::140c:9579:8037:f565")
+		return []net.Addr{ipnet1, ipAddr}, nil
+	}
+
+	return nil, fmt.Errorf("Can't find addresses for device: %v", intf.Name)
+}
+
 func TestNetworkFingerprint_basic(t *testing.T) {
 	if v := os.Getenv(skipOnlineTestsEnvVar); v != "" {
 		t.Skipf("Environment variable %+q not empty, skipping test", skipOnlineTestsEnvVar)
@@ -321,3 +349,176 @@ func TestNetworkFingerPrint_excludelo_down_interfaces(t *testing.T) {
 		t.Fatalf("bad number of IPs %v", len(node.Resources.Networks))
 	}
 }
+
+func TestNetworkFingerPrint_IPv6(t *testing.T) {
+	f := &NetworkFingerprint{logger: testLogger(), interfaceDetector: &NetworkInterfaceDetectorMultipleInterfaces{}}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+	cfg := &config.Config{NetworkSpeed: 100}
+
+	ok, err := f.Fingerprint(cfg, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("should apply")
+	}
+
+	assertNodeAttributeEquals(t, node, "unique.network.ip-address", "100.64.0.0")
+	assertNodeAttributeEquals(t, node, "unique.network.ip-address-v6", "2001:db8:85a3::")
+}
+
+func TestNetworkFingerPrint_IPv4Only(t *testing.T) {
+	f := &NetworkFingerprint{logger: testLogger(), interfaceDetector: &NetworkInterfaceDetectorIPv4Only{}}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+	cfg := &config.Config{NetworkSpeed: 100}
+
+	ok, err := f.Fingerprint(cfg, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("should apply")
+	}
+
+	assertNodeAttributeEquals(t, node, "unique.network.ip-address", "100.64.0.0")
+	if ip, ok := node.Attributes["unique.network.ip-address-v6"]; ok {
+		t.Fatalf("unexpected IPv6 address: %v", ip)
+	}
+}
+
+func TestNetworkFingerPrint_PreferredAddressFamily(t *testing.T) {
+	cases := []struct {
+		family   string
+		expected string
+	}{
+		{"", "100.64.0.0"},
+		{"ipv4", "100.64.0.0"},
+		{"ipv6", "2001:db8:85a3::"},
+	}
+
+	for _, c := range cases {
+		f := &NetworkFingerprint{logger: testLogger(), interfaceDetector: &NetworkInterfaceDetectorMultipleInterfaces{}}
+		node := &structs.Node{
+			Attributes: make(map[string]string),
+		}
+		cfg := &config.Config{
+			NetworkSpeed: 100,
+			Options: map[string]string{
+				"fingerprint.network.preferred_address_family": c.family,
+			},
+		}
+
+		ok, err := f.Fingerprint(cfg, node)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if !ok {
+			t.Fatalf("should apply")
+		}
+
+		assertNodeAttributeEquals(t, node, "unique.network.ip-address", c.expected)
+	}
+}
+
+func TestNetworkFingerPrint_PreferredAddressFamily_Fallback(t *testing.T) {
+	f := &NetworkFingerprint{logger: testLogger(), interfaceDetector: &NetworkInterfaceDetectorIPv4Only{}}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+	cfg := &config.Config{
+		NetworkSpeed: 100,
+		Options: map[string]string{
+			"fingerprint.network.preferred_address_family": "ipv6",
+		},
+	}
+
+	ok, err := f.Fingerprint(cfg, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("should apply")
+	}
+
+	// The IPv4 address is used when there is no IPv6 address
+	assertNodeAttributeEquals(t, node, "unique.network.ip-address", "100.64.0.0")
+
+	// The node is left unchanged by an invalid address family
+	node = &structs.Node{
+		Attributes: make(map[string]string),
+	}
+	cfg.Options["fingerprint.network.preferred_address_family"] = "ipx"
+	if _, err := f.Fingerprint(cfg, node); err == nil {
+		t.Fatalf("expected error for invalid address family")
+	}
+	if node.Resources != nil || len(node.Attributes) != 0 {
+		t.Fatalf("expected the node to be unchanged, got %v %v", node.Resources, node.Attributes)
+	}
+}
+
+func TestNetworkFingerPrint_MeasureThroughput(t *testing.T) {
+	var target string
+	var duration time.Duration
+	f := &NetworkFingerprint{
+		logger:            testLogger(),
+		interfaceDetector: &NetworkInterfaceDetectorMultipleInterfaces{},
+		measureThroughput: func(tgt string, d time.Duration) (int, error) {
+			target, duration = tgt, d
+			return 742, nil
+		},
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+	cfg := &config.Config{
+		NetworkSpeed: 100,
+		Options: map[string]string{
+			"fingerprint.network.measure_throughput": "true",
+			"fingerprint.network.measure_target":     "10.0.0.2:9",
+			"fingerprint.network.measure_duration":   "1m",
+		},
+	}
+
+	ok, err := f.Fingerprint(cfg, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("should apply")
+	}
+
+	assertNodeAttributeEquals(t, node, "unique.network.throughput-measured-mbps", "742")
+	if target != "10.0.0.2:9" {
+		t.Fatalf("unexpected target %q", target)
+	}
+	if duration != 5*time.Second {
+		t.Fatalf("expected duration to be capped, got %v", duration)
+	}
+
+	// The measurement is disabled by default
+	delete(cfg.Options, "fingerprint.network.measure_throughput")
+	f.measureThroughput = func(string, time.Duration) (int, error) {
+		t.Fatalf("unexpected measurement")
+		return 0, nil
+	}
+	if _, err := f.Fingerprint(cfg, node); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if mbits, ok := node.Attributes["unique.network.throughput-measured-mbps"]; ok {
+		t.Fatalf("unexpected measured throughput %q", mbits)
+	}
+}
+
+func TestNetworkFingerPrint_MeasureTCPThroughput(t *testing.T) {
+	mbits, err := measureTCPThroughput("", 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if mbits <= 0 {
+		t.Fatalf("expected a positive throughput, got %d", mbits)
+	}
+}
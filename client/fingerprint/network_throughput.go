@@ -0,0 +1,85 @@
+package fingerprint
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"time"
+)
+
+const (
+	// measureThroughputOption enables measuring the network throughput rather
+	// than relying on the link speed reported by the interface.
+	measureThroughputOption = "fingerprint.network.measure_throughput"
+
+	// measureTargetOption is the address of a TCP endpoint that discards the
+	// data sent to it. If empty, the throughput is measured over loopback.
+	measureTargetOption = "fingerprint.network.measure_target"
+
+	// measureDurationOption is how long to send data for when measuring
+	measureDurationOption = "fingerprint.network.measure_duration"
+
+	// defaultMeasureDuration and maxMeasureDuration are the default and
+	// maximum length of a measurement, which delays the client's startup.
+	defaultMeasureDuration = 1 * time.Second
+	maxMeasureDuration     = 5 * time.Second
+
+	// measureDialTimeout is the length of time to wait when connecting to the
+	// measurement target.
+	measureDialTimeout = 2 * time.Second
+
+	measuredThroughputAttr = "unique.network.throughput-measured-mbps"
+)
+
+// ThroughputMeasurer measures the throughput in megabits per second achieved
+// sending data to the target for the given duration.
+type ThroughputMeasurer func(target string, duration time.Duration) (int, error)
+
+// measureTCPThroughput measures the throughput of sending data over a TCP
+// connection to the target. If the target is empty, a loopback listener that
+// discards the data is used.
+func measureTCPThroughput(target string, duration time.Duration) (int, error) {
+	if target == "" {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return 0, err
+		}
+		defer l.Close()
+
+		go func() {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			io.Copy(ioutil.Discard, conn)
+		}()
+		target = l.Addr().String()
+	}
+
+	conn, err := net.DialTimeout("tcp", target, measureDialTimeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	buf := make([]byte, 64*1024)
+	start := time.Now()
+	deadline := start.Add(duration)
+	conn.SetWriteDeadline(deadline)
+
+	var sent int64
+	for time.Now().Before(deadline) {
+		n, err := conn.Write(buf)
+		sent += int64(n)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				break
+			}
+			return 0, err
+		}
+	}
+
+	elapsed := time.Since(start).Seconds()
+	return int(float64(sent*8) / elapsed / 1e6), nil
+}
@@ -2,12 +2,15 @@ package fingerprint
 
 import (
 	"log"
+	"strconv"
 
 	client "github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
-// NomadFingerprint is used to fingerprint the Nomad version
+// NomadFingerprint is used to fingerprint the Nomad version, revision,
+// advertised HTTP address, node class, reserved resources and garbage
+// collection thresholds
 type NomadFingerprint struct {
 	StaticFingerprinter
 	logger *log.Logger
@@ -22,5 +25,34 @@ func NewNomadFingerprint(logger *log.Logger) Fingerprint {
 func (f *NomadFingerprint) Fingerprint(config *client.Config, node *structs.Node) (bool, error) {
 	node.Attributes["nomad.version"] = config.Version
 	node.Attributes["nomad.revision"] = config.Revision
+
+	// The advertise address is only known when running as part of an agent
+	if node.HTTPAddr != "" {
+		node.Attributes["unique.nomad.advertise.address"] = node.HTTPAddr
+	} else {
+		delete(node.Attributes, "unique.nomad.advertise.address")
+	}
+
+	// Surface the configured node class so it appears among the attributes
+	if node.NodeClass != "" {
+		node.Attributes["nomad.node-class"] = node.NodeClass
+	} else {
+		delete(node.Attributes, "nomad.node-class")
+	}
+
+	// Surface the resources reserved in the client configuration, which are
+	// otherwise easily forgotten as they are only subtracted from the total.
+	reserved := node.Reserved
+	if reserved == nil {
+		reserved = &structs.Resources{}
+	}
+	node.Attributes["nomad.reserved.cpu-mhz"] = strconv.Itoa(reserved.CPU)
+	node.Attributes["nomad.reserved.memory-mb"] = strconv.Itoa(reserved.MemoryMB)
+	node.Attributes["nomad.reserved.disk-mb"] = strconv.Itoa(reserved.DiskMB)
+
+	// Surface the usage thresholds past which terminal allocations are
+	// garbage collected to help explain when the client collects them
+	node.Attributes["nomad.gc.disk-usage-threshold"] = strconv.FormatFloat(config.GCDiskUsageThreshold, 'f', -1, 64)
+	node.Attributes["nomad.gc.inode-usage-threshold"] = strconv.FormatFloat(config.GCInodeUsageThreshold, 'f', -1, 64)
 	return true, nil
 }
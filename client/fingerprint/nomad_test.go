@@ -11,6 +11,8 @@ func TestNomadFingerprint(t *testing.T) {
 	f := NewNomadFingerprint(testLogger())
 	node := &structs.Node{
 		Attributes: make(map[string]string),
+		HTTPAddr:   "10.0.0.1:4646",
+		NodeClass:  "linux-64bit",
 	}
 	v := "foo"
 	r := "123"
@@ -31,4 +33,69 @@ func TestNomadFingerprint(t *testing.T) {
 	if node.Attributes["nomad.revision"] != r {
 		t.Fatalf("incorrect revision")
 	}
+	if node.Attributes["unique.nomad.advertise.address"] != node.HTTPAddr {
+		t.Fatalf("incorrect advertise address")
+	}
+	if node.Attributes["nomad.node-class"] != node.NodeClass {
+		t.Fatalf("incorrect node class")
+	}
+
+	// The attribute is removed when no class is configured
+	node.NodeClass = ""
+	if _, err := f.Fingerprint(c, node); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if class, ok := node.Attributes["nomad.node-class"]; ok {
+		t.Fatalf("unexpected node class %q", class)
+	}
+}
+
+func TestNomadFingerprint_Reserved(t *testing.T) {
+	f := NewNomadFingerprint(testLogger())
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+		Reserved: &structs.Resources{
+			CPU:      500,
+			MemoryMB: 1024,
+			DiskMB:   10240,
+		},
+	}
+
+	if _, err := f.Fingerprint(&config.Config{}, node); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assertNodeAttributeEquals(t, node, "nomad.reserved.cpu-mhz", "500")
+	assertNodeAttributeEquals(t, node, "nomad.reserved.memory-mb", "1024")
+	assertNodeAttributeEquals(t, node, "nomad.reserved.disk-mb", "10240")
+
+	// Nothing is reserved when no reservations are configured
+	node.Reserved = nil
+	if _, err := f.Fingerprint(&config.Config{}, node); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assertNodeAttributeEquals(t, node, "nomad.reserved.cpu-mhz", "0")
+	assertNodeAttributeEquals(t, node, "nomad.reserved.memory-mb", "0")
+	assertNodeAttributeEquals(t, node, "nomad.reserved.disk-mb", "0")
+}
+
+func TestNomadFingerprint_GCThresholds(t *testing.T) {
+	f := NewNomadFingerprint(testLogger())
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	cfg := config.DefaultConfig()
+	if _, err := f.Fingerprint(cfg, node); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assertNodeAttributeEquals(t, node, "nomad.gc.disk-usage-threshold", "80")
+	assertNodeAttributeEquals(t, node, "nomad.gc.inode-usage-threshold", "70")
+
+	cfg.GCDiskUsageThreshold = 92.5
+	cfg.GCInodeUsageThreshold = 60
+	if _, err := f.Fingerprint(cfg, node); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assertNodeAttributeEquals(t, node, "nomad.gc.disk-usage-threshold", "92.5")
+	assertNodeAttributeEquals(t, node, "nomad.gc.inode-usage-threshold", "60")
 }
@@ -0,0 +1,320 @@
+package fingerprint
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// nvidiaAttrPrefix is the prefix of the NVIDIA GPU attributes
+	nvidiaAttrPrefix = "gpu.nvidia."
+
+	// nvidiaInterval is the interval at which the NVIDIA GPUs are polled as
+	// their free memory changes with the processes using them.
+	nvidiaInterval = 15 * time.Second
+)
+
+var (
+	// nvidiaMIGLineRe matches the rows of the tables listed by nvidia-smi mig
+	// -lgi and -lgip, capturing the GPU index and the profile name, such as:
+	//	|   0  MIG 1g.5gb        19     7/7        4.75       No     14     0     0   |
+	nvidiaMIGLineRe = regexp.MustCompile(`^\|\s+(\d+)\s+MIG\s+(\S+)\s`)
+
+	// nvidiaTopoGPURe matches the GPU column and row labels of the matrix
+	// listed by nvidia-smi topo -m, capturing the GPU index.
+	nvidiaTopoGPURe = regexp.MustCompile(`^GPU(\d+)$`)
+)
+
+// NvidiaFingerprint is used to fingerprint NVIDIA GPUs and their free memory
+// using nvidia-smi
+type NvidiaFingerprint struct {
+	logger *log.Logger
+	smi    NvidiaSMIQuerier
+}
+
+// An interface to isolate calls to nvidia-smi
+// This facilitates testing where we can return canned output
+type NvidiaSMIQuerier interface {
+	// Query returns the CSV output of nvidia-smi listing the index, total
+	// memory, free memory and name of every GPU.
+	Query() ([]byte, error)
+
+	// MIGInstances returns the output of nvidia-smi mig -lgi listing the MIG
+	// GPU instances that have been created.
+	MIGInstances() ([]byte, error)
+
+	// MIGProfiles returns the output of nvidia-smi mig -lgip listing the MIG
+	// GPU instance profiles of the GPUs with MIG enabled.
+	MIGProfiles() ([]byte, error)
+
+	// Topology returns the output of nvidia-smi topo -m listing the matrix
+	// of the connections between the GPUs.
+	Topology() ([]byte, error)
+}
+
+// Implements the querier which calls nvidia-smi found in the $PATH
+type DefaultNvidiaSMIQuerier struct {
+}
+
+func (d *DefaultNvidiaSMIQuerier) Query() ([]byte, error) {
+	path, err := exec.LookPath("nvidia-smi")
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command(path, "--query-gpu=index,memory.total,memory.free,name", "--format=csv,noheader,nounits").Output()
+}
+
+func (d *DefaultNvidiaSMIQuerier) MIGInstances() ([]byte, error) {
+	return d.mig("-lgi")
+}
+
+func (d *DefaultNvidiaSMIQuerier) MIGProfiles() ([]byte, error) {
+	return d.mig("-lgip")
+}
+
+func (d *DefaultNvidiaSMIQuerier) Topology() ([]byte, error) {
+	path, err := exec.LookPath("nvidia-smi")
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command(path, "topo", "-m").Output()
+}
+
+func (d *DefaultNvidiaSMIQuerier) mig(flag string) ([]byte, error) {
+	path, err := exec.LookPath("nvidia-smi")
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command(path, "mig", flag).Output()
+}
+
+// NewNvidiaFingerprint is used to create an NVIDIA GPU fingerprint
+func NewNvidiaFingerprint(logger *log.Logger) Fingerprint {
+	f := &NvidiaFingerprint{
+		logger: logger,
+		smi:    &DefaultNvidiaSMIQuerier{},
+	}
+	return f
+}
+
+func (f *NvidiaFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	// Clear any attributes from the previous poll
+	uniquePrefix := structs.UniqueNamespace(nvidiaAttrPrefix)
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, nvidiaAttrPrefix) || strings.HasPrefix(k, uniquePrefix) {
+			delete(node.Attributes, k)
+		}
+	}
+
+	out, err := f.smi.Query()
+	if err != nil {
+		// nvidia-smi is only present on nodes with NVIDIA GPUs
+		if _, ok := err.(*exec.Error); !ok {
+			f.logger.Printf("[WARN] fingerprint.nvidia: Error calling nvidia-smi: %v", err)
+		}
+		return false, nil
+	}
+
+	// Output looks something like:
+	//	0, 16160, 15874, Tesla V100-SXM2-16GB
+	count := 0
+	var indexes []int
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.SplitN(line, ",", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+
+		index, err := strconv.Atoi(fields[0])
+		if err != nil {
+			f.logger.Printf("[WARN] fingerprint.nvidia: Error parsing GPU index %q: %v", fields[0], err)
+			continue
+		}
+		prefix := fmt.Sprintf("%s%d.", nvidiaAttrPrefix, index)
+
+		node.Attributes[prefix+"model"] = fields[3]
+		if _, err := strconv.Atoi(fields[1]); err == nil {
+			node.Attributes[prefix+"memory-mb"] = fields[1]
+		}
+		if _, err := strconv.Atoi(fields[2]); err == nil {
+			node.Attributes[structs.UniqueNamespace(prefix+"memory-free-mb")] = fields[2]
+		}
+		indexes = append(indexes, index)
+		count++
+	}
+
+	if count == 0 {
+		return false, nil
+	}
+
+	f.fingerprintMIG(indexes, node)
+	f.fingerprintTopology(node)
+
+	node.Attributes[nvidiaAttrPrefix+"count"] = strconv.Itoa(count)
+	return true, nil
+}
+
+// fingerprintMIG records whether MIG is enabled on each of the GPUs and, if it
+// is, the MIG GPU instance profiles they support and the GPU instances that
+// have been created. nvidia-smi fails to list the profiles when no GPU has MIG
+// enabled, or if the driver does not support MIG.
+func (f *NvidiaFingerprint) fingerprintMIG(indexes []int, node *structs.Node) {
+	profiles := map[int][]string{}
+	if out, err := f.smi.MIGProfiles(); err != nil {
+		f.logger.Printf("[DEBUG] fingerprint.nvidia: MIG GPU instance profiles not available: %v", err)
+	} else {
+		profiles = parseNvidiaMIG(out)
+	}
+
+	instances := map[int][]string{}
+	if len(profiles) != 0 {
+		if out, err := f.smi.MIGInstances(); err != nil {
+			f.logger.Printf("[DEBUG] fingerprint.nvidia: MIG GPU instances not available: %v", err)
+		} else {
+			instances = parseNvidiaMIG(out)
+		}
+	}
+
+	for _, index := range indexes {
+		prefix := fmt.Sprintf("%s%d.mig.", nvidiaAttrPrefix, index)
+		p, ok := profiles[index]
+		node.Attributes[prefix+"enabled"] = strconv.FormatBool(ok)
+		if !ok {
+			continue
+		}
+
+		node.Attributes[prefix+"profiles"] = strings.Join(p, ",")
+		node.Attributes[prefix+"instances.count"] = strconv.Itoa(len(instances[index]))
+		if len(instances[index]) != 0 {
+			node.Attributes[prefix+"instances"] = strings.Join(instances[index], ",")
+		}
+	}
+}
+
+// fingerprintTopology records whether any of the GPUs are connected by NVLink,
+// the GPUs each of them is connected to by NVLink and a summary of the
+// connections between every pair of GPUs, such as "0-1:NV12,0-2:SYS,1-2:SYS".
+func (f *NvidiaFingerprint) fingerprintTopology(node *structs.Node) {
+	out, err := f.smi.Topology()
+	if err != nil {
+		f.logger.Printf("[DEBUG] fingerprint.nvidia: GPU topology not available: %v", err)
+		return
+	}
+	links := parseNvidiaTopology(out)
+
+	present := false
+	var pairs []string
+	peers := map[int][]string{}
+	indexes := make([]int, 0, len(links))
+	for i := range links {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		for _, j := range indexes {
+			link, ok := links[i][j]
+			if !ok || i == j {
+				continue
+			}
+			nvlink := strings.HasPrefix(link, "NV")
+			if nvlink {
+				present = true
+				peers[i] = append(peers[i], strconv.Itoa(j))
+			}
+			if i < j {
+				pairs = append(pairs, fmt.Sprintf("%d-%d:%s", i, j, link))
+			}
+		}
+	}
+
+	node.Attributes[nvidiaAttrPrefix+"nvlink.present"] = strconv.FormatBool(present)
+	if len(pairs) != 0 {
+		node.Attributes[nvidiaAttrPrefix+"topology"] = strings.Join(pairs, ",")
+	}
+	for i, p := range peers {
+		node.Attributes[fmt.Sprintf("%s%d.nvlink.peers", nvidiaAttrPrefix, i)] = strings.Join(p, ",")
+	}
+}
+
+// parseNvidiaTopology parses the matrix listed by nvidia-smi topo -m and
+// returns the type of the connection between each pair of GPUs by their
+// indexes, such as "NV12" for twelve NVLinks or "SYS" for a connection through
+// the system interconnect. Columns and rows of other devices, such as NICs,
+// are ignored. The matrix looks something like:
+//
+//	        GPU0    GPU1    CPU Affinity    NUMA Affinity
+//	GPU0     X      NV12    0-23            N/A
+//	GPU1    NV12     X      0-23            N/A
+func parseNvidiaTopology(out []byte) map[int]map[int]string {
+	links := map[int]map[int]string{}
+	var columns []int
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// The header is the first line labelling the GPU columns
+		if columns == nil {
+			for _, field := range fields {
+				m := nvidiaTopoGPURe.FindStringSubmatch(field)
+				if m == nil {
+					break
+				}
+				index, _ := strconv.Atoi(m[1])
+				columns = append(columns, index)
+			}
+			continue
+		}
+
+		m := nvidiaTopoGPURe.FindStringSubmatch(fields[0])
+		if m == nil {
+			continue
+		}
+		row, _ := strconv.Atoi(m[1])
+		links[row] = map[int]string{}
+		for i, column := range columns {
+			if i+1 >= len(fields) {
+				break
+			}
+			links[row][column] = fields[i+1]
+		}
+	}
+	return links
+}
+
+// parseNvidiaMIG parses a table listed by nvidia-smi mig -lgi or -lgip and
+// returns the profile names of its rows by GPU index, in the order they appear.
+func parseNvidiaMIG(out []byte) map[int][]string {
+	names := map[int][]string{}
+	for _, line := range strings.Split(string(out), "\n") {
+		m := nvidiaMIGLineRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+
+		index, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
+		}
+		names[index] = append(names[index], m[2])
+	}
+	return names
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *NvidiaFingerprint) Periodic() (bool, time.Duration) {
+	return true, nvidiaInterval
+}
@@ -0,0 +1,241 @@
+package fingerprint
+
+import (
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// A fake nvidia-smi querier that returns the canned outputs in turn
+type NvidiaSMIQuerierMock struct {
+	outs [][]byte
+	err  error
+
+	// lgi and lgip are the outputs of nvidia-smi mig -lgi and -lgip, which
+	// fail as if MIG were disabled if nil.
+	lgi  []byte
+	lgip []byte
+
+	// topo is the output of nvidia-smi topo -m, which fails if nil.
+	topo []byte
+}
+
+func (n *NvidiaSMIQuerierMock) Query() ([]byte, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
+	out := n.outs[0]
+	n.outs = n.outs[1:]
+	return out, nil
+}
+
+func (n *NvidiaSMIQuerierMock) MIGInstances() ([]byte, error) {
+	return n.mig(n.lgi)
+}
+
+func (n *NvidiaSMIQuerierMock) MIGProfiles() ([]byte, error) {
+	return n.mig(n.lgip)
+}
+
+func (n *NvidiaSMIQuerierMock) Topology() ([]byte, error) {
+	if n.topo == nil {
+		return nil, fmt.Errorf("topology not supported")
+	}
+	return n.topo, nil
+}
+
+func (n *NvidiaSMIQuerierMock) mig(out []byte) ([]byte, error) {
+	if out == nil {
+		return nil, fmt.Errorf("No MIG-enabled devices found.")
+	}
+	return out, nil
+}
+
+func TestNvidiaFingerprint(t *testing.T) {
+	f := &NvidiaFingerprint{
+		logger: testLogger(),
+		smi: &NvidiaSMIQuerierMock{outs: [][]byte{
+			[]byte("0, 16160, 15874, Tesla V100-SXM2-16GB\n1, 16160, 16150, Tesla V100-SXM2-16GB\n"),
+			[]byte("0, 16160, 1024, Tesla V100-SXM2-16GB\n"),
+		}},
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.count", "2")
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.0.model", "Tesla V100-SXM2-16GB")
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.0.memory-mb", "16160")
+	assertNodeAttributeEquals(t, node, "unique.gpu.nvidia.0.memory-free-mb", "15874")
+	assertNodeAttributeEquals(t, node, "unique.gpu.nvidia.1.memory-free-mb", "16150")
+
+	// The next poll updates the free memory and drops the removed GPU
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.count", "1")
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.0.memory-mb", "16160")
+	assertNodeAttributeEquals(t, node, "unique.gpu.nvidia.0.memory-free-mb", "1024")
+	if a, ok := node.Attributes["unique.gpu.nvidia.1.memory-free-mb"]; ok {
+		t.Fatalf("unexpected attribute unique.gpu.nvidia.1.memory-free-mb found, %s", a)
+	}
+}
+
+const (
+	// nvidiaMIGProfiles is the output of nvidia-smi mig -lgip on a node with
+	// MIG enabled on the first of two A100s.
+	nvidiaMIGProfiles = `+-----------------------------------------------------------------------------+
+| GPU instance profiles:                                                      |
+| GPU   Name             ID    Instances   Memory     P2P    SM    DEC   ENC  |
+|                              Free/Total   GiB              CE    JPEG  OFA  |
+|=============================================================================|
+|   0  MIG 1g.5gb        19     5/7        4.75       No     14     0     0   |
+|                                                             1     0     0   |
++-----------------------------------------------------------------------------+
+|   0  MIG 2g.10gb       14     3/3        9.75       No     28     1     0   |
+|                                                             2     0     0   |
++-----------------------------------------------------------------------------+
+|   0  MIG 7g.40gb        0     0/1        39.25      No     98     5     0   |
+|                                                             7     1     1   |
++-----------------------------------------------------------------------------+
+`
+
+	// nvidiaMIGInstances is the output of nvidia-smi mig -lgi on the same
+	// node.
+	nvidiaMIGInstances = `+----------------------------------------------------+
+| GPU instances:                                     |
+| GPU   Name          Profile  Instance   Placement  |
+|                       ID       ID       Start:Size |
+|====================================================|
+|   0  MIG 1g.5gb       19        9          2:1     |
++----------------------------------------------------+
+|   0  MIG 1g.5gb       19       10          3:1     |
++----------------------------------------------------+
+`
+)
+
+func TestNvidiaFingerprint_MIG(t *testing.T) {
+	f := &NvidiaFingerprint{
+		logger: testLogger(),
+		smi: &NvidiaSMIQuerierMock{
+			outs: [][]byte{
+				[]byte("0, 40536, 40000, A100-SXM4-40GB\n1, 40536, 40536, A100-SXM4-40GB\n"),
+				[]byte("0, 40536, 40000, A100-SXM4-40GB\n"),
+			},
+			lgi:  []byte(nvidiaMIGInstances),
+			lgip: []byte(nvidiaMIGProfiles),
+		},
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.0.mig.enabled", "true")
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.0.mig.profiles", "1g.5gb,2g.10gb,7g.40gb")
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.0.mig.instances", "1g.5gb,1g.5gb")
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.0.mig.instances.count", "2")
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.1.mig.enabled", "false")
+	if a, ok := node.Attributes["gpu.nvidia.1.mig.profiles"]; ok {
+		t.Fatalf("unexpected attribute gpu.nvidia.1.mig.profiles found, %s", a)
+	}
+
+	// Disabling MIG removes the profiles and instances
+	f.smi.(*NvidiaSMIQuerierMock).lgi = nil
+	f.smi.(*NvidiaSMIQuerierMock).lgip = nil
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.0.mig.enabled", "false")
+	for _, k := range []string{"gpu.nvidia.0.mig.profiles", "gpu.nvidia.0.mig.instances", "gpu.nvidia.0.mig.instances.count"} {
+		if a, ok := node.Attributes[k]; ok {
+			t.Fatalf("unexpected attribute %s found, %s", k, a)
+		}
+	}
+}
+
+const (
+	// nvidiaTopology is the output of nvidia-smi topo -m on a node with two
+	// pairs of GPUs connected by NVLink and a NIC.
+	nvidiaTopology = `	GPU0	GPU1	GPU2	GPU3	NIC0	CPU Affinity	NUMA Affinity
+GPU0	 X 	NV12	SYS	SYS	PHB	0-23	0
+GPU1	NV12	 X 	SYS	SYS	PHB	0-23	0
+GPU2	SYS	SYS	 X 	NV4	SYS	24-47	1
+GPU3	SYS	SYS	NV4	 X 	SYS	24-47	1
+NIC0	PHB	PHB	SYS	SYS	 X 		
+
+Legend:
+
+  X    = Self
+  SYS  = Connection traversing PCIe as well as the SMP interconnect between NUMA nodes (e.g., QPI/UPI)
+  PHB  = Connection traversing PCIe as well as a PCIe Host Bridge (typically the CPU)
+  NV#  = Connection traversing a bonded set of # NVLinks
+`
+
+	// nvidiaTopologyPCIe is the output of nvidia-smi topo -m on a node with
+	// two GPUs connected through a PCIe switch.
+	nvidiaTopologyPCIe = `	GPU0	GPU1	CPU Affinity	NUMA Affinity
+GPU0	 X 	PIX	0-11		N/A
+GPU1	PIX	 X 	0-11		N/A
+`
+)
+
+func TestNvidiaFingerprint_Topology(t *testing.T) {
+	gpus := []byte("0, 16160, 16160, Tesla V100-SXM2-16GB\n1, 16160, 16160, Tesla V100-SXM2-16GB\n" +
+		"2, 16160, 16160, Tesla V100-SXM2-16GB\n3, 16160, 16160, Tesla V100-SXM2-16GB\n")
+	f := &NvidiaFingerprint{
+		logger: testLogger(),
+		smi: &NvidiaSMIQuerierMock{
+			outs: [][]byte{gpus, gpus, gpus},
+			topo: []byte(nvidiaTopology),
+		},
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.nvlink.present", "true")
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.topology", "0-1:NV12,0-2:SYS,0-3:SYS,1-2:SYS,1-3:SYS,2-3:NV4")
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.0.nvlink.peers", "1")
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.3.nvlink.peers", "2")
+
+	// GPUs connected through PCIe have no NVLink peers
+	f.smi.(*NvidiaSMIQuerierMock).topo = []byte(nvidiaTopologyPCIe)
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.nvlink.present", "false")
+	assertNodeAttributeEquals(t, node, "gpu.nvidia.topology", "0-1:PIX")
+	if a, ok := node.Attributes["gpu.nvidia.0.nvlink.peers"]; ok {
+		t.Fatalf("unexpected attribute gpu.nvidia.0.nvlink.peers found, %s", a)
+	}
+
+	// Failing to list the topology leaves it unknown
+	f.smi.(*NvidiaSMIQuerierMock).topo = nil
+	assertFingerprintOK(t, f, node)
+	for _, k := range []string{"gpu.nvidia.nvlink.present", "gpu.nvidia.topology"} {
+		if a, ok := node.Attributes[k]; ok {
+			t.Fatalf("unexpected attribute %s found, %s", k, a)
+		}
+	}
+}
+
+func TestNvidiaFingerprint_MissingBinary(t *testing.T) {
+	f := &NvidiaFingerprint{
+		logger: testLogger(),
+		smi:    &NvidiaSMIQuerierMock{err: &exec.Error{Name: "nvidia-smi", Err: exec.ErrNotFound}},
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 0 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
@@ -0,0 +1,110 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// nvmeAttrPrefix is the prefix of the attributes describing NVMe
+	// namespaces.
+	nvmeAttrPrefix = "storage.nvme."
+
+	// nvmeSectorSize is the unit of the namespace size reported by sysfs,
+	// which is independent of the block size of the device.
+	nvmeSectorSize = 512
+
+	bytesPerGigabyte = 1024 * 1024 * 1024
+)
+
+var (
+	// nvmeNamespaceRe matches the namespaces of an NVMe controller
+	nvmeNamespaceRe = regexp.MustCompile(`^nvme\d+n\d+$`)
+)
+
+// NVMeFingerprint is used to fingerprint the model, serial and size of local
+// NVMe namespaces.
+type NVMeFingerprint struct {
+	StaticFingerprinter
+	logger *log.Logger
+
+	// sysfsDir is the sysfs class directory of the NVMe controllers
+	sysfsDir string
+}
+
+// NewNVMeFingerprint is used to create a NVMe fingerprint
+func NewNVMeFingerprint(logger *log.Logger) Fingerprint {
+	f := &NVMeFingerprint{
+		logger:   logger,
+		sysfsDir: "/sys/class/nvme",
+	}
+	return f
+}
+
+func (f *NVMeFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	// Clear any attributes from the previous fingerprint
+	uniquePrefix := structs.UniqueNamespace(nvmeAttrPrefix)
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, nvmeAttrPrefix) || strings.HasPrefix(k, uniquePrefix) {
+			delete(node.Attributes, k)
+		}
+	}
+
+	controllers, err := ioutil.ReadDir(f.sysfsDir)
+	if err != nil {
+		// The directory is absent where there are no NVMe devices
+		return false, nil
+	}
+
+	applies := false
+	for _, controller := range controllers {
+		dir := filepath.Join(f.sysfsDir, controller.Name())
+		model := readSysfsValue(filepath.Join(dir, "model"))
+		serial := readSysfsValue(filepath.Join(dir, "serial"))
+
+		namespaces, err := ioutil.ReadDir(dir)
+		if err != nil {
+			f.logger.Printf("[DEBUG] fingerprint.nvme: Error reading %s: %v", dir, err)
+			continue
+		}
+
+		for _, ns := range namespaces {
+			if !nvmeNamespaceRe.MatchString(ns.Name()) {
+				continue
+			}
+
+			prefix := nvmeAttrPrefix + ns.Name()
+			if model != "" {
+				node.Attributes[prefix+".model"] = model
+			}
+			if serial != "" {
+				node.Attributes[structs.UniqueNamespace(prefix+".serial")] = serial
+			}
+
+			sectors, err := strconv.ParseUint(readSysfsValue(filepath.Join(dir, ns.Name(), "size")), 10, 64)
+			if err == nil {
+				node.Attributes[prefix+".size-gb"] = strconv.FormatUint(sectors*nvmeSectorSize/bytesPerGigabyte, 10)
+			}
+			applies = true
+		}
+	}
+
+	return applies, nil
+}
+
+// readSysfsValue returns the trimmed contents of a sysfs attribute file, or an
+// empty string if it can not be read.
+func readSysfsValue(path string) string {
+	out, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
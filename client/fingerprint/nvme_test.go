@@ -0,0 +1,83 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// writeSysfsTree writes the files of a sysfs fixture under a temporary
+// directory and returns the directory.
+func writeSysfsTree(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "sysfs")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestNVMeFingerprint(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{
+		"nvme0/model":        "Samsung SSD 970 EVO Plus 1TB            \n",
+		"nvme0/serial":       "S4EWNX0N123456      \n",
+		"nvme0/nvme0n1/size": "1953525168\n",
+		"nvme0/nvme0c0n1":    "",
+		"nvme1/model":        "Amazon EC2 NVMe Instance Storage\n",
+		"nvme1/nvme1n1/size": "3710937500\n",
+	})
+	defer os.RemoveAll(dir)
+
+	f := &NVMeFingerprint{
+		logger:   testLogger(),
+		sysfsDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "storage.nvme.nvme0n1.model", "Samsung SSD 970 EVO Plus 1TB")
+	assertNodeAttributeEquals(t, node, "unique.storage.nvme.nvme0n1.serial", "S4EWNX0N123456")
+	assertNodeAttributeEquals(t, node, "storage.nvme.nvme0n1.size-gb", "931")
+	assertNodeAttributeEquals(t, node, "storage.nvme.nvme1n1.model", "Amazon EC2 NVMe Instance Storage")
+	assertNodeAttributeEquals(t, node, "storage.nvme.nvme1n1.size-gb", "1769")
+	for _, k := range []string{"unique.storage.nvme.nvme1n1.serial", "storage.nvme.nvme0c0n1.model"} {
+		if a, ok := node.Attributes[k]; ok {
+			t.Fatalf("unexpected attribute %s found, %s", k, a)
+		}
+	}
+}
+
+func TestNVMeFingerprint_Absent(t *testing.T) {
+	f := &NVMeFingerprint{
+		logger:   testLogger(),
+		sysfsDir: filepath.Join(os.TempDir(), "nomad-nvme-missing"),
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 0 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
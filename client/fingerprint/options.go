@@ -0,0 +1,46 @@
+package fingerprint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// optionNameRe matches the names usable in the name=value pairs of client
+	// options, which are used in attribute names.
+	optionNameRe = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
+)
+
+// parseNamedValues parses the value of a client option listing comma separated
+// name=value pairs into a map of the values by name. form describes the values
+// in errors, such as "url", and validate, if non-nil, returns an error if a
+// value is invalid.
+func parseNamedValues(option, value, form string, validate func(string) error) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("invalid %s entry %q: must be of the form name=%s", option, pair, form)
+		}
+		name, v := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if !optionNameRe.MatchString(name) {
+			return nil, fmt.Errorf("invalid %s name %q: must only contain letters, digits, underscores and dashes", option, name)
+		}
+		if _, ok := values[name]; ok {
+			return nil, fmt.Errorf("duplicate %s name %q", option, name)
+		}
+		if validate != nil {
+			if err := validate(v); err != nil {
+				return nil, fmt.Errorf("invalid %s %s for %s: %v", option, form, name, err)
+			}
+		}
+		values[name] = v
+	}
+	return values, nil
+}
@@ -0,0 +1,39 @@
+package fingerprint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseNamedValues(t *testing.T) {
+	values, err := parseNamedValues("opt", " a=1 ,, b-2 = 2 ", "num", nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(values) != 2 || values["a"] != "1" || values["b-2"] != "2" {
+		t.Fatalf("unexpected values: %v", values)
+	}
+
+	for _, value := range []string{
+		"1",
+		"a=",
+		"a.b=1",
+		"a=1,a=2",
+	} {
+		if _, err := parseNamedValues("opt", value, "num", nil); err == nil {
+			t.Fatalf("expected error parsing %q", value)
+		}
+	}
+
+	validate := func(v string) error {
+		if v != "1" {
+			return fmt.Errorf("%q is not 1", v)
+		}
+		return nil
+	}
+	_, err = parseNamedValues("opt", "a=1,b=2", "num", validate)
+	if err == nil || !strings.Contains(err.Error(), `invalid opt num for b: "2" is not 1`) {
+		t.Fatalf("expected validation error, got: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package fingerprint
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	client "github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// portsInterval is the interval at which the listening ports are
+	// fingerprinted.
+	portsInterval = 30 * time.Second
+
+	portsInUseAttr = "unique.network.ports.in-use"
+
+	// tcpListenState is the socket state of listening sockets in /proc/net/tcp
+	tcpListenState = "0A"
+)
+
+// PortsFingerprint is used to fingerprint the TCP ports already listened on by
+// processes on the host.
+type PortsFingerprint struct {
+	logger *log.Logger
+
+	// procNetDir is the directory containing the tcp and tcp6 socket tables
+	procNetDir string
+}
+
+// NewPortsFingerprint is used to create a listening ports fingerprint
+func NewPortsFingerprint(logger *log.Logger) Fingerprint {
+	f := &PortsFingerprint{
+		logger:     logger,
+		procNetDir: "/proc/net",
+	}
+	return f
+}
+
+func (f *PortsFingerprint) Fingerprint(cfg *client.Config, node *structs.Node) (bool, error) {
+	ports := make(map[int]struct{})
+	read := 0
+	for _, table := range []string{"tcp", "tcp6"} {
+		path := filepath.Join(f.procNetDir, table)
+		if err := listeningPorts(path, ports); err != nil {
+			// IPv6 may be disabled
+			f.logger.Printf("[DEBUG] fingerprint.ports: Error reading %s: %v", path, err)
+			continue
+		}
+		read++
+	}
+
+	if read == 0 {
+		delete(node.Attributes, portsInUseAttr)
+		return false, nil
+	}
+
+	sorted := make([]int, 0, len(ports))
+	for port := range ports {
+		sorted = append(sorted, port)
+	}
+	sort.Ints(sorted)
+
+	values := make([]string, len(sorted))
+	for i, port := range sorted {
+		values[i] = strconv.Itoa(port)
+	}
+	node.Attributes[portsInUseAttr] = strings.Join(values, ",")
+	return true, nil
+}
+
+// listeningPorts adds the ports of the listening sockets in the socket table at
+// path to ports.
+func listeningPorts(path string, ports map[int]struct{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Lines look something like the following, with a header line first:
+	//	0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000 ...
+	//	[0] slot [1] local address [2] remote address [3] state
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[3] != tcpListenState {
+			continue
+		}
+
+		i := strings.LastIndex(fields[1], ":")
+		if i == -1 {
+			continue
+		}
+		port, err := strconv.ParseUint(fields[1][i+1:], 16, 16)
+		if err != nil {
+			continue
+		}
+		ports[int(port)] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *PortsFingerprint) Periodic() (bool, time.Duration) {
+	return true, portsInterval
+}
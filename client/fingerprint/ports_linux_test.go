@@ -0,0 +1,71 @@
+package fingerprint
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const procNetTCPFixture = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 18433 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 21931 1 0000000000000000 100 0 0 10 0
+   2: 0F02000A:0016 0202000A:D3A4 01 00000000:00000000 02:0009F4E2 00000000     0        0 22571 4 0000000000000000 20 4 29 10 -1
+`
+
+const procNetTCP6Fixture = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:0016 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 18435 1 0000000000000000 100 0 0 10 0
+   1: 00000000000000000000000001000000:1276 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 19880 1 0000000000000000 100 0 0 10 0
+   2: 00000000000000000000000001000000:1F41 00000000000000000000000001000000:C350 06 00000000:00000000 03:000011D8 00000000     0        0 0 3 0000000000000000
+`
+
+func TestPortsFingerprint(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{
+		"tcp":  procNetTCPFixture,
+		"tcp6": procNetTCP6Fixture,
+	})
+	defer os.RemoveAll(dir)
+
+	f := &PortsFingerprint{
+		logger:     testLogger(),
+		procNetDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "unique.network.ports.in-use", "22,4726,8080")
+}
+
+func TestPortsFingerprint_IPv4Only(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{
+		"tcp": procNetTCPFixture,
+	})
+	defer os.RemoveAll(dir)
+
+	f := &PortsFingerprint{
+		logger:     testLogger(),
+		procNetDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "unique.network.ports.in-use", "22,8080")
+
+	// No-op where the socket tables are unavailable
+	f.procNetDir = dir + "-missing"
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if a, ok := node.Attributes["unique.network.ports.in-use"]; ok {
+		t.Fatalf("unexpected attribute found, %s", a)
+	}
+}
@@ -0,0 +1,110 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// powerAttrPrefix is the prefix of the power source attributes
+	powerAttrPrefix = "power."
+
+	// powerInterval is the interval at which the power source is
+	// fingerprinted as the node can be plugged in and unplugged at any time.
+	powerInterval = time.Minute
+
+	powerSourceAC      = "ac"
+	powerSourceBattery = "battery"
+)
+
+// PowerFingerprint is used to fingerprint whether a node with a battery, such
+// as an edge device, is running on AC power or on its battery, and the charge
+// left in the battery.
+type PowerFingerprint struct {
+	logger *log.Logger
+
+	// sysfsDir is the sysfs class directory of the power supplies
+	sysfsDir string
+}
+
+// NewPowerFingerprint is used to create a power source fingerprint
+func NewPowerFingerprint(logger *log.Logger) Fingerprint {
+	f := &PowerFingerprint{
+		logger:   logger,
+		sysfsDir: "/sys/class/power_supply",
+	}
+	return f
+}
+
+func (f *PowerFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	// Clear any attributes from the previous fingerprint
+	uniquePrefix := structs.UniqueNamespace(powerAttrPrefix)
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, powerAttrPrefix) || strings.HasPrefix(k, uniquePrefix) {
+			delete(node.Attributes, k)
+		}
+	}
+
+	supplies, err := ioutil.ReadDir(f.sysfsDir)
+	if err != nil {
+		// The directory is absent on nodes without power supply drivers
+		return false, nil
+	}
+
+	batteries, capacity, online, charging := 0, 0, false, false
+	for _, supply := range supplies {
+		dir := filepath.Join(f.sysfsDir, supply.Name())
+		switch readSysfsValue(filepath.Join(dir, "type")) {
+		case "Battery":
+			// Batteries of peripherals, such as wireless mice, don't power
+			// the node
+			if readSysfsValue(filepath.Join(dir, "scope")) == "Device" {
+				continue
+			}
+
+			percent, err := strconv.Atoi(readSysfsValue(filepath.Join(dir, "capacity")))
+			if err != nil {
+				f.logger.Printf("[DEBUG] fingerprint.power: Error reading capacity of %s: %v", supply.Name(), err)
+				continue
+			}
+			batteries++
+			capacity += percent
+
+			// A battery that isn't discharging is being kept charged by an
+			// external supply, even if that supply isn't listed
+			if status := readSysfsValue(filepath.Join(dir, "status")); status != "" && status != "Discharging" {
+				charging = true
+			}
+		default:
+			if readSysfsValue(filepath.Join(dir, "online")) == "1" {
+				online = true
+			}
+		}
+	}
+
+	// Nodes without a battery are assumed to always be on AC power
+	if batteries == 0 {
+		return false, nil
+	}
+
+	source := powerSourceBattery
+	if online || charging {
+		source = powerSourceAC
+	}
+	node.Attributes[powerAttrPrefix+"source"] = source
+
+	node.Attributes[structs.UniqueNamespace(powerAttrPrefix+"battery.percent")] = strconv.Itoa(capacity / batteries)
+	return true, nil
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *PowerFingerprint) Periodic() (bool, time.Duration) {
+	return true, powerInterval
+}
@@ -0,0 +1,109 @@
+package fingerprint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestPowerFingerprint(t *testing.T) {
+	cases := []struct {
+		name    string
+		files   map[string]string
+		source  string
+		percent string
+	}{
+		{
+			name: "ac",
+			files: map[string]string{
+				"AC/type":       "Mains\n",
+				"AC/online":     "1\n",
+				"BAT0/type":     "Battery\n",
+				"BAT0/status":   "Full\n",
+				"BAT0/capacity": "100\n",
+			},
+			source:  "ac",
+			percent: "100",
+		},
+		{
+			name: "battery",
+			files: map[string]string{
+				"AC/type":                  "Mains\n",
+				"AC/online":                "0\n",
+				"BAT0/type":                "Battery\n",
+				"BAT0/status":              "Discharging\n",
+				"BAT0/capacity":            "40\n",
+				"BAT1/type":                "Battery\n",
+				"BAT1/status":              "Discharging\n",
+				"BAT1/capacity":            "61\n",
+				"hidpp_battery_0/type":     "Battery\n",
+				"hidpp_battery_0/scope":    "Device\n",
+				"hidpp_battery_0/capacity": "5\n",
+			},
+			source:  "battery",
+			percent: "50",
+		},
+		{
+			name: "charging without mains supply",
+			files: map[string]string{
+				"BAT0/type":     "Battery\n",
+				"BAT0/status":   "Charging\n",
+				"BAT0/capacity": "80\n",
+			},
+			source:  "ac",
+			percent: "80",
+		},
+	}
+
+	for _, c := range cases {
+		dir := writeSysfsTree(t, c.files)
+		defer os.RemoveAll(dir)
+
+		f := &PowerFingerprint{logger: testLogger(), sysfsDir: dir}
+		node := &structs.Node{Attributes: make(map[string]string)}
+
+		ok, err := f.Fingerprint(&config.Config{}, node)
+		if err != nil {
+			t.Fatalf("%s: err: %v", c.name, err)
+		}
+		if !ok {
+			t.Fatalf("%s: should apply", c.name)
+		}
+		assertNodeAttributeEquals(t, node, "power.source", c.source)
+		assertNodeAttributeEquals(t, node, "unique.power.battery.percent", c.percent)
+	}
+}
+
+func TestPowerFingerprint_NoBattery(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{
+		"AC/type":                  "Mains\n",
+		"AC/online":                "1\n",
+		"hidpp_battery_0/type":     "Battery\n",
+		"hidpp_battery_0/scope":    "Device\n",
+		"hidpp_battery_0/capacity": "5\n",
+	})
+	defer os.RemoveAll(dir)
+
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"power.source":                 "battery",
+			"unique.power.battery.percent": "10",
+		},
+	}
+	for _, sysfsDir := range []string{dir, filepath.Join(dir, "missing")} {
+		f := &PowerFingerprint{logger: testLogger(), sysfsDir: sysfsDir}
+		ok, err := f.Fingerprint(&config.Config{}, node)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if ok {
+			t.Fatalf("should not apply")
+		}
+		if len(node.Attributes) != 0 {
+			t.Fatalf("unexpected attributes: %v", node.Attributes)
+		}
+	}
+}
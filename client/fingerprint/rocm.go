@@ -0,0 +1,106 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// rocmSMIModelKey and rocmSMIMemoryKey are the keys of the rocm-smi JSON
+	// output holding the GPU model and the total VRAM in bytes.
+	rocmSMIModelKey  = "Card series"
+	rocmSMIMemoryKey = "VRAM Total Memory (B)"
+)
+
+// ROCmFingerprint is used to fingerprint AMD GPUs using rocm-smi
+type ROCmFingerprint struct {
+	StaticFingerprinter
+	logger *log.Logger
+	smi    ROCmSMIQuerier
+}
+
+// An interface to isolate calls to rocm-smi
+// This facilitates testing where we can return canned output
+type ROCmSMIQuerier interface {
+	// Query returns the JSON output of rocm-smi listing the product name and
+	// memory of every card.
+	Query() ([]byte, error)
+}
+
+// Implements the querier which calls rocm-smi found in the $PATH
+type DefaultROCmSMIQuerier struct {
+}
+
+func (d *DefaultROCmSMIQuerier) Query() ([]byte, error) {
+	path, err := exec.LookPath("rocm-smi")
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command(path, "--showproductname", "--showmeminfo", "vram", "--json").Output()
+}
+
+// NewROCmFingerprint is used to create an AMD GPU fingerprint
+func NewROCmFingerprint(logger *log.Logger) Fingerprint {
+	f := &ROCmFingerprint{
+		logger: logger,
+		smi:    &DefaultROCmSMIQuerier{},
+	}
+	return f
+}
+
+func (f *ROCmFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	out, err := f.smi.Query()
+	if err != nil {
+		// rocm-smi is only present on nodes with AMD GPUs
+		if _, ok := err.(*exec.Error); !ok {
+			f.logger.Printf("[WARN] fingerprint.rocm: Error calling rocm-smi: %v", err)
+		}
+		return false, nil
+	}
+
+	// Output looks something like:
+	//	{"card0": {"Card series": "Vega 20", "VRAM Total Memory (B)": "34342961152"}}
+	var cards map[string]map[string]string
+	if err := json.Unmarshal(out, &cards); err != nil {
+		f.logger.Printf("[WARN] fingerprint.rocm: Error decoding rocm-smi output: %v", err)
+		return false, nil
+	}
+
+	// Order the cards by index rather than lexically so card10 follows card9
+	ids := make([]int, 0, len(cards))
+	for name := range cards {
+		id, err := strconv.Atoi(strings.TrimPrefix(name, "card"))
+		if err != nil || !strings.HasPrefix(name, "card") {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for i, id := range ids {
+		card := cards[fmt.Sprintf("card%d", id)]
+		prefix := fmt.Sprintf("gpu.amd.%d.", i)
+
+		if model := strings.TrimSpace(card[rocmSMIModelKey]); model != "" {
+			node.Attributes[prefix+"model"] = model
+		}
+		if bytes, err := strconv.ParseUint(strings.TrimSpace(card[rocmSMIMemoryKey]), 10, 64); err == nil {
+			node.Attributes[prefix+"memory-mb"] = strconv.FormatUint(bytes/bytesPerMegabyte, 10)
+		}
+	}
+
+	if len(ids) == 0 {
+		return false, nil
+	}
+
+	node.Attributes["gpu.amd.count"] = strconv.Itoa(len(ids))
+	return true, nil
+}
@@ -0,0 +1,63 @@
+package fingerprint
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// A fake rocm-smi querier that returns canned output
+type ROCmSMIQuerierMock struct {
+	out []byte
+	err error
+}
+
+func (r *ROCmSMIQuerierMock) Query() ([]byte, error) {
+	return r.out, r.err
+}
+
+const rocmSMIOutput = `{
+  "card0": {"Card series": "Vega 20", "VRAM Total Memory (B)": "34342961152"},
+  "card1": {"Card series": "Vega 20", "VRAM Total Memory (B)": "34342961152"},
+  "system": {"Driver version": "5.6.0"}
+}`
+
+func TestROCmFingerprint(t *testing.T) {
+	f := &ROCmFingerprint{
+		logger: testLogger(),
+		smi:    &ROCmSMIQuerierMock{out: []byte(rocmSMIOutput)},
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "gpu.amd.count", "2")
+	assertNodeAttributeEquals(t, node, "gpu.amd.0.model", "Vega 20")
+	assertNodeAttributeEquals(t, node, "gpu.amd.0.memory-mb", "32752")
+	assertNodeAttributeEquals(t, node, "gpu.amd.1.model", "Vega 20")
+	assertNodeAttributeEquals(t, node, "gpu.amd.1.memory-mb", "32752")
+}
+
+func TestROCmFingerprint_MissingBinary(t *testing.T) {
+	f := &ROCmFingerprint{
+		logger: testLogger(),
+		smi:    &ROCmSMIQuerierMock{err: &exec.Error{Name: "rocm-smi", Err: exec.ErrNotFound}},
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if a, ok := node.Attributes["gpu.amd.count"]; ok {
+		t.Fatalf("unexpected attribute found, %s", a)
+	}
+}
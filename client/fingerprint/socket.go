@@ -0,0 +1,80 @@
+package fingerprint
+
+import (
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	client "github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// socketAttrPrefix is the prefix of the attributes recording whether the
+	// configured Unix sockets are present.
+	socketAttrPrefix = "host.socket."
+
+	// socketPathsOption is the client option listing the Unix sockets to
+	// fingerprint.
+	socketPathsOption = "fingerprint.socket.paths"
+
+	// socketInterval is the interval at which the sockets are fingerprinted
+	// as they are created and removed when their daemons start and stop.
+	socketInterval = 30 * time.Second
+)
+
+var (
+	// hostPathSanitizeRe matches the characters of a host path that are
+	// replaced to form its attribute name.
+	hostPathSanitizeRe = regexp.MustCompile(`[^a-zA-Z0-9.\-]+`)
+)
+
+// SocketFingerprint is used to fingerprint whether the configured Unix
+// sockets, such as the Docker daemon's, are present.
+type SocketFingerprint struct {
+	logger *log.Logger
+}
+
+// NewSocketFingerprint is used to create a Unix socket fingerprint
+func NewSocketFingerprint(logger *log.Logger) Fingerprint {
+	f := &SocketFingerprint{logger: logger}
+	return f
+}
+
+func (f *SocketFingerprint) Fingerprint(cfg *client.Config, node *structs.Node) (bool, error) {
+	// Clear any attributes from the previous fingerprint
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, socketAttrPrefix) {
+			delete(node.Attributes, k)
+		}
+	}
+
+	paths := cfg.ReadStringListToMap(socketPathsOption)
+	if len(paths) == 0 {
+		return false, nil
+	}
+
+	for path := range paths {
+		present := false
+		if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			present = true
+		}
+
+		node.Attributes[socketAttrPrefix+sanitizeHostPath(path)+".present"] = strconv.FormatBool(present)
+	}
+	return true, nil
+}
+
+// sanitizeHostPath returns the host path in a form usable in an attribute
+// name. Ex: /var/run/docker.sock becomes var_run_docker.sock
+func sanitizeHostPath(path string) string {
+	return hostPathSanitizeRe.ReplaceAllString(strings.Trim(path, "/"), "_")
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *SocketFingerprint) Periodic() (bool, time.Duration) {
+	return true, socketInterval
+}
@@ -0,0 +1,92 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestSocketFingerprint(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix sockets are not supported")
+	}
+
+	dir, err := ioutil.TempDir("", "socket")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	sock := filepath.Join(dir, "docker.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer l.Close()
+
+	// A regular file is not a socket
+	file := filepath.Join(dir, "containerd.sock")
+	if err := ioutil.WriteFile(file, nil, 0644); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	missing := filepath.Join(dir, "podman.sock")
+
+	f := NewSocketFingerprint(testLogger())
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"host.socket.stale.sock.present": "true",
+		},
+	}
+	cfg := &config.Config{
+		Options: map[string]string{
+			"fingerprint.socket.paths": strings.Join([]string{sock, file, missing}, ","),
+		},
+	}
+
+	ok, err := f.Fingerprint(cfg, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("should apply")
+	}
+	assertNodeAttributeEquals(t, node, "host.socket."+sanitizeHostPath(sock)+".present", "true")
+	assertNodeAttributeEquals(t, node, "host.socket."+sanitizeHostPath(file)+".present", "false")
+	assertNodeAttributeEquals(t, node, "host.socket."+sanitizeHostPath(missing)+".present", "false")
+	if _, ok := node.Attributes["host.socket.stale.sock.present"]; ok {
+		t.Fatalf("expected stale socket attribute to be removed")
+	}
+
+	// The fingerprinter doesn't apply without any configured sockets
+	ok, err = f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 0 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
+
+func TestSocketFingerprint_SanitizePath(t *testing.T) {
+	cases := map[string]string{
+		"/var/run/docker.sock":              "var_run_docker.sock",
+		"/run/containerd/containerd.sock":   "run_containerd_containerd.sock",
+		"/run/user/1000/podman/podman.sock": "run_user_1000_podman_podman.sock",
+		"/tmp/my socket":                    "tmp_my_socket",
+	}
+	for path, expected := range cases {
+		if actual := sanitizeHostPath(path); actual != expected {
+			t.Fatalf("expected %q to be sanitized to %q, got %q", path, expected, actual)
+		}
+	}
+}
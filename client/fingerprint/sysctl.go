@@ -0,0 +1,122 @@
+package fingerprint
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// sysctlAttrPrefix is the prefix of the attributes of the configured
+	// sysctls.
+	sysctlAttrPrefix = "sysctl."
+
+	// sysctlMinimumsOption is the client option listing the sysctls to
+	// fingerprint and their minimum values as comma separated key=min pairs.
+	sysctlMinimumsOption = "fingerprint.sysctl.minimums"
+
+	// sysctlInterval is the interval at which the sysctls are fingerprinted
+	// as they can be changed at runtime.
+	sysctlInterval = 30 * time.Second
+)
+
+var (
+	// sysctlKeyRe matches the keys of sysctls, such as net.core.somaxconn
+	sysctlKeyRe = regexp.MustCompile(`^[a-zA-Z0-9_\-]+(\.[a-zA-Z0-9_\-]+)*$`)
+)
+
+// SysctlFingerprint is used to fingerprint the values of the configured
+// sysctls and whether they meet the minimums jobs require, such as
+// vm.max_map_count for Elasticsearch.
+type SysctlFingerprint struct {
+	logger *log.Logger
+
+	// procSysDir is the directory the sysctls are read from
+	procSysDir string
+}
+
+// NewSysctlFingerprint is used to create a sysctl fingerprint
+func NewSysctlFingerprint(logger *log.Logger) Fingerprint {
+	f := &SysctlFingerprint{
+		logger:     logger,
+		procSysDir: "/proc/sys",
+	}
+	return f
+}
+
+func (f *SysctlFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	// Clear any attributes from the previous fingerprint
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, sysctlAttrPrefix) {
+			delete(node.Attributes, k)
+		}
+	}
+
+	minimums, err := parseSysctlMinimums(cfg.Read(sysctlMinimumsOption))
+	if err != nil {
+		return false, err
+	}
+
+	applies := false
+	for key, min := range minimums {
+		value := strings.Join(strings.Fields(readSysfsValue(filepath.Join(f.procSysDir, strings.Replace(key, ".", "/", -1)))), " ")
+		if value == "" {
+			f.logger.Printf("[DEBUG] fingerprint.sysctl: sysctl %s not found", key)
+			continue
+		}
+
+		prefix := sysctlAttrPrefix + key
+		node.Attributes[prefix+".value"] = value
+		applies = true
+
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			f.logger.Printf("[WARN] fingerprint.sysctl: sysctl %s value %q is not an integer", key, value)
+			continue
+		}
+		node.Attributes[prefix+".meets-min"] = strconv.FormatBool(n >= min)
+	}
+	return applies, nil
+}
+
+// parseSysctlMinimums parses the value of the sysctl minimums option into the
+// minimum of each sysctl by key.
+func parseSysctlMinimums(value string) (map[string]int64, error) {
+	minimums := make(map[string]int64)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid %s entry %q: must be of the form key=min", sysctlMinimumsOption, pair)
+		}
+		key := strings.TrimSpace(parts[0])
+		if !sysctlKeyRe.MatchString(key) {
+			return nil, fmt.Errorf("invalid %s key %q", sysctlMinimumsOption, key)
+		}
+		if _, ok := minimums[key]; ok {
+			return nil, fmt.Errorf("duplicate %s key %q", sysctlMinimumsOption, key)
+		}
+		min, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s minimum for %s: %v", sysctlMinimumsOption, key, err)
+		}
+		minimums[key] = min
+	}
+	return minimums, nil
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *SysctlFingerprint) Periodic() (bool, time.Duration) {
+	return true, sysctlInterval
+}
@@ -0,0 +1,116 @@
+package fingerprint
+
+import (
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// sysctlNetworkInterval is the interval at which the network sysctls are
+	// fingerprinted as they can be changed at runtime.
+	sysctlNetworkInterval = 30 * time.Second
+
+	ipForwardAttr = "network.ip-forward.enabled"
+	bridgeNFAttr  = "network.bridge-nf.enabled"
+	portRangeAttr = "network.ephemeral-port-range"
+	somaxconnAttr = "network.somaxconn"
+
+	// ipForwardSysctl and bridgeNFSysctl are the paths of the sysctls under
+	// /proc/sys. The bridge-nf sysctl only exists once the br_netfilter module
+	// is loaded.
+	ipForwardSysctl = "net/ipv4/ip_forward"
+	bridgeNFSysctl  = "net/bridge/bridge-nf-call-iptables"
+
+	// portRangeSysctl is the range of the local ports used for outgoing
+	// connections and somaxconnSysctl is the maximum listen backlog.
+	portRangeSysctl = "net/ipv4/ip_local_port_range"
+	somaxconnSysctl = "net/core/somaxconn"
+)
+
+// SysctlNetworkFingerprint is used to fingerprint whether the kernel is
+// configured to forward and filter the traffic of bridge networks, along with
+// the limits on the connections it can make and accept.
+type SysctlNetworkFingerprint struct {
+	logger *log.Logger
+
+	// procSysDir is the directory the sysctls are read from
+	procSysDir string
+}
+
+// NewSysctlNetworkFingerprint is used to create a network sysctl fingerprint
+func NewSysctlNetworkFingerprint(logger *log.Logger) Fingerprint {
+	f := &SysctlNetworkFingerprint{
+		logger:     logger,
+		procSysDir: "/proc/sys",
+	}
+	return f
+}
+
+func (f *SysctlNetworkFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	ipForward := readSysfsValue(filepath.Join(f.procSysDir, ipForwardSysctl))
+	if ipForward == "" {
+		// procfs is not available
+		delete(node.Attributes, ipForwardAttr)
+		delete(node.Attributes, bridgeNFAttr)
+		delete(node.Attributes, portRangeAttr)
+		delete(node.Attributes, somaxconnAttr)
+		return false, nil
+	}
+
+	// A missing bridge-nf sysctl means bridged traffic isn't passed to
+	// iptables, which is the same as it being disabled.
+	bridgeNF := readSysfsValue(filepath.Join(f.procSysDir, bridgeNFSysctl))
+
+	node.Attributes[ipForwardAttr] = sysctlEnabled(ipForward)
+	node.Attributes[bridgeNFAttr] = sysctlEnabled(bridgeNF)
+
+	portRange := readSysfsValue(filepath.Join(f.procSysDir, portRangeSysctl))
+	if r, ok := parsePortRange(portRange); ok {
+		node.Attributes[portRangeAttr] = r
+	} else {
+		delete(node.Attributes, portRangeAttr)
+	}
+
+	somaxconn := readSysfsValue(filepath.Join(f.procSysDir, somaxconnSysctl))
+	if _, err := strconv.Atoi(somaxconn); err == nil {
+		node.Attributes[somaxconnAttr] = somaxconn
+	} else {
+		delete(node.Attributes, somaxconnAttr)
+	}
+	return true, nil
+}
+
+// parsePortRange parses the ip_local_port_range sysctl, which holds the first
+// and last port separated by whitespace, such as "32768	60999", and returns
+// the range as "32768-60999".
+func parsePortRange(value string) (string, bool) {
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return "", false
+	}
+	for _, field := range fields {
+		if _, err := strconv.Atoi(field); err != nil {
+			return "", false
+		}
+	}
+	return fields[0] + "-" + fields[1], true
+}
+
+// sysctlEnabled returns whether a boolean sysctl value is set as a string
+func sysctlEnabled(value string) string {
+	if value == "1" {
+		return "true"
+	}
+	return "false"
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *SysctlNetworkFingerprint) Periodic() (bool, time.Duration) {
+	return true, sysctlNetworkInterval
+}
@@ -0,0 +1,112 @@
+package fingerprint
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestSysctlNetworkFingerprint(t *testing.T) {
+	cases := []struct {
+		name      string
+		sysctls   map[string]string
+		ipForward string
+		bridgeNF  string
+		portRange string
+		somaxconn string
+	}{
+		{
+			name: "enabled",
+			sysctls: map[string]string{
+				"net/ipv4/ip_forward":                "1\n",
+				"net/bridge/bridge-nf-call-iptables": "1\n",
+				"net/ipv4/ip_local_port_range":       "32768\t60999\n",
+				"net/core/somaxconn":                 "4096\n",
+			},
+			ipForward: "true",
+			bridgeNF:  "true",
+			portRange: "32768-60999",
+			somaxconn: "4096",
+		},
+		{
+			name: "disabled",
+			sysctls: map[string]string{
+				"net/ipv4/ip_forward":                "0\n",
+				"net/bridge/bridge-nf-call-iptables": "0\n",
+			},
+			ipForward: "false",
+			bridgeNF:  "false",
+		},
+		{
+			name: "br_netfilter not loaded",
+			sysctls: map[string]string{
+				"net/ipv4/ip_forward": "1\n",
+			},
+			ipForward: "true",
+			bridgeNF:  "false",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := writeSysfsTree(t, c.sysctls)
+			defer os.RemoveAll(dir)
+
+			f := &SysctlNetworkFingerprint{
+				logger:     testLogger(),
+				procSysDir: dir,
+			}
+			node := &structs.Node{
+				Attributes: make(map[string]string),
+			}
+
+			assertFingerprintOK(t, f, node)
+			assertNodeAttributeEquals(t, node, "network.ip-forward.enabled", c.ipForward)
+			assertNodeAttributeEquals(t, node, "network.bridge-nf.enabled", c.bridgeNF)
+			for k, v := range map[string]string{
+				"network.ephemeral-port-range": c.portRange,
+				"network.somaxconn":            c.somaxconn,
+			} {
+				if v == "" {
+					if a, ok := node.Attributes[k]; ok {
+						t.Fatalf("unexpected attribute %s found, %s", k, a)
+					}
+					continue
+				}
+				assertNodeAttributeEquals(t, node, k, v)
+			}
+		})
+	}
+}
+
+func TestSysctlNetworkFingerprint_Unsupported(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{})
+	defer os.RemoveAll(dir)
+
+	f := &SysctlNetworkFingerprint{
+		logger:     testLogger(),
+		procSysDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"network.ip-forward.enabled": "true",
+			"network.bridge-nf.enabled":  "true",
+			"network.somaxconn":          "128",
+		},
+	}
+
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	for _, k := range []string{"network.ip-forward.enabled", "network.bridge-nf.enabled", "network.somaxconn"} {
+		if a, ok := node.Attributes[k]; ok {
+			t.Fatalf("unexpected attribute %s found, %s", k, a)
+		}
+	}
+}
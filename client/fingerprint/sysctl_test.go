@@ -0,0 +1,91 @@
+package fingerprint
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestSysctlFingerprint(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{
+		"net/core/somaxconn":           "4096\n",
+		"vm/max_map_count":             "65530\n",
+		"net/ipv4/ip_local_port_range": "32768\t60999\n",
+	})
+	defer os.RemoveAll(dir)
+
+	f := &SysctlFingerprint{
+		logger:     testLogger(),
+		procSysDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"sysctl.fs.file-max.value": "100",
+		},
+	}
+	cfg := &config.Config{
+		Options: map[string]string{
+			"fingerprint.sysctl.minimums": "net.core.somaxconn=1024, vm.max_map_count=262144,net.ipv4.ip_local_port_range=1024,kernel.missing=1",
+		},
+	}
+
+	ok, err := f.Fingerprint(cfg, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("should apply")
+	}
+	assertNodeAttributeEquals(t, node, "sysctl.net.core.somaxconn.value", "4096")
+	assertNodeAttributeEquals(t, node, "sysctl.net.core.somaxconn.meets-min", "true")
+	assertNodeAttributeEquals(t, node, "sysctl.vm.max_map_count.value", "65530")
+	assertNodeAttributeEquals(t, node, "sysctl.vm.max_map_count.meets-min", "false")
+	assertNodeAttributeEquals(t, node, "sysctl.net.ipv4.ip_local_port_range.value", "32768 60999")
+	for _, k := range []string{
+		"sysctl.net.ipv4.ip_local_port_range.meets-min",
+		"sysctl.kernel.missing.value",
+		"sysctl.fs.file-max.value",
+	} {
+		if a, ok := node.Attributes[k]; ok {
+			t.Fatalf("unexpected attribute %s found, %s", k, a)
+		}
+	}
+
+	// Without any sysctls configured the fingerprinter doesn't apply
+	ok, err = f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 0 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
+
+func TestSysctlFingerprint_ParseMinimums(t *testing.T) {
+	minimums, err := parseSysctlMinimums(" vm.max_map_count=262144 , net.core.somaxconn = 1024")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(minimums) != 2 ||
+		minimums["vm.max_map_count"] != 262144 ||
+		minimums["net.core.somaxconn"] != 1024 {
+		t.Fatalf("unexpected minimums: %v", minimums)
+	}
+
+	for _, value := range []string{
+		"vm.max_map_count",
+		"vm.max_map_count=lots",
+		"vm..max_map_count=1",
+		"../../etc/passwd=1",
+		"vm.max_map_count=1,vm.max_map_count=2",
+	} {
+		if _, err := parseSysctlMinimums(value); err == nil {
+			t.Fatalf("expected error parsing %q", value)
+		}
+	}
+}
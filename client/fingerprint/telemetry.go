@@ -0,0 +1,95 @@
+package fingerprint
+
+import (
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// telemetryAttrPrefix is the prefix of the attributes recording whether
+	// the configured telemetry sinks are reachable.
+	telemetryAttrPrefix = "telemetry."
+
+	// telemetrySinksOption is the client option listing the telemetry sinks
+	// to fingerprint as comma separated name=host:port pairs.
+	telemetrySinksOption = "fingerprint.telemetry.sinks"
+
+	// telemetryDialTimeout is the length of time to wait when connecting to a
+	// sink. Sinks are expected to be local, so it is kept short.
+	telemetryDialTimeout = 1 * time.Second
+
+	// telemetryInterval is the interval at which the sinks are fingerprinted
+	// as they are started and stopped independently of the client.
+	telemetryInterval = 30 * time.Second
+)
+
+// dialer connects to the address on the named network within the timeout.
+type dialer func(network, address string, timeout time.Duration) (net.Conn, error)
+
+// TelemetryFingerprint is used to fingerprint whether the configured
+// telemetry sinks, such as a local statsd or Prometheus pushgateway, accept
+// connections.
+type TelemetryFingerprint struct {
+	logger *log.Logger
+	dial   dialer
+}
+
+// NewTelemetryFingerprint is used to create a telemetry sink fingerprint
+func NewTelemetryFingerprint(logger *log.Logger) Fingerprint {
+	f := &TelemetryFingerprint{
+		logger: logger,
+		dial:   net.DialTimeout,
+	}
+	return f
+}
+
+func (f *TelemetryFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	// Clear any attributes from the previous fingerprint
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, telemetryAttrPrefix) {
+			delete(node.Attributes, k)
+		}
+	}
+
+	sinks, err := parseTelemetrySinks(cfg.Read(telemetrySinksOption))
+	if err != nil {
+		return false, err
+	}
+	if len(sinks) == 0 {
+		return false, nil
+	}
+
+	for name, addr := range sinks {
+		reachable := true
+		conn, err := f.dial("tcp", addr, telemetryDialTimeout)
+		if err != nil {
+			f.logger.Printf("[DEBUG] fingerprint.telemetry: sink %s at %s not reachable: %v", name, addr, err)
+			reachable = false
+		} else {
+			conn.Close()
+		}
+
+		node.Attributes[telemetryAttrPrefix+name+".reachable"] = strconv.FormatBool(reachable)
+	}
+	return true, nil
+}
+
+// parseTelemetrySinks parses the value of the telemetry sinks option into the
+// address of each sink by name.
+func parseTelemetrySinks(value string) (map[string]string, error) {
+	return parseNamedValues(telemetrySinksOption, value, "host:port", func(addr string) error {
+		_, _, err := net.SplitHostPort(addr)
+		return err
+	})
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *TelemetryFingerprint) Periodic() (bool, time.Duration) {
+	return true, telemetryInterval
+}
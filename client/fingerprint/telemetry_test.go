@@ -0,0 +1,96 @@
+package fingerprint
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// testDialer returns a dialer that only connects to the given addresses
+func testDialer(reachable ...string) dialer {
+	return func(network, address string, timeout time.Duration) (net.Conn, error) {
+		if network != "tcp" {
+			return nil, fmt.Errorf("unexpected network %q", network)
+		}
+		for _, addr := range reachable {
+			if addr == address {
+				client, server := net.Pipe()
+				server.Close()
+				return client, nil
+			}
+		}
+		return nil, fmt.Errorf("dial tcp %s: connection refused", address)
+	}
+}
+
+func TestTelemetryFingerprint(t *testing.T) {
+	f := &TelemetryFingerprint{
+		logger: testLogger(),
+		dial:   testDialer("127.0.0.1:9091"),
+	}
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"telemetry.removed.reachable": "true",
+		},
+	}
+	cfg := &config.Config{
+		Options: map[string]string{
+			"fingerprint.telemetry.sinks": "statsd=127.0.0.1:8125, pushgateway=127.0.0.1:9091",
+		},
+	}
+
+	ok, err := f.Fingerprint(cfg, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("should apply")
+	}
+	assertNodeAttributeEquals(t, node, "telemetry.statsd.reachable", "false")
+	assertNodeAttributeEquals(t, node, "telemetry.pushgateway.reachable", "true")
+	if a, ok := node.Attributes["telemetry.removed.reachable"]; ok {
+		t.Fatalf("unexpected attribute telemetry.removed.reachable found, %s", a)
+	}
+
+	// Without any sinks configured the fingerprinter doesn't apply
+	ok, err = f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if a, ok := node.Attributes["telemetry.pushgateway.reachable"]; ok {
+		t.Fatalf("unexpected attribute telemetry.pushgateway.reachable found, %s", a)
+	}
+}
+
+func TestTelemetryFingerprint_InvalidSinks(t *testing.T) {
+	f := &TelemetryFingerprint{
+		logger: testLogger(),
+		dial:   testDialer(),
+	}
+
+	for _, sinks := range []string{
+		"statsd",
+		"stats d=127.0.0.1:8125",
+		"statsd=127.0.0.1",
+		"statsd=127.0.0.1:8125,statsd=127.0.0.1:9125",
+	} {
+		node := &structs.Node{
+			Attributes: make(map[string]string),
+		}
+		cfg := &config.Config{
+			Options: map[string]string{
+				"fingerprint.telemetry.sinks": sinks,
+			},
+		}
+		if _, err := f.Fingerprint(cfg, node); err == nil {
+			t.Fatalf("expected error for sinks %q", sinks)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package fingerprint
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// timezoneFile holds the name of the time zone on Debian based systems
+	timezoneFile = "etc/timezone"
+
+	// localtimeFile is a symlink to the time zone's file in the zoneinfo
+	// database on most other systems.
+	localtimeFile = "etc/localtime"
+
+	// zoneinfoDir is the path component preceding the time zone name in the
+	// target of the localtime symlink.
+	zoneinfoDir = "zoneinfo/"
+)
+
+// TimezoneFingerprint is used to fingerprint the configured time zone
+type TimezoneFingerprint struct {
+	StaticFingerprinter
+	logger *log.Logger
+
+	// root is the filesystem root the time zone configuration is read from
+	root string
+}
+
+// NewTimezoneFingerprint is used to create a time zone fingerprint
+func NewTimezoneFingerprint(logger *log.Logger) Fingerprint {
+	f := &TimezoneFingerprint{
+		logger: logger,
+		root:   "/",
+	}
+	return f
+}
+
+func (f *TimezoneFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	tz := f.timezone()
+	if tz == "" {
+		delete(node.Attributes, "os.timezone")
+		return false, nil
+	}
+
+	node.Attributes["os.timezone"] = tz
+	return true, nil
+}
+
+// timezone returns the name of the configured time zone, such as
+// America/New_York, or an empty string if it can not be determined.
+func (f *TimezoneFingerprint) timezone() string {
+	if tz := readSysfsValue(filepath.Join(f.root, timezoneFile)); tz != "" {
+		return tz
+	}
+
+	// The symlink looks something like:
+	//	/etc/localtime -> /usr/share/zoneinfo/America/New_York
+	target, err := os.Readlink(filepath.Join(f.root, localtimeFile))
+	if err != nil {
+		return ""
+	}
+	i := strings.LastIndex(target, zoneinfoDir)
+	if i == -1 {
+		return ""
+	}
+	return target[i+len(zoneinfoDir):]
+}
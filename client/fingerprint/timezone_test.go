@@ -0,0 +1,88 @@
+package fingerprint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestTimezoneFingerprint(t *testing.T) {
+	cases := []struct {
+		name      string
+		files     map[string]string
+		localtime string
+		expected  string
+	}{
+		{
+			name:     "timezone file",
+			files:    map[string]string{"etc/timezone": "Europe/Berlin\n"},
+			expected: "Europe/Berlin",
+		},
+		{
+			name:      "localtime symlink",
+			localtime: "/usr/share/zoneinfo/America/New_York",
+			expected:  "America/New_York",
+		},
+		{
+			name:      "timezone file preferred",
+			files:     map[string]string{"etc/timezone": "Etc/UTC\n"},
+			localtime: "/usr/share/zoneinfo/America/New_York",
+			expected:  "Etc/UTC",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := writeSysfsTree(t, c.files)
+			defer os.RemoveAll(dir)
+
+			if c.localtime != "" {
+				if err := os.MkdirAll(filepath.Join(dir, "etc"), 0755); err != nil {
+					t.Fatalf("err: %v", err)
+				}
+				if err := os.Symlink(c.localtime, filepath.Join(dir, "etc", "localtime")); err != nil {
+					t.Fatalf("err: %v", err)
+				}
+			}
+
+			f := &TimezoneFingerprint{
+				logger: testLogger(),
+				root:   dir,
+			}
+			node := &structs.Node{
+				Attributes: make(map[string]string),
+			}
+
+			assertFingerprintOK(t, f, node)
+			assertNodeAttributeEquals(t, node, "os.timezone", c.expected)
+		})
+	}
+}
+
+func TestTimezoneFingerprint_Undeterminable(t *testing.T) {
+	// A localtime file that isn't a symlink doesn't name the time zone
+	dir := writeSysfsTree(t, map[string]string{"etc/localtime": "TZif2"})
+	defer os.RemoveAll(dir)
+
+	f := &TimezoneFingerprint{
+		logger: testLogger(),
+		root:   dir,
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if a, ok := node.Attributes["os.timezone"]; ok {
+		t.Fatalf("unexpected attribute found, %s", a)
+	}
+}
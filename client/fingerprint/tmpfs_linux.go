@@ -0,0 +1,164 @@
+package fingerprint
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	client "github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// tmpfsAttrPrefix is the prefix of the attributes describing tmpfs mounts
+	tmpfsAttrPrefix = "storage.tmpfs."
+
+	// tmpfsPathsOption is the client option listing the tmpfs mounts to
+	// fingerprint.
+	tmpfsPathsOption = "fingerprint.tmpfs.paths"
+
+	// tmpfsDefaultPaths are the tmpfs mounts fingerprinted by default
+	tmpfsDefaultPaths = "/dev/shm"
+
+	// tmpfsInterval is the interval at which tmpfs mounts are fingerprinted so
+	// that the free space stays current.
+	tmpfsInterval = 15 * time.Second
+
+	// procMounts is the file listing the mounted filesystems
+	procMounts = "/proc/mounts"
+)
+
+// TmpfsFingerprint is used to fingerprint the size and free space of
+// configured tmpfs mounts such as /dev/shm.
+type TmpfsFingerprint struct {
+	logger     *log.Logger
+	mountsFile string
+	statter    FilesystemStatter
+}
+
+// An interface to isolate calls to statfs
+// This facilitates testing where we can return fake filesystem sizes
+type FilesystemStatter interface {
+	// Statfs returns the total and free bytes of the filesystem mounted at
+	// path.
+	Statfs(path string) (total, free uint64, err error)
+}
+
+// Implements the statter which calls statfs directly
+type DefaultFilesystemStatter struct {
+}
+
+func (d *DefaultFilesystemStatter) Statfs(path string) (uint64, uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	bsize := uint64(stat.Bsize)
+	return stat.Blocks * bsize, stat.Bavail * bsize, nil
+}
+
+// NewTmpfsFingerprint is used to create a tmpfs fingerprint
+func NewTmpfsFingerprint(logger *log.Logger) Fingerprint {
+	f := &TmpfsFingerprint{
+		logger:     logger,
+		mountsFile: procMounts,
+		statter:    &DefaultFilesystemStatter{},
+	}
+	return f
+}
+
+func (f *TmpfsFingerprint) Fingerprint(cfg *client.Config, node *structs.Node) (bool, error) {
+	// Clear any attributes from the previous fingerprint
+	uniquePrefix := structs.UniqueNamespace(tmpfsAttrPrefix)
+	for k := range node.Attributes {
+		if strings.HasPrefix(k, tmpfsAttrPrefix) || strings.HasPrefix(k, uniquePrefix) {
+			delete(node.Attributes, k)
+		}
+	}
+
+	mounts, err := f.tmpfsMounts()
+	if err != nil {
+		f.logger.Printf("[WARN] fingerprint.tmpfs: Error reading %s: %v", f.mountsFile, err)
+		return false, nil
+	}
+
+	applies := false
+	for path := range cfg.ReadStringListToMapDefault(tmpfsPathsOption, tmpfsDefaultPaths) {
+		if _, ok := mounts[path]; !ok {
+			f.logger.Printf("[DEBUG] fingerprint.tmpfs: %s is not a tmpfs mount", path)
+			continue
+		}
+
+		total, free, err := f.statter.Statfs(path)
+		if err != nil {
+			f.logger.Printf("[WARN] fingerprint.tmpfs: Error calling statfs on %s: %v", path, err)
+			continue
+		}
+
+		prefix := tmpfsAttrPrefix + path
+		node.Attributes[prefix+".size-mb"] = strconv.FormatUint(total/bytesPerMegabyte, 10)
+		node.Attributes[structs.UniqueNamespace(prefix+".free-mb")] = strconv.FormatUint(free/bytesPerMegabyte, 10)
+		applies = true
+	}
+
+	return applies, nil
+}
+
+// tmpfsMounts returns the set of mount points of tmpfs filesystems
+func (f *TmpfsFingerprint) tmpfsMounts() (map[string]struct{}, error) {
+	file, err := os.Open(f.mountsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Lines look something like:
+	//	tmpfs /dev/shm tmpfs rw,nosuid,nodev,size=65536k 0 0
+	mounts := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		if fields[2] == "tmpfs" {
+			mounts[unescapeMountPath(fields[1])] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse mounts: %v", err)
+	}
+	return mounts, nil
+}
+
+// unescapeMountPath decodes the octal escapes used in /proc/mounts for
+// whitespace and backslashes in mount paths.
+func unescapeMountPath(path string) string {
+	if !strings.Contains(path, `\`) {
+		return path
+	}
+
+	var b bytes.Buffer
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) {
+			if c, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(path[i])
+	}
+	return b.String()
+}
+
+// Periodic determines the interval at which the periodic fingerprinter will run.
+func (f *TmpfsFingerprint) Periodic() (bool, time.Duration) {
+	return true, tmpfsInterval
+}
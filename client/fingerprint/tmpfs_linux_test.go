@@ -0,0 +1,110 @@
+package fingerprint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// A fake statter that returns canned filesystem sizes keyed by path
+type FilesystemStatterMock map[string][2]uint64
+
+func (f FilesystemStatterMock) Statfs(path string) (uint64, uint64, error) {
+	sizes, ok := f[path]
+	if !ok {
+		return 0, 0, fmt.Errorf("no such file or directory")
+	}
+	return sizes[0], sizes[1], nil
+}
+
+const tmpfsMountsFixture = `sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0
+proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0
+/dev/sda1 / ext4 rw,relatime,errors=remount-ro 0 0
+tmpfs /dev/shm tmpfs rw,nosuid,nodev,size=65536k 0 0
+tmpfs /run/scratch\040space tmpfs rw,nosuid,nodev,size=1048576k 0 0
+`
+
+func writeTmpfsMountsFixture(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "tmpfs")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	path := filepath.Join(dir, "mounts")
+	if err := ioutil.WriteFile(path, []byte(tmpfsMountsFixture), 0644); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	return path
+}
+
+func TestTmpfsFingerprint(t *testing.T) {
+	mounts := writeTmpfsMountsFixture(t)
+	defer os.RemoveAll(filepath.Dir(mounts))
+
+	f := &TmpfsFingerprint{
+		logger:     testLogger(),
+		mountsFile: mounts,
+		statter: FilesystemStatterMock{
+			"/dev/shm":           {64 * bytesPerMegabyte, 48 * bytesPerMegabyte},
+			"/run/scratch space": {1024 * bytesPerMegabyte, 1000 * bytesPerMegabyte},
+		},
+	}
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"storage.tmpfs./tmp.size-mb":        "10",
+			"unique.storage.tmpfs./tmp.free-mb": "5",
+		},
+	}
+	cfg := &config.Config{
+		Options: map[string]string{
+			tmpfsPathsOption: "/dev/shm, /run/scratch space, /",
+		},
+	}
+
+	ok, err := f.Fingerprint(cfg, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("should apply")
+	}
+
+	assertNodeAttributeEquals(t, node, "storage.tmpfs./dev/shm.size-mb", "64")
+	assertNodeAttributeEquals(t, node, "unique.storage.tmpfs./dev/shm.free-mb", "48")
+	assertNodeAttributeEquals(t, node, "storage.tmpfs./run/scratch space.size-mb", "1024")
+	assertNodeAttributeEquals(t, node, "unique.storage.tmpfs./run/scratch space.free-mb", "1000")
+
+	// Non-tmpfs paths and stale attributes should not be present
+	for _, key := range []string{"storage.tmpfs./.size-mb", "storage.tmpfs./tmp.size-mb", "unique.storage.tmpfs./tmp.free-mb"} {
+		if a, ok := node.Attributes[key]; ok {
+			t.Fatalf("unexpected attribute %s found, %s", key, a)
+		}
+	}
+}
+
+func TestTmpfsFingerprint_Default(t *testing.T) {
+	mounts := writeTmpfsMountsFixture(t)
+	defer os.RemoveAll(filepath.Dir(mounts))
+
+	f := &TmpfsFingerprint{
+		logger:     testLogger(),
+		mountsFile: mounts,
+		statter: FilesystemStatterMock{
+			"/dev/shm": {64 * bytesPerMegabyte, 64 * bytesPerMegabyte},
+		},
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "storage.tmpfs./dev/shm.size-mb", "64")
+	assertNodeAttributeEquals(t, node, "unique.storage.tmpfs./dev/shm.free-mb", "64")
+	if a, ok := node.Attributes["storage.tmpfs./run/scratch space.size-mb"]; ok {
+		t.Fatalf("unexpected attribute found, %s", a)
+	}
+}
@@ -0,0 +1,119 @@
+package fingerprint
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// windowsCurrentVersionKey is the registry key holding the version
+	// details of the running Windows installation.
+	windowsCurrentVersionKey = `HKLM\SOFTWARE\Microsoft\Windows NT\CurrentVersion`
+)
+
+var (
+	// windowsVerRe extracts the version from the output of "ver", which looks
+	// like: Microsoft Windows [Version 10.0.17763.1879]
+	windowsVerRe = regexp.MustCompile(`\[Version ((\d+)\.(\d+)\.(\d+)(\.\d+)?)\]`)
+)
+
+// WindowsFingerprint is used to fingerprint the version, build and edition of
+// Windows clients.
+type WindowsFingerprint struct {
+	StaticFingerprinter
+	logger        *log.Logger
+	versionReader WindowsVersionReader
+}
+
+// An interface to isolate the calls used to query the Windows version.
+// This facilitates testing where we can return canned output.
+type WindowsVersionReader interface {
+	// Ver returns the output of the "ver" command
+	Ver() (string, error)
+
+	// EditionID returns the edition of the Windows installation
+	EditionID() (string, error)
+}
+
+// Implements the version reader by shelling out to "ver" and "reg"
+type DefaultWindowsVersionReader struct {
+}
+
+func (d *DefaultWindowsVersionReader) Ver() (string, error) {
+	out, err := exec.Command("cmd", "/c", "ver").Output()
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func (d *DefaultWindowsVersionReader) EditionID() (string, error) {
+	out, err := exec.Command("reg", "query", windowsCurrentVersionKey, "/v", "EditionID").Output()
+	if err != nil {
+		return "", err
+	}
+
+	// Output looks something like:
+	//	HKEY_LOCAL_MACHINE\SOFTWARE\Microsoft\Windows NT\CurrentVersion
+	//	    EditionID    REG_SZ    ServerStandard
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == "EditionID" {
+			return fields[2], nil
+		}
+	}
+	return "", fmt.Errorf("EditionID not found in registry output")
+}
+
+// NewWindowsFingerprint is used to create a Windows fingerprint
+func NewWindowsFingerprint(logger *log.Logger) Fingerprint {
+	f := &WindowsFingerprint{
+		logger:        logger,
+		versionReader: &DefaultWindowsVersionReader{},
+	}
+	return f
+}
+
+func (f *WindowsFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	if runtime.GOOS != "windows" {
+		return false, nil
+	}
+
+	return f.fingerprintVersion(node), nil
+}
+
+// fingerprintVersion sets the Windows version attributes and returns whether
+// the version could be determined.
+func (f *WindowsFingerprint) fingerprintVersion(node *structs.Node) bool {
+	ver, err := f.versionReader.Ver()
+	if err != nil {
+		f.logger.Printf("[WARN] fingerprint.windows: Error querying Windows version: %v", err)
+		return false
+	}
+
+	m := windowsVerRe.FindStringSubmatch(ver)
+	if m == nil {
+		f.logger.Printf("[WARN] fingerprint.windows: Unable to parse Windows version from %q", strings.TrimSpace(ver))
+		return false
+	}
+
+	node.Attributes["os.windows.version"] = m[1]
+	node.Attributes["os.windows.build"] = m[4]
+
+	// The edition is best effort as it requires access to the registry
+	edition, err := f.versionReader.EditionID()
+	if err != nil {
+		f.logger.Printf("[DEBUG] fingerprint.windows: Error querying Windows edition: %v", err)
+		return true
+	}
+	node.Attributes["os.windows.edition"] = edition
+
+	return true
+}
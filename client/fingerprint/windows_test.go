@@ -0,0 +1,111 @@
+package fingerprint
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// A fake version reader that returns canned output
+type WindowsVersionReaderMock struct {
+	ver        string
+	verErr     error
+	edition    string
+	editionErr error
+}
+
+func (w *WindowsVersionReaderMock) Ver() (string, error) {
+	return w.ver, w.verErr
+}
+
+func (w *WindowsVersionReaderMock) EditionID() (string, error) {
+	return w.edition, w.editionErr
+}
+
+func TestWindowsFingerprint_NonWindows(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test only runs on non-Windows hosts")
+	}
+
+	f := NewWindowsFingerprint(testLogger())
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 0 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
+
+func TestWindowsFingerprint_Version(t *testing.T) {
+	f := &WindowsFingerprint{
+		logger: testLogger(),
+		versionReader: &WindowsVersionReaderMock{
+			ver:     "\r\nMicrosoft Windows [Version 10.0.17763.1879]\r\n",
+			edition: "ServerStandard",
+		},
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	if !f.fingerprintVersion(node) {
+		t.Fatalf("should apply")
+	}
+
+	assertNodeAttributeEquals(t, node, "os.windows.version", "10.0.17763.1879")
+	assertNodeAttributeEquals(t, node, "os.windows.build", "17763")
+	assertNodeAttributeEquals(t, node, "os.windows.edition", "ServerStandard")
+}
+
+func TestWindowsFingerprint_NoEdition(t *testing.T) {
+	f := &WindowsFingerprint{
+		logger: testLogger(),
+		versionReader: &WindowsVersionReaderMock{
+			ver:        "Microsoft Windows [Version 6.3.9600]",
+			editionErr: fmt.Errorf("access denied"),
+		},
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	if !f.fingerprintVersion(node) {
+		t.Fatalf("should apply")
+	}
+
+	assertNodeAttributeEquals(t, node, "os.windows.version", "6.3.9600")
+	assertNodeAttributeEquals(t, node, "os.windows.build", "9600")
+	if a, ok := node.Attributes["os.windows.edition"]; ok {
+		t.Fatalf("unexpected attribute found, %s", a)
+	}
+}
+
+func TestWindowsFingerprint_BadVersion(t *testing.T) {
+	f := &WindowsFingerprint{
+		logger: testLogger(),
+		versionReader: &WindowsVersionReaderMock{
+			ver: "not a version",
+		},
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	if f.fingerprintVersion(node) {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 0 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
@@ -0,0 +1,90 @@
+package fingerprint
+
+import (
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+var (
+	// wgVersionRe matches the version in the output of wg --version, which
+	// looks something like:
+	//	wireguard-tools v1.0.20210914 - https://git.zx2c4.com/wireguard-tools/
+	wgVersionRe = regexp.MustCompile(`\bv(\d+(?:\.\d+)*)\b`)
+)
+
+// WireGuardFingerprint is used to fingerprint whether the kernel supports
+// WireGuard tunnels and the version of the WireGuard tools.
+type WireGuardFingerprint struct {
+	StaticFingerprinter
+	logger *log.Logger
+	wg     WireGuardQuerier
+
+	// sysModuleDir is the sysfs directory of the loaded kernel modules
+	sysModuleDir string
+}
+
+// An interface to isolate calls to wg
+// This facilitates testing where we can return canned output
+type WireGuardQuerier interface {
+	// Version returns the output of wg --version
+	Version() ([]byte, error)
+}
+
+// Implements the querier which calls wg found in the $PATH
+type DefaultWireGuardQuerier struct {
+}
+
+func (d *DefaultWireGuardQuerier) Version() ([]byte, error) {
+	path, err := exec.LookPath("wg")
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command(path, "--version").Output()
+}
+
+// NewWireGuardFingerprint is used to create a WireGuard fingerprint
+func NewWireGuardFingerprint(logger *log.Logger) Fingerprint {
+	f := &WireGuardFingerprint{
+		logger:       logger,
+		wg:           &DefaultWireGuardQuerier{},
+		sysModuleDir: "/sys/module",
+	}
+	return f
+}
+
+func (f *WireGuardFingerprint) Fingerprint(cfg *config.Config, node *structs.Node) (bool, error) {
+	// The module directory exists whether WireGuard is loaded as a module or
+	// built into the kernel.
+	_, err := os.Stat(filepath.Join(f.sysModuleDir, "wireguard"))
+	available := err == nil
+
+	version := ""
+	if out, err := f.wg.Version(); err == nil {
+		if m := wgVersionRe.FindSubmatch(out); m != nil {
+			version = string(m[1])
+		}
+	} else if _, ok := err.(*exec.Error); !ok {
+		f.logger.Printf("[WARN] fingerprint.wireguard: Error calling wg: %v", err)
+	}
+
+	if !available && version == "" {
+		delete(node.Attributes, "network.wireguard.available")
+		delete(node.Attributes, "network.wireguard.version")
+		return false, nil
+	}
+
+	node.Attributes["network.wireguard.available"] = strconv.FormatBool(available)
+	if version != "" {
+		node.Attributes["network.wireguard.version"] = version
+	} else {
+		delete(node.Attributes, "network.wireguard.version")
+	}
+	return true, nil
+}
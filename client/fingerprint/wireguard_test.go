@@ -0,0 +1,79 @@
+package fingerprint
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// A fake wg querier that returns canned output
+type WireGuardQuerierMock struct {
+	out []byte
+	err error
+}
+
+func (w *WireGuardQuerierMock) Version() ([]byte, error) {
+	return w.out, w.err
+}
+
+func TestWireGuardFingerprint(t *testing.T) {
+	dir := writeSysfsTree(t, map[string]string{
+		"wireguard/version": "1.0.0\n",
+	})
+	defer os.RemoveAll(dir)
+
+	f := &WireGuardFingerprint{
+		logger:       testLogger(),
+		wg:           &WireGuardQuerierMock{out: []byte("wireguard-tools v1.0.20210914 - https://git.zx2c4.com/wireguard-tools/\n")},
+		sysModuleDir: dir,
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "network.wireguard.available", "true")
+	assertNodeAttributeEquals(t, node, "network.wireguard.version", "1.0.20210914")
+
+	// The tools are of no use without kernel support
+	f.sysModuleDir = dir + "-missing"
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "network.wireguard.available", "false")
+	assertNodeAttributeEquals(t, node, "network.wireguard.version", "1.0.20210914")
+
+	// The kernel may support WireGuard without the tools installed
+	f.sysModuleDir = dir
+	f.wg = &WireGuardQuerierMock{err: &exec.Error{Name: "wg", Err: exec.ErrNotFound}}
+	assertFingerprintOK(t, f, node)
+	assertNodeAttributeEquals(t, node, "network.wireguard.available", "true")
+	if a, ok := node.Attributes["network.wireguard.version"]; ok {
+		t.Fatalf("unexpected attribute network.wireguard.version found, %s", a)
+	}
+}
+
+func TestWireGuardFingerprint_Absent(t *testing.T) {
+	f := &WireGuardFingerprint{
+		logger:       testLogger(),
+		wg:           &WireGuardQuerierMock{err: &exec.Error{Name: "wg", Err: exec.ErrNotFound}},
+		sysModuleDir: "/nonexistent/sys/module",
+	}
+	node := &structs.Node{
+		Attributes: map[string]string{
+			"network.wireguard.available": "true",
+		},
+	}
+
+	ok, err := f.Fingerprint(&config.Config{}, node)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if ok {
+		t.Fatalf("should not apply")
+	}
+	if len(node.Attributes) != 0 {
+		t.Fatalf("unexpected attributes: %v", node.Attributes)
+	}
+}
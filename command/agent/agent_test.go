@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/nomad/client/fingerprint"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad"
 	sconfig "github.com/hashicorp/nomad/nomad/structs/config"
@@ -362,6 +363,44 @@ func TestAgent_ClientConfig(t *testing.T) {
 	}
 }
 
+// TestAgent_ClientConfig_NomadFingerprint asserts the Nomad fingerprint of a
+// client reports the version the agent was built with.
+func TestAgent_ClientConfig_NomadFingerprint(t *testing.T) {
+	conf := DefaultConfig()
+	conf.Client.Enabled = true
+	conf.Version = "0.6.0"
+	conf.VersionPrerelease = "rc1"
+	conf.Revision = "abcdef"
+	conf.Addresses.HTTP = "169.254.0.1"
+	conf.Addresses.RPC = "169.254.0.1"
+	a := &Agent{config: conf}
+
+	if err := conf.normalizeAddrs(); err != nil {
+		t.Fatalf("error normalizing config: %v", err)
+	}
+	c, err := a.clientConfig()
+	if err != nil {
+		t.Fatalf("got err: %v", err)
+	}
+
+	c.Node.Attributes = make(map[string]string)
+	f := fingerprint.NewNomadFingerprint(log.New(ioutil.Discard, "", 0))
+	if _, err := f.Fingerprint(c, c.Node); err != nil {
+		t.Fatalf("got err: %v", err)
+	}
+
+	expected := map[string]string{
+		"nomad.version":           "0.6.0rc1",
+		"nomad.revision":          "abcdef",
+		"nomad.advertise.address": "169.254.0.1:4646",
+	}
+	for k, v := range expected {
+		if actual := c.Node.Attributes[k]; actual != v {
+			t.Fatalf("Expected %s: %v, got: %v", k, v, actual)
+		}
+	}
+}
+
 // TestAgent_HTTPCheck asserts Agent.agentHTTPCheck properly alters the HTTP
 // API health check depending on configuration.
 func TestAgent_HTTPCheck(t *testing.T) {
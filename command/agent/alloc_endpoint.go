@@ -106,6 +106,26 @@ func (s *HTTPServer) ClientGCRequest(resp http.ResponseWriter, req *http.Request
 	return nil, s.agent.Client().CollectAllAllocs()
 }
 
+func (s *HTTPServer) ClientFingerprintRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	if s.agent.client == nil {
+		return nil, clientNotRunning
+	}
+	return nil, s.agent.Client().RefreshFingerprints()
+}
+
+func (s *HTTPServer) ClientFingerprintDurationsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	if s.agent.client == nil {
+		return nil, clientNotRunning
+	}
+	return s.agent.Client().FingerprintDurations(), nil
+}
+
 func (s *HTTPServer) allocGC(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	return nil, s.agent.Client().CollectAllocation(allocID)
 }
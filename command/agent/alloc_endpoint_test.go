@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/snappy"
 	"github.com/hashicorp/nomad/nomad/mock"
@@ -287,3 +288,49 @@ func TestHTTP_AllocAllGC(t *testing.T) {
 	})
 
 }
+
+func TestHTTP_ClientFingerprint(t *testing.T) {
+	httpTest(t, nil, func(s *TestServer) {
+		// Only writes are allowed
+		req, err := http.NewRequest("GET", "/v1/client/fingerprint", nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW := httptest.NewRecorder()
+		if _, err := s.Server.ClientFingerprintRequest(respW, req); err == nil {
+			t.Fatalf("expected invalid method error")
+		}
+
+		// Make the HTTP request
+		req, err = http.NewRequest("PUT", "/v1/client/fingerprint", nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW = httptest.NewRecorder()
+
+		// Make the request
+		if _, err := s.Server.ClientFingerprintRequest(respW, req); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	})
+}
+
+func TestHTTP_ClientFingerprintDurations(t *testing.T) {
+	httpTest(t, nil, func(s *TestServer) {
+		req, err := http.NewRequest("GET", "/v1/client/fingerprint/durations", nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.ClientFingerprintDurationsRequest(respW, req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		durations := obj.(map[string]time.Duration)
+		if _, ok := durations["arch"]; !ok {
+			t.Fatalf("expected a duration for the arch fingerprinter: %v", durations)
+		}
+	})
+}
@@ -4,13 +4,31 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"text/template"
 
 	"github.com/ugorji/go/codec"
 )
 
+const (
+	// jsonSchemaVersion is the version of the structure of the JSON output
+	// wrapped in a jsonEnvelope. It must be bumped on every breaking change to
+	// the structure so that scripts can guard against them.
+	jsonSchemaVersion = 1
+)
+
 var (
+	// jsonHandlePretty encodes maps with sorted keys so the output of maps
+	// such as node attributes is stable between invocations.
 	jsonHandlePretty = &codec.JsonHandle{
+		BasicHandle: codec.BasicHandle{
+			EncodeOptions: codec.EncodeOptions{
+				Canonical: true,
+			},
+		},
 		HTMLCharsAsIs: true,
 		Indent:        4,
 	}
@@ -98,3 +116,140 @@ func Format(json bool, template string, data interface{}) (string, error) {
 
 	return out, nil
 }
+
+// WriteFormatted writes the formatted output to the file at path, creating
+// its parent directories as needed.
+func WriteFormatted(path, out string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("Error creating output directory: %s", err)
+	}
+	if err := ioutil.WriteFile(path, []byte(out), 0644); err != nil {
+		return fmt.Errorf("Error writing output to %q: %s", path, err)
+	}
+	return nil
+}
+
+// FormatTemplateDir renders every template in the directory against the data.
+// If outDir is empty the rendered templates are concatenated in file name
+// order and returned. Otherwise each rendered template is written to a file of
+// the same name in outDir and a summary of the written files is returned.
+// outDir must not be the template directory as the rendered templates would
+// overwrite them.
+func FormatTemplateDir(dir, outDir string, data interface{}) (string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("Error reading template directory: %s", err)
+	}
+
+	if outDir != "" {
+		same, err := sameDir(dir, outDir)
+		if err != nil {
+			return "", fmt.Errorf("Error resolving output directory: %s", err)
+		}
+		if same {
+			return "", fmt.Errorf("Output directory %q can not be the template directory", outDir)
+		}
+		if err := os.MkdirAll(outDir, 0755); err != nil {
+			return "", fmt.Errorf("Error creating output directory: %s", err)
+		}
+	}
+
+	var outputs []string
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		tmpl, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return "", fmt.Errorf("Error reading template %q: %s", file.Name(), err)
+		}
+
+		out, err := Format(false, string(tmpl), data)
+		if err != nil {
+			return "", fmt.Errorf("Error rendering template %q: %s", file.Name(), err)
+		}
+
+		if outDir == "" {
+			outputs = append(outputs, out)
+			continue
+		}
+
+		dest := filepath.Join(outDir, file.Name())
+		if err := ioutil.WriteFile(dest, []byte(out), 0644); err != nil {
+			return "", fmt.Errorf("Error writing rendered template %q: %s", dest, err)
+		}
+		outputs = append(outputs, fmt.Sprintf("Wrote %s", dest))
+	}
+
+	if len(outputs) == 0 {
+		return "", fmt.Errorf("No templates found in %q", dir)
+	}
+
+	sep := ""
+	if outDir != "" {
+		sep = "\n"
+	}
+	return strings.Join(outputs, sep), nil
+}
+
+// sameDir returns whether the paths resolve to the same directory, following
+// any symlinks. A path that doesn't exist yet is compared as is.
+func sameDir(a, b string) (bool, error) {
+	resolve := func(path string) (string, error) {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return "", err
+		}
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+			return resolved, nil
+		}
+		return abs, nil
+	}
+
+	ra, err := resolve(a)
+	if err != nil {
+		return false, err
+	}
+	rb, err := resolve(b)
+	if err != nil {
+		return false, err
+	}
+	return ra == rb, nil
+}
+
+// formatData formats the data as JSON or using the template option. If a
+// template directory is given instead, each template in it is rendered,
+// optionally writing the results to outDir.
+func formatData(json bool, tmpl, tmplDir, outDir string, data interface{}) (string, error) {
+	if tmplDir == "" {
+		if outDir != "" {
+			return "", fmt.Errorf("-out-dir requires a template directory to be given with -t-dir")
+		}
+		return Format(json, tmpl, data)
+	}
+
+	if json || len(tmpl) > 0 {
+		return "", fmt.Errorf("-t-dir can not be used with -json or -t")
+	}
+	return FormatTemplateDir(tmplDir, outDir, data)
+}
+
+// jsonEnvelope wraps the JSON output of a command with the version of its
+// schema.
+type jsonEnvelope struct {
+	SchemaVersion int
+	Data          interface{}
+}
+
+// versionedData returns the data wrapped in a jsonEnvelope if it is to be
+// output as JSON, unless the raw data is requested.
+func versionedData(json, raw bool, data interface{}) interface{} {
+	if !json || raw {
+		return data
+	}
+	return &jsonEnvelope{
+		SchemaVersion: jsonSchemaVersion,
+		Data:          data,
+	}
+}
@@ -1,6 +1,9 @@
 package command
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -62,3 +65,155 @@ func TestInvalidJSONTemplate(t *testing.T) {
 		t.Fatalf("expected not specified template error, got: %s", err.Error())
 	}
 }
+
+func TestJSONFormat_SortedMaps(t *testing.T) {
+	data := map[string]string{
+		"kernel.name": "linux",
+		"cpu.arch":    "amd64",
+		"os.name":     "ubuntu",
+	}
+
+	expected := `{
+    "cpu.arch": "amd64",
+    "kernel.name": "linux",
+    "os.name": "ubuntu"
+}`
+
+	// Encode several times as map iteration order is random
+	for i := 0; i < 10; i++ {
+		out, err := Format(true, "", data)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if out != expected {
+			t.Fatalf("expected output:\n%s\nactual:\n%s", expected, out)
+		}
+	}
+}
+
+func TestJSONFormat_SchemaVersion(t *testing.T) {
+	data := []testData{tData}
+
+	expected := `{
+    "Data": [
+        {
+            "ID": "1",
+            "Name": "example",
+            "Region": "global"
+        }
+    ],
+    "SchemaVersion": 1
+}`
+	out, err := Format(true, "", versionedData(true, false, data))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out != expected {
+		t.Fatalf("expected output:\n%s\nactual:\n%s", expected, out)
+	}
+
+	// The raw data is output as before
+	out, err = Format(true, "", versionedData(true, true, data))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !strings.HasPrefix(out, "[") || strings.Contains(out, "SchemaVersion") {
+		t.Fatalf("expected raw array output, got:\n%s", out)
+	}
+
+	// Templates are rendered against the raw data
+	out, err = Format(false, "{{range .}}{{.Region}}{{end}}", versionedData(false, false, data))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out != "global" {
+		t.Fatalf("expected template output %q, got: %q", "global", out)
+	}
+}
+
+func TestFormatTemplateDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nomad-templates")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	templates := map[string]string{
+		"a.tmpl": "region={{.Region}}\n",
+		"b.tmpl": "name={{.Name}}\n",
+	}
+	for name, tmpl := range templates {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(tmpl), 0644); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+
+	// Concatenate the rendered templates
+	out, err := formatData(false, "", dir, "", tData)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if expected := "region=global\nname=example\n"; out != expected {
+		t.Fatalf("expected output:\n%s\nactual:\n%s", expected, out)
+	}
+
+	// Write the rendered templates to the output directory
+	outDir := filepath.Join(dir, "out")
+	if _, err := formatData(false, "", dir, outDir, tData); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	expected := map[string]string{
+		"a.tmpl": "region=global\n",
+		"b.tmpl": "name=example\n",
+	}
+	for name, content := range expected {
+		b, err := ioutil.ReadFile(filepath.Join(outDir, name))
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if string(b) != content {
+			t.Fatalf("expected %s to contain %q; got %q", name, content, string(b))
+		}
+	}
+
+	// The rendered templates can not overwrite the templates, including
+	// through a symlink to the template directory
+	link := dir + "-link"
+	if err := os.Symlink(dir, link); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.Remove(link)
+	for _, out := range []string{dir, dir + "/", link} {
+		if _, err := formatData(false, "", dir, out, tData); err == nil || !strings.Contains(err.Error(), "can not be the template directory") {
+			t.Fatalf("expected error writing to the template directory %q, got: %v", out, err)
+		}
+	}
+	for name, tmpl := range templates {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if string(b) != tmpl {
+			t.Fatalf("expected template %s to be unchanged, got %q", name, string(b))
+		}
+	}
+
+	// An output directory requires a template directory
+	if _, err := formatData(false, "{{.Name}}", "", outDir, tData); err == nil {
+		t.Fatalf("expected error using -out-dir without a template directory")
+	}
+
+	// A template directory can not be combined with an inline template
+	if _, err := formatData(false, "{{.Name}}", dir, "", tData); err == nil {
+		t.Fatalf("expected error using -t with -t-dir")
+	}
+
+	// Inline templates starting with "@" are not treated as a directory
+	out, err = formatData(false, "@{{.Name}}", "", "", tData)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out != "@example" {
+		t.Fatalf("expected inline template output, got: %q", out)
+	}
+}
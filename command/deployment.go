@@ -1,6 +1,11 @@
 package command
 
-import "github.com/mitchellh/cli"
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
 
 type DeploymentCommand struct {
 	Meta
@@ -17,3 +22,63 @@ func (f *DeploymentCommand) Synopsis() string {
 func (f *DeploymentCommand) Run(args []string) int {
 	return cli.RunResultHelp
 }
+
+// monitorDeploymentEval handles the evaluation created by a deployment update.
+// When detached, the evaluation ID is printed and the command returns
+// immediately; otherwise the evaluation is monitored.
+func monitorDeploymentEval(ui cli.Ui, evalID string, detach bool, monitor func(evalID string) int) int {
+	// Nothing to do
+	if evalID == "" {
+		return 0
+	}
+
+	if detach {
+		ui.Output("Evaluation ID: " + evalID)
+		return 0
+	}
+
+	ui.Output("")
+	return monitor(evalID)
+}
+
+// Actions recorded in the output of deployment updates
+const (
+	deploymentActionPromote = "promote"
+	deploymentActionFail    = "fail"
+)
+
+// deploymentUpdateOutput is the machine-readable confirmation of a deployment
+// update, along with the evaluation it created if any.
+type deploymentUpdateOutput struct {
+	DeploymentID string
+	EvalID       string
+	Action       string
+	Evaluation   *api.Evaluation
+}
+
+// outputDeploymentUpdate outputs the confirmation of a deployment update as
+// JSON or using the template rather than monitoring the evaluation it created.
+// The evaluation is looked up using the info function.
+func outputDeploymentUpdate(ui cli.Ui, deployID, action, evalID string, json bool, tmpl string, info func(evalID string) (*api.Evaluation, error)) int {
+	update := &deploymentUpdateOutput{
+		DeploymentID: deployID,
+		EvalID:       evalID,
+		Action:       action,
+	}
+	if evalID != "" {
+		eval, err := info(evalID)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Error retrieving evaluation: %s", err))
+			return 1
+		}
+		update.Evaluation = eval
+	}
+
+	out, err := Format(json, tmpl, versionedData(json, false, update))
+	if err != nil {
+		ui.Error(err.Error())
+		return 1
+	}
+	ui.Output(out)
+	return 0
+}
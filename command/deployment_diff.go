@@ -0,0 +1,184 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+type DeploymentDiffCommand struct {
+	Meta
+}
+
+func (c *DeploymentDiffCommand) Help() string {
+	helpText := `
+Usage: nomad deployment diff [options] <deployment id> <version>
+
+Diff is used to compare the version of the job rolled out by a deployment with
+another version of the job, such as the version a revert would return to. The
+diff shows the changes from the deployed version to the given version.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Diff Options:
+
+  -json
+    Output the diff in its JSON format. The diff is wrapped in an object whose
+    "SchemaVersion" field is incremented on every breaking change to the output
+    and whose "Data" field holds the diff.
+
+  -json-raw
+    Output the JSON without the envelope recording its schema version. Must be
+    used with -json.
+
+  -t
+    Format and display the diff using a Go template.
+
+  -verbose
+    Display full information, expanding added and removed task groups and
+    tasks.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *DeploymentDiffCommand) Synopsis() string {
+	return "Compare the job of a deployment with another job version"
+}
+
+func (c *DeploymentDiffCommand) Run(args []string) int {
+	var json, rawJSON, verbose bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet("deployment diff", FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.BoolVar(&rawJSON, "json-raw", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly two arguments
+	args = flags.Args()
+	if l := len(args); l != 2 {
+		c.Ui.Error(c.Help())
+		return 1
+	}
+
+	if rawJSON && !json {
+		c.Ui.Error("The -json-raw flag can only be used with -json")
+		return 1
+	}
+
+	dID := args[0]
+	version, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing version value %q: %v", args[1], err))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Do a prefix lookup
+	deploy, possible, err := getDeployment(client.Deployments(), dID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving deployment: %s", err))
+		return 1
+	}
+
+	if len(possible) != 0 {
+		c.Ui.Output(fmt.Sprintf("Prefix matched multiple deployments\n\n%s", formatDeployments(possible, length)))
+		return 0
+	}
+
+	versions, _, _, err := client.Jobs().Versions(deploy.JobID, false, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving job versions: %s", err))
+		return 1
+	}
+
+	diff, err := diffJobVersions(versions, deploy.JobVersion, version)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, versionedData(json, rawJSON, diff))
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[bold]Changes from version %d of deployment %q to version %d[reset]\n",
+		deploy.JobVersion, limit(deploy.ID, length), version)))
+	if diff.Type == string(structs.DiffTypeNone) {
+		c.Ui.Output("No differences")
+		return 0
+	}
+	c.Ui.Output(c.Colorize().Color(strings.TrimSpace(formatJobDiff(diff, verbose))))
+	return 0
+}
+
+// diffJobVersions returns the diff of the job from the from version to the to
+// version. An error is returned if either version is not one of the versions
+// of the job.
+func diffJobVersions(versions []*api.Job, from, to uint64) (*api.JobDiff, error) {
+	var fromJob, toJob *api.Job
+	for _, job := range versions {
+		if job.Version == nil {
+			continue
+		}
+		if *job.Version == from {
+			fromJob = job
+		}
+		if *job.Version == to {
+			toJob = job
+		}
+	}
+	if fromJob == nil {
+		return nil, fmt.Errorf("Job version %d not found", from)
+	}
+	if toJob == nil {
+		return nil, fmt.Errorf("Job version %d not found", to)
+	}
+
+	diff, err := agent.ApiJobToStructJob(fromJob).Diff(agent.ApiJobToStructJob(toJob), true)
+	if err != nil {
+		return nil, fmt.Errorf("Error diffing job versions: %v", err)
+	}
+
+	// The diff is converted the same way it is when returned by the API
+	buf, err := json.Marshal(diff)
+	if err != nil {
+		return nil, err
+	}
+	var out api.JobDiff
+	if err := json.Unmarshal(buf, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,84 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/mitchellh/cli"
+)
+
+func TestDeploymentDiffCommand_Implements(t *testing.T) {
+	var _ cli.Command = &DeploymentDiffCommand{}
+}
+
+func TestDeploymentDiffCommand_Fails(t *testing.T) {
+	ui := new(cli.MockUi)
+	cmd := &DeploymentDiffCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, cmd.Help()) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-json-raw", "12", "1"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-json-raw") {
+		t.Fatalf("expected -json-raw error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"12", "latest"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error parsing version") {
+		t.Fatalf("expected version parse error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=nope", "12", "1"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error retrieving deployment") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+}
+
+func TestDeploymentDiffCommand_Diff(t *testing.T) {
+	v0 := testJob("web")
+	v0.Canonicalize()
+	v0.Version = helper.Uint64ToPtr(0)
+
+	v1 := testJob("web")
+	v1.TaskGroups[0].Count = helper.IntToPtr(3)
+	v1.Canonicalize()
+	v1.Version = helper.Uint64ToPtr(1)
+
+	// Reverting from the deployed version 1 to 0 reduces the count
+	diff, err := diffJobVersions([]*api.Job{v1, v0}, 1, 0)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if diff.Type != "Edited" || len(diff.TaskGroups) != 1 {
+		t.Fatalf("unexpected diff: %#v", diff)
+	}
+	out := formatJobDiff(diff, false)
+	if !strings.Contains(out, `Task Group: "group1"`) || !strings.Contains(out, `Count: "3" => "1"`) {
+		t.Fatalf("unexpected diff output:\n%s", out)
+	}
+
+	// Identical versions have no differences
+	if diff, err := diffJobVersions([]*api.Job{v1, v0}, 0, 0); err != nil || diff.Type != "None" {
+		t.Fatalf("expected no differences, got %v %v", diff, err)
+	}
+
+	if _, err := diffJobVersions([]*api.Job{v1, v0}, 1, 5); err == nil || !strings.Contains(err.Error(), "Job version 5 not found") {
+		t.Fatalf("expected missing version error, got %v", err)
+	}
+}
@@ -3,6 +3,8 @@ package command
 import (
 	"fmt"
 	"strings"
+
+	"github.com/hashicorp/nomad/api"
 )
 
 type DeploymentFailCommand struct {
@@ -18,6 +20,9 @@ stop the placement of new allocations as part of rolling deployment and
 if the job is configured to auto revert, the job will attempt to roll back to a
 stable version.
 
+Failing a deployment creates an evaluation of its job in the same step, which
+is monitored unless -detach is given.
+
 General Options:
 
   ` + generalOptionsUsage() + `
@@ -29,6 +34,18 @@ Fail Options:
 	resume, the evaluation ID will be printed to the screen, which can be used
 	to examine the evaluation using the eval-status command.
 
+  -json
+    Output a confirmation of the deployment update in a JSON format instead of
+    monitoring its evaluation. The confirmation holds the "DeploymentID", the
+    "EvalID" and "Evaluation" created, if any, and the "Action" taken, "fail".
+    It is wrapped in an object whose "SchemaVersion" field is incremented on
+    every breaking change to the output and whose "Data" field holds the
+    confirmation.
+
+  -t
+    Format and display the confirmation of the deployment update using a Go
+    template instead of monitoring its evaluation.
+
   -verbose
     Display full information.
 `
@@ -40,11 +57,14 @@ func (c *DeploymentFailCommand) Synopsis() string {
 }
 
 func (c *DeploymentFailCommand) Run(args []string) int {
-	var detach, verbose bool
+	var detach, verbose, json bool
+	var tmpl string
 
-	flags := c.Meta.FlagSet("deployment resume", FlagSetClient)
+	flags := c.Meta.FlagSet("deployment fail", FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&detach, "detach", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 
 	if err := flags.Parse(args); err != nil {
@@ -91,20 +111,21 @@ func (c *DeploymentFailCommand) Run(args []string) int {
 		return 1
 	}
 
+	if json || len(tmpl) > 0 {
+		return outputDeploymentUpdate(c.Ui, deploy.ID, deploymentActionFail, u.EvalID, json, tmpl, func(evalID string) (*api.Evaluation, error) {
+			eval, _, err := client.Evaluations().Info(evalID, nil)
+			return eval, err
+		})
+	}
+
 	if u.RevertedJobVersion == nil {
 		c.Ui.Output(fmt.Sprintf("Deployment %q failed", deploy.ID))
 	} else {
 		c.Ui.Output(fmt.Sprintf("Deployment %q failed. Auto-reverted to job version %d.", deploy.ID, *u.RevertedJobVersion))
 	}
 
-	evalCreated := u.EvalID != ""
-
-	// Nothing to do
-	if detach || !evalCreated {
-		return 0
-	}
-
-	c.Ui.Output("")
-	mon := newMonitor(c.Ui, client, length)
-	return mon.monitor(u.EvalID, false)
+	return monitorDeploymentEval(c.Ui, u.EvalID, detach, func(evalID string) int {
+		mon := newMonitor(c.Ui, client, length)
+		return mon.monitor(evalID, false)
+	})
 }
@@ -1,9 +1,11 @@
 package command
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/mitchellh/cli"
 )
 
@@ -32,3 +34,44 @@ func TestDeploymentFailCommand_Fails(t *testing.T) {
 	}
 	ui.ErrorWriter.Reset()
 }
+
+func TestDeploymentFailCommand_UpdateJSON(t *testing.T) {
+	deployID := "11111111-2222-3333-4444-555555555555"
+	info := func(id string) (*api.Evaluation, error) {
+		return &api.Evaluation{ID: id, Status: "pending"}, nil
+	}
+
+	ui := new(cli.MockUi)
+	if code := outputDeploymentUpdate(ui, deployID, deploymentActionFail, "fail-eval", true, "", info); code != 0 {
+		t.Fatalf("expected exit code 0, got: %d: %s", code, ui.ErrorWriter.String())
+	}
+
+	var out map[string]interface{}
+	if err := json.Unmarshal([]byte(ui.OutputWriter.String()), &out); err != nil {
+		t.Fatalf("invalid JSON output: %v: %s", err, ui.OutputWriter.String())
+	}
+	data, ok := out["Data"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a Data object: %s", ui.OutputWriter.String())
+	}
+	if data["DeploymentID"] != deployID || data["EvalID"] != "fail-eval" || data["Action"] != "fail" {
+		t.Fatalf("unexpected confirmation: %v", data)
+	}
+	if eval, ok := data["Evaluation"].(map[string]interface{}); !ok || eval["ID"] != "fail-eval" {
+		t.Fatalf("expected the evaluation in the confirmation: %v", data)
+	}
+
+	// Failing a deployment may not create an evaluation
+	ui = new(cli.MockUi)
+	if code := outputDeploymentUpdate(ui, deployID, deploymentActionFail, "", true, "", info); code != 0 {
+		t.Fatalf("expected exit code 0, got: %d: %s", code, ui.ErrorWriter.String())
+	}
+	out = nil
+	if err := json.Unmarshal([]byte(ui.OutputWriter.String()), &out); err != nil {
+		t.Fatalf("invalid JSON output: %v: %s", err, ui.OutputWriter.String())
+	}
+	data = out["Data"].(map[string]interface{})
+	if data["EvalID"] != "" || data["Evaluation"] != nil {
+		t.Fatalf("expected no evaluation in the confirmation: %v", data)
+	}
+}
@@ -0,0 +1,155 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// defaultDeploymentGCThreshold is the deployment GC threshold of servers
+	// that don't configure one.
+	defaultDeploymentGCThreshold = time.Hour
+)
+
+type DeploymentGCStatusCommand struct {
+	Meta
+}
+
+func (c *DeploymentGCStatusCommand) Help() string {
+	helpText := `
+Usage: nomad deployment gc-status [options]
+
+GC-status lists the deployments and whether each is eligible for garbage
+collection. Deployments are eligible once they reach a terminal status and
+are garbage collected after they have not been modified for the deployment GC
+threshold of the servers, which is read from the queried agent. Active
+deployments are never garbage collected.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+GC-status Options:
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *DeploymentGCStatusCommand) Synopsis() string {
+	return "List deployments and whether they are eligible for GC"
+}
+
+func (c *DeploymentGCStatusCommand) Run(args []string) int {
+	var verbose bool
+
+	flags := c.Meta.FlagSet("deployment gc-status", FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error(c.Help())
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	deploys, _, err := client.Deployments().List(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving deployments: %s", err))
+		return 1
+	}
+
+	self, err := client.Agent().Self()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying agent info: %s", err))
+		return 1
+	}
+	threshold, ok, err := deploymentGCThreshold(self)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if ok {
+		c.Ui.Output(fmt.Sprintf("Deployment GC threshold: %s\n", threshold))
+	} else {
+		c.Ui.Output(fmt.Sprintf("Deployment GC threshold: %s (default, the agent is not a server)\n", threshold))
+	}
+	c.Ui.Output(formatDeploymentGCStatus(deploys, length))
+	return 0
+}
+
+// deploymentGCThreshold returns the deployment GC threshold from the
+// configuration of the agent. If the agent is not a server, the default
+// threshold is returned and ok is false.
+func deploymentGCThreshold(self *api.AgentSelf) (threshold time.Duration, ok bool, err error) {
+	server, _ := self.Config["Server"].(map[string]interface{})
+	if enabled, _ := server["Enabled"].(bool); !enabled {
+		return defaultDeploymentGCThreshold, false, nil
+	}
+
+	value, _ := server["DeploymentGCThreshold"].(string)
+	if value == "" {
+		return defaultDeploymentGCThreshold, true, nil
+	}
+
+	threshold, err = time.ParseDuration(value)
+	if err != nil {
+		return 0, false, fmt.Errorf("Error parsing deployment GC threshold %q: %s", value, err)
+	}
+	return threshold, true, nil
+}
+
+// formatDeploymentGCStatus formats the deployments with whether each is
+// eligible for garbage collection.
+func formatDeploymentGCStatus(deploys []*api.Deployment, uuidLength int) string {
+	if len(deploys) == 0 {
+		return "No deployments found"
+	}
+
+	rows := make([]string, len(deploys)+1)
+	rows[0] = "ID|Job ID|Job Version|Status|GC Eligible"
+	for i, d := range deploys {
+		rows[i+1] = fmt.Sprintf("%s|%s|%d|%s|%s",
+			limit(d.ID, uuidLength),
+			d.JobID,
+			d.JobVersion,
+			d.Status,
+			deploymentGCEligibility(d))
+	}
+	return formatList(rows)
+}
+
+// deploymentGCEligibility describes whether the deployment is eligible for
+// garbage collection, which requires it to be in a terminal status.
+func deploymentGCEligibility(d *api.Deployment) string {
+	switch d.Status {
+	case structs.DeploymentStatusRunning, structs.DeploymentStatusPaused:
+		return "no (active)"
+	default:
+		return "yes (terminal)"
+	}
+}
@@ -0,0 +1,102 @@
+package command
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+func TestDeploymentGCStatusCommand_Implements(t *testing.T) {
+	var _ cli.Command = &DeploymentGCStatusCommand{}
+}
+
+func TestDeploymentGCStatusCommand_Fails(t *testing.T) {
+	ui := new(cli.MockUi)
+	cmd := &DeploymentGCStatusCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, cmd.Help()) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=nope"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error retrieving deployments") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+}
+
+func TestDeploymentGCStatusCommand_Eligibility(t *testing.T) {
+	deploys := []*api.Deployment{
+		{ID: "11111111-2222-3333-4444-555555555555", JobID: "web", Status: "running"},
+		{ID: "22222222-2222-3333-4444-555555555555", JobID: "web", Status: "paused"},
+		{ID: "33333333-2222-3333-4444-555555555555", JobID: "web", Status: "successful"},
+		{ID: "44444444-2222-3333-4444-555555555555", JobID: "api", Status: "failed"},
+		{ID: "55555555-2222-3333-4444-555555555555", JobID: "api", Status: "cancelled"},
+	}
+
+	out := formatDeploymentGCStatus(deploys, shortId)
+	lines := strings.Split(out, "\n")
+	if len(lines) != 6 || !strings.Contains(lines[0], "GC Eligible") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	for i, line := range lines[1:] {
+		expected := "yes (terminal)"
+		if i < 2 {
+			expected = "no (active)"
+		}
+		if !strings.HasSuffix(line, expected) {
+			t.Fatalf("expected %q for deployment %s, got: %s", expected, deploys[i].ID, line)
+		}
+	}
+
+	if out := formatDeploymentGCStatus(nil, shortId); out != "No deployments found" {
+		t.Fatalf("unexpected output: %s", out)
+	}
+}
+
+func TestDeploymentGCStatusCommand_Threshold(t *testing.T) {
+	cases := []struct {
+		config    map[string]interface{}
+		threshold time.Duration
+		ok        bool
+		err       bool
+	}{
+		{
+			config:    map[string]interface{}{"Server": map[string]interface{}{"Enabled": true, "DeploymentGCThreshold": "12h"}},
+			threshold: 12 * time.Hour,
+			ok:        true,
+		},
+		{
+			config:    map[string]interface{}{"Server": map[string]interface{}{"Enabled": true, "DeploymentGCThreshold": ""}},
+			threshold: time.Hour,
+			ok:        true,
+		},
+		{
+			config:    map[string]interface{}{"Server": map[string]interface{}{"Enabled": false}},
+			threshold: time.Hour,
+		},
+		{
+			config: map[string]interface{}{"Server": map[string]interface{}{"Enabled": true, "DeploymentGCThreshold": "soon"}},
+			err:    true,
+		},
+	}
+
+	for i, c := range cases {
+		threshold, ok, err := deploymentGCThreshold(&api.AgentSelf{Config: c.config})
+		if (err != nil) != c.err {
+			t.Fatalf("case %d: unexpected error: %v", i, err)
+		}
+		if threshold != c.threshold || ok != c.ok {
+			t.Fatalf("case %d: expected %s/%v, got %s/%v", i, c.threshold, c.ok, threshold, ok)
+		}
+	}
+}
@@ -2,21 +2,38 @@ package command
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/nomad/api"
 )
 
+const (
+	// allRegions is the value of the -region flag that lists deployments
+	// across every region.
+	allRegions = "all"
+)
+
 type DeploymentListCommand struct {
 	Meta
 }
 
+// regionDeployment is a deployment and the region it was listed from
+type regionDeployment struct {
+	Region     string
+	Deployment *api.Deployment
+}
+
 func (c *DeploymentListCommand) Help() string {
 	helpText := `
 Usage: nomad deployment list [options]
 
 List is used to list the set of deployments tracked by Nomad.
 
+If the -region flag is set to "all", the deployments of every region are
+listed along with their region. Regions that can not be queried are reported
+as warnings. This can not be combined with -json or -t.
+
 General Options:
 
   ` + generalOptionsUsage() + `
@@ -24,11 +41,45 @@ General Options:
 List Options:
 
   -json
-    Output the deployments in a JSON format.
+    Output the deployments in a JSON format. The deployments are wrapped in an
+    object whose "SchemaVersion" field is incremented on every breaking change
+    to the output and whose "Data" field holds the deployments.
+
+  -json-raw
+    Output the JSON without the envelope recording its schema version, as it
+    was output before the envelope was introduced. Must be used with -json.
 
   -t
     Format and display the deployments using a Go template.
 
+  -t-dir
+    Format and display the deployments using each Go template in the given
+    directory. A directory is given with this flag rather than as "-t @<dir>"
+    so that inline templates starting with "@" are not mistaken for one.
+
+  -out-dir
+    Write each template rendered from a template directory to a file of the
+    same name in the given directory instead of displaying them.
+
+  -stale
+    Allow the deployments to be read from any server rather than only the
+    leader, reducing the load on the leader at the risk of reading deployments
+    that are slightly out of date.
+
+  -page-size
+    Display at most the given number of deployments. If more deployments
+    remain, the token to pass to -page-token to display the next page is
+    output after them. When used with -quiet, -json or -t the token is output
+    to stderr. Can not be used with -region=all.
+
+  -page-token
+    Display the page of deployments starting at the given token, as output by
+    a previous use of -page-size.
+
+  -quiet
+    Display only the full IDs of the deployments, one per line. Can not be
+    used with -json or -t.
+
   -verbose
     Display full information.
 `
@@ -40,14 +91,22 @@ func (c *DeploymentListCommand) Synopsis() string {
 }
 
 func (c *DeploymentListCommand) Run(args []string) int {
-	var json, verbose bool
-	var tmpl string
+	var json, rawJSON, quiet, verbose, stale bool
+	var tmpl, tmplDir, outDir, pageToken string
+	var pageSize int
 
 	flags := c.Meta.FlagSet("deployment list", FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&stale, "stale", false, "")
+	flags.BoolVar(&quiet, "quiet", false, "")
 	flags.BoolVar(&json, "json", false, "")
+	flags.BoolVar(&rawJSON, "json-raw", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
+	flags.StringVar(&tmplDir, "t-dir", "", "")
+	flags.StringVar(&outDir, "out-dir", "", "")
+	flags.IntVar(&pageSize, "page-size", 0, "")
+	flags.StringVar(&pageToken, "page-token", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -60,6 +119,21 @@ func (c *DeploymentListCommand) Run(args []string) int {
 		return 1
 	}
 
+	if rawJSON && !json {
+		c.Ui.Error("The -json-raw flag can only be used with -json")
+		return 1
+	}
+
+	if quiet && (json || len(tmpl) > 0 || tmplDir != "" || outDir != "") {
+		c.Ui.Error("The -quiet flag can not be used with -json or -t")
+		return 1
+	}
+
+	if pageSize < 0 {
+		c.Ui.Error("The -page-size flag must not be negative")
+		return 1
+	}
+
 	// Truncate the id unless full length is requested
 	length := shortId
 	if verbose {
@@ -73,27 +147,154 @@ func (c *DeploymentListCommand) Run(args []string) int {
 		return 1
 	}
 
-	deploys, _, err := client.Deployments().List(nil)
+	if c.Meta.region == allRegions {
+		if json || len(tmpl) > 0 || tmplDir != "" || outDir != "" {
+			c.Ui.Error("The -json and -t flags can not be used with -region=all")
+			return 1
+		}
+		if pageSize != 0 || pageToken != "" {
+			c.Ui.Error("The -page-size and -page-token flags can not be used with -region=all")
+			return 1
+		}
+
+		// Query the regions from the local agent rather than the "all" region
+		client.SetRegion("")
+		regions, err := client.Regions().List()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error retrieving regions: %s", err))
+			return 1
+		}
+
+		list := func(region string) ([]*api.Deployment, error) {
+			deploys, _, err := client.Deployments().List(&api.QueryOptions{Region: region, AllowStale: stale})
+			return deploys, err
+		}
+		return c.listRegions(regions, list, quiet, length)
+	}
+
+	q := &api.QueryOptions{
+		AllowStale: stale,
+		PerPage:    int32(pageSize),
+		NextToken:  pageToken,
+	}
+	deploys, qm, err := client.Deployments().List(q)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error retrieving deployments: %s", err))
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, deploys)
+	if json || len(tmpl) > 0 || tmplDir != "" || outDir != "" {
+		out, err := formatData(json, tmpl, tmplDir, outDir, versionedData(json, rawJSON, deploys))
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
 		}
 
 		c.Ui.Output(out)
+		c.outputNextToken(qm.NextToken, true)
+		return 0
+	}
+
+	if quiet {
+		if len(deploys) != 0 {
+			c.Ui.Output(formatDeploymentIDs(deploys))
+		}
+		c.outputNextToken(qm.NextToken, true)
 		return 0
 	}
 
 	c.Ui.Output(formatDeployments(deploys, length))
+	c.outputNextToken(qm.NextToken, false)
+	return 0
+}
+
+// outputNextToken outputs the token of the next page of deployments, if any.
+// If the output is meant to be parsed the token is output to stderr so that
+// it does not corrupt the output.
+func (c *DeploymentListCommand) outputNextToken(token string, parsed bool) {
+	if token == "" {
+		return
+	}
+	msg := fmt.Sprintf("Next page token: %s", token)
+	if parsed {
+		c.Ui.Warn(msg)
+		return
+	}
+	c.Ui.Output("\n" + msg)
+}
+
+// listRegions lists the deployments of each region and outputs the merged
+// results. Regions that fail to be listed are reported as warnings and the
+// command only fails if no region could be listed.
+func (c *DeploymentListCommand) listRegions(regions []string, list func(region string) ([]*api.Deployment, error), quiet bool, length int) int {
+	deploys, failures := listDeploymentsByRegion(regions, list)
+
+	failed := make([]string, 0, len(failures))
+	for region := range failures {
+		failed = append(failed, region)
+	}
+	sort.Strings(failed)
+	for _, region := range failed {
+		c.Ui.Warn(fmt.Sprintf("Error retrieving deployments in region %q: %s", region, failures[region]))
+	}
+	if len(regions) != 0 && len(failures) == len(regions) {
+		c.Ui.Error("Error retrieving deployments: no region could be queried")
+		return 1
+	}
+
+	if quiet {
+		ids := make([]string, len(deploys))
+		for i, d := range deploys {
+			ids[i] = d.Deployment.ID
+		}
+		if len(ids) != 0 {
+			c.Ui.Output(strings.Join(ids, "\n"))
+		}
+		return 0
+	}
+
+	c.Ui.Output(formatRegionDeployments(deploys, length))
 	return 0
 }
 
+// listDeploymentsByRegion lists the deployments of each region, returning the
+// merged deployments and the error of each region that could not be listed.
+func listDeploymentsByRegion(regions []string, list func(region string) ([]*api.Deployment, error)) ([]*regionDeployment, map[string]error) {
+	var deploys []*regionDeployment
+	failures := make(map[string]error)
+	for _, region := range regions {
+		regionDeploys, err := list(region)
+		if err != nil {
+			failures[region] = err
+			continue
+		}
+		for _, d := range regionDeploys {
+			deploys = append(deploys, &regionDeployment{Region: region, Deployment: d})
+		}
+	}
+	return deploys, failures
+}
+
+func formatRegionDeployments(deploys []*regionDeployment, uuidLength int) string {
+	if len(deploys) == 0 {
+		return "No deployments found"
+	}
+
+	rows := make([]string, len(deploys)+1)
+	rows[0] = "ID|Region|Job ID|Job Version|Status|Description"
+	for i, rd := range deploys {
+		d := rd.Deployment
+		rows[i+1] = fmt.Sprintf("%s|%s|%s|%d|%s|%s",
+			limit(d.ID, uuidLength),
+			rd.Region,
+			d.JobID,
+			d.JobVersion,
+			d.Status,
+			d.StatusDescription)
+	}
+	return formatList(rows)
+}
+
 func formatDeployments(deploys []*api.Deployment, uuidLength int) string {
 	if len(deploys) == 0 {
 		return "No deployments found"
@@ -111,3 +312,12 @@ func formatDeployments(deploys []*api.Deployment, uuidLength int) string {
 	}
 	return formatList(rows)
 }
+
+// formatDeploymentIDs returns the full IDs of the deployments, one per line
+func formatDeploymentIDs(deploys []*api.Deployment) string {
+	ids := make([]string, len(deploys))
+	for i, d := range deploys {
+		ids[i] = d.ID
+	}
+	return strings.Join(ids, "\n")
+}
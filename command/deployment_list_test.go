@@ -1,9 +1,15 @@
 package command
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"strings"
+	"sync"
 	"testing"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/mitchellh/cli"
 )
 
@@ -31,4 +37,163 @@ func TestDeploymentListCommand_Fails(t *testing.T) {
 		t.Fatalf("expected failed query error, got: %s", out)
 	}
 	ui.ErrorWriter.Reset()
+
+	// Fails when -quiet is combined with formatted output
+	for _, flag := range []string{"-json", "-t={{.}}"} {
+		if code := cmd.Run([]string{"-address=nope", "-quiet", flag}); code != 1 {
+			t.Fatalf("expected exit code 1, got: %d", code)
+		}
+		if out := ui.ErrorWriter.String(); !strings.Contains(out, "-quiet") {
+			t.Fatalf("expected -quiet error, got: %s", out)
+		}
+		ui.ErrorWriter.Reset()
+	}
+
+	// Fails on a negative page size
+	if code := cmd.Run([]string{"-address=nope", "-page-size=-1"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-page-size") {
+		t.Fatalf("expected -page-size error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+}
+
+func TestDeploymentListCommand_FormatIDs(t *testing.T) {
+	deploys := []*api.Deployment{
+		{ID: "11111111-2222-3333-4444-555555555555", JobID: "foo"},
+		{ID: "66666666-7777-8888-9999-000000000000", JobID: "bar"},
+	}
+
+	expected := "11111111-2222-3333-4444-555555555555\n66666666-7777-8888-9999-000000000000"
+	if out := formatDeploymentIDs(deploys); out != expected {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+}
+
+func TestDeploymentListCommand_AllRegions(t *testing.T) {
+	ui := new(cli.MockUi)
+	cmd := &DeploymentListCommand{Meta: Meta{Ui: ui}}
+
+	deploys := map[string][]*api.Deployment{
+		"east": {{ID: "11111111-2222-3333-4444-555555555555", JobID: "web", Status: "running"}},
+		"west": {{ID: "66666666-7777-8888-9999-000000000000", JobID: "api", Status: "successful"}},
+	}
+	list := func(region string) ([]*api.Deployment, error) {
+		d, ok := deploys[region]
+		if !ok {
+			return nil, fmt.Errorf("no path to region")
+		}
+		return d, nil
+	}
+
+	if code := cmd.listRegions([]string{"east", "west"}, list, false, shortId); code != 0 {
+		t.Fatalf("expected exit code 0, got: %d", code)
+	}
+	out := ui.OutputWriter.String()
+	for _, expected := range []string{"Region", "11111111", "east", "66666666", "west"} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("expected %q in output:\n%s", expected, out)
+		}
+	}
+	ui.OutputWriter.Reset()
+
+	// A failed region is reported while the others are still listed
+	if code := cmd.listRegions([]string{"east", "north"}, list, false, shortId); code != 0 {
+		t.Fatalf("expected exit code 0, got: %d", code)
+	}
+	if out := ui.OutputWriter.String(); !strings.Contains(out, "east") || strings.Contains(out, "66666666") {
+		t.Fatalf("expected only east deployments, got:\n%s", out)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, `region "north"`) {
+		t.Fatalf("expected warning for north region, got: %s", out)
+	}
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Fails when no region can be listed
+	if code := cmd.listRegions([]string{"north"}, list, false, shortId); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+}
+
+func TestDeploymentListCommand_Stale(t *testing.T) {
+	srv, queried := testStaleServer(t, map[string]string{
+		"/v1/deployments": `[{"ID": "11111111-2222-3333-4444-555555555555", "JobID": "web", "Status": "running", "TaskGroups": {}}]`,
+	})
+	defer srv.Close()
+
+	for _, stale := range []bool{false, true} {
+		ui := new(cli.MockUi)
+		cmd := &DeploymentListCommand{Meta: Meta{Ui: ui}}
+		args := []string{"-address=" + srv.URL}
+		if stale {
+			args = append(args, "-stale")
+		}
+		if code := cmd.Run(args); code != 0 {
+			t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+		}
+
+		q := queried()
+		if len(q) != 1 {
+			t.Fatalf("unexpected queries: %v", q)
+		}
+		for path, allowStale := range q {
+			if allowStale != stale {
+				t.Fatalf("expected stale=%v for query of %s", stale, path)
+			}
+		}
+	}
+}
+
+func TestDeploymentListCommand_Pagination(t *testing.T) {
+	var lock sync.Mutex
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		query = r.URL.Query()
+		lock.Unlock()
+
+		w.Header().Set("X-Nomad-Index", "1")
+		w.Header().Set("X-Nomad-LastContact", "0")
+		w.Header().Set("X-Nomad-KnownLeader", "true")
+		w.Header().Set("X-Nomad-NextToken", "33333333-0000-0000-0000-000000000000")
+		fmt.Fprint(w, `[{"ID": "22222222-0000-0000-0000-000000000000", "JobID": "web", "Status": "running", "TaskGroups": {}}]`)
+	}))
+	defer srv.Close()
+
+	ui := new(cli.MockUi)
+	cmd := &DeploymentListCommand{Meta: Meta{Ui: ui}}
+	args := []string{"-address=" + srv.URL, "-page-size=1", "-page-token=22222222-0000-0000-0000-000000000000"}
+	if code := cmd.Run(args); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+
+	lock.Lock()
+	perPage, nextToken := query.Get("per_page"), query.Get("next_token")
+	lock.Unlock()
+	if perPage != "1" {
+		t.Fatalf("expected per_page 1, got %q", perPage)
+	}
+	if nextToken != "22222222-0000-0000-0000-000000000000" {
+		t.Fatalf("expected next_token to be passed, got %q", nextToken)
+	}
+
+	out := ui.OutputWriter.String()
+	if !strings.Contains(out, "22222222") || !strings.Contains(out, "Next page token: 33333333-0000-0000-0000-000000000000") {
+		t.Fatalf("expected deployment and next token in output: %s", out)
+	}
+
+	// The token is output to stderr when the output is meant to be parsed
+	ui = new(cli.MockUi)
+	cmd = &DeploymentListCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + srv.URL, "-page-size=1", "-quiet"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	if out := ui.OutputWriter.String(); strings.TrimSpace(out) != "22222222-0000-0000-0000-000000000000" {
+		t.Fatalf("unexpected quiet output: %s", out)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Next page token: 33333333-0000-0000-0000-000000000000") {
+		t.Fatalf("expected next token on stderr: %s", out)
+	}
 }
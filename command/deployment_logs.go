@@ -0,0 +1,247 @@
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+type DeploymentLogsCommand struct {
+	Meta
+}
+
+func (c *DeploymentLogsCommand) Help() string {
+	helpText := `
+Usage: nomad deployment logs [options] <deployment id>
+
+Logs tails the stdout and stderr of the tasks of every allocation placed by a
+deployment, such as its canaries, prefixing each line with the allocation,
+task and stream it was read from. This saves looking up the allocations of a
+failing deployment one at a time.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Logs Options:
+
+  -group
+    Only tail the allocations of the given task group.
+
+  -task
+    Only tail the given task. Defaults to every task of the allocations.
+
+  -f
+    Causes the output to not stop when the end of the logs are reached, but
+    rather to wait for additional output.
+
+  -n
+    Sets the number of lines of each log to start from, relative to the end of
+    the log. Defaults to 10.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *DeploymentLogsCommand) Synopsis() string {
+	return "Tail the logs of the allocations of a deployment"
+}
+
+func (c *DeploymentLogsCommand) Run(args []string) int {
+	var follow, verbose bool
+	var group, task string
+	var numLines int64
+
+	flags := c.Meta.FlagSet("deployment logs", FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&group, "group", "", "")
+	flags.StringVar(&task, "task", "", "")
+	flags.BoolVar(&follow, "f", false, "")
+	flags.Int64Var(&numLines, "n", defaultTailLines, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error(c.Help())
+		return 1
+	}
+	if numLines < 0 {
+		c.Ui.Error("The -n flag must not be negative")
+		return 1
+	}
+	dID := args[0]
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Do a prefix lookup
+	deploy, possible, err := getDeployment(client.Deployments(), dID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving deployment: %s", err))
+		return 1
+	}
+
+	if len(possible) != 0 {
+		c.Ui.Output(fmt.Sprintf("Prefix matched multiple deployments\n\n%s", formatDeployments(possible, length)))
+		return 0
+	}
+
+	stubs, _, err := client.Deployments().Allocations(deploy.ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving deployment allocations: %s", err))
+		return 1
+	}
+
+	var allocs []*api.Allocation
+	for _, stub := range stubs {
+		if group != "" && stub.TaskGroup != group {
+			continue
+		}
+
+		alloc, _, err := client.Allocations().Info(stub.ID, nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying allocation %q: %s", stub.ID, err))
+			return 1
+		}
+		allocs = append(allocs, alloc)
+	}
+
+	targets, err := deploymentLogTargets(allocs, task, length)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if len(targets) == 0 {
+		c.Ui.Error(fmt.Sprintf("No allocations found for deployment %q", limit(deploy.ID, length)))
+		return 1
+	}
+
+	cancel := make(chan struct{})
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signalCh
+		close(cancel)
+	}()
+
+	stream := func(t *logTarget) (io.ReadCloser, error) {
+		frames, err := client.AllocFS().Logs(t.Alloc, follow, t.Task, t.LogType, api.OriginEnd, numLines*bytesToLines, cancel, nil)
+		if err != nil {
+			return nil, err
+		}
+		frameReader := api.NewFrameReader(frames, cancel)
+		frameReader.SetUnblockTime(500 * time.Millisecond)
+		return NewLineLimitReader(frameReader, int(numLines), int(numLines*bytesToLines), 1*time.Second), nil
+	}
+
+	if err := streamDeploymentLogs(os.Stdout, targets, stream); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	return 0
+}
+
+// logTarget is a log of a task of an allocation to tail
+type logTarget struct {
+	Alloc   *api.Allocation
+	Task    string
+	LogType string
+
+	// Prefix is prepended to every line read from the log
+	Prefix string
+}
+
+// deploymentLogTargets returns the stdout and stderr logs to tail for the
+// given task, or every task, of the allocations.
+func deploymentLogTargets(allocs []*api.Allocation, task string, uuidLength int) ([]*logTarget, error) {
+	var targets []*logTarget
+	for _, alloc := range allocs {
+		var tasks []string
+		for _, tg := range alloc.Job.TaskGroups {
+			if tg.Name == nil || *tg.Name != alloc.TaskGroup {
+				continue
+			}
+			for _, t := range tg.Tasks {
+				if task == "" || t.Name == task {
+					tasks = append(tasks, t.Name)
+				}
+			}
+		}
+		if task != "" && len(tasks) == 0 {
+			return nil, fmt.Errorf("Allocation %q has no task %q", limit(alloc.ID, uuidLength), task)
+		}
+
+		for _, t := range tasks {
+			for _, logType := range []string{"stdout", "stderr"} {
+				targets = append(targets, &logTarget{
+					Alloc:   alloc,
+					Task:    t,
+					LogType: logType,
+					Prefix:  fmt.Sprintf("[%s %s %s] ", limit(alloc.ID, uuidLength), t, logType),
+				})
+			}
+		}
+	}
+	return targets, nil
+}
+
+// streamDeploymentLogs tails every target concurrently using the stream
+// function and writes their lines to out, each prefixed with the prefix of its
+// target. It returns once every log has ended.
+func streamDeploymentLogs(out io.Writer, targets []*logTarget, stream func(*logTarget) (io.ReadCloser, error)) error {
+	readers := make([]io.ReadCloser, len(targets))
+	for i, t := range targets {
+		r, err := stream(t)
+		if err != nil {
+			for _, r := range readers[:i] {
+				r.Close()
+			}
+			return fmt.Errorf("Error reading %s of task %q in allocation %q: %v", t.LogType, t.Task, t.Alloc.ID, err)
+		}
+		readers[i] = r
+	}
+
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for i, r := range readers {
+		wg.Add(1)
+		go func(t *logTarget, r io.ReadCloser) {
+			defer wg.Done()
+			defer r.Close()
+
+			scanner := bufio.NewScanner(r)
+			for scanner.Scan() {
+				lock.Lock()
+				fmt.Fprintf(out, "%s%s\n", t.Prefix, scanner.Text())
+				lock.Unlock()
+			}
+		}(targets[i], r)
+	}
+	wg.Wait()
+	return nil
+}
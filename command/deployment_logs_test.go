@@ -0,0 +1,109 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/mitchellh/cli"
+)
+
+func TestDeploymentLogsCommand_Implements(t *testing.T) {
+	var _ cli.Command = &DeploymentLogsCommand{}
+}
+
+func TestDeploymentLogsCommand_Fails(t *testing.T) {
+	ui := new(cli.MockUi)
+	cmd := &DeploymentLogsCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, cmd.Help()) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=nope", "12"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error retrieving deployment") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+}
+
+func TestDeploymentLogsCommand_Stream(t *testing.T) {
+	job := &api.Job{
+		TaskGroups: []*api.TaskGroup{
+			{Name: helper.StringToPtr("web"), Tasks: []*api.Task{{Name: "server"}, {Name: "sidecar"}}},
+			{Name: helper.StringToPtr("db"), Tasks: []*api.Task{{Name: "redis"}}},
+		},
+	}
+	allocs := []*api.Allocation{
+		{ID: "aaaaaaaa-2222-3333-4444-555555555555", TaskGroup: "web", Job: job},
+		{ID: "bbbbbbbb-2222-3333-4444-555555555555", TaskGroup: "db", Job: job},
+	}
+
+	// Every task of every allocation is tailed on both streams
+	targets, err := deploymentLogTargets(allocs, "", shortId)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(targets) != 6 {
+		t.Fatalf("expected 6 logs, got %d", len(targets))
+	}
+
+	// Only the given task is tailed
+	targets, err = deploymentLogTargets(allocs[:1], "server", shortId)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(targets) != 2 || targets[0].Task != "server" || targets[0].LogType != "stdout" || targets[1].LogType != "stderr" {
+		t.Fatalf("unexpected logs: %v", targets)
+	}
+	if _, err := deploymentLogTargets(allocs, "server", shortId); err == nil || !strings.Contains(err.Error(), `no task "server"`) {
+		t.Fatalf("expected missing task error, got %v", err)
+	}
+
+	// The logs are multiplexed with a prefix per line
+	var streamed []string
+	stream := func(t *logTarget) (io.ReadCloser, error) {
+		streamed = append(streamed, fmt.Sprintf("%s/%s/%s", t.Alloc.ID, t.Task, t.LogType))
+		return ioutil.NopCloser(strings.NewReader(fmt.Sprintf("%s line 1\n%s line 2\n", t.LogType, t.LogType))), nil
+	}
+
+	var out bytes.Buffer
+	if err := streamDeploymentLogs(&out, targets, stream); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(streamed) != 2 || streamed[0] != allocs[0].ID+"/server/stdout" || streamed[1] != allocs[0].ID+"/server/stderr" {
+		t.Fatalf("unexpected streams: %v", streamed)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	sort.Strings(lines)
+	expected := []string{
+		"[aaaaaaaa server stderr] stderr line 1",
+		"[aaaaaaaa server stderr] stderr line 2",
+		"[aaaaaaaa server stdout] stdout line 1",
+		"[aaaaaaaa server stdout] stdout line 2",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	// Errors opening a log are reported
+	stream = func(t *logTarget) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("node unreachable")
+	}
+	if err := streamDeploymentLogs(&out, targets, stream); err == nil || !strings.Contains(err.Error(), "node unreachable") {
+		t.Fatalf("expected stream error, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+)
+
+type DeploymentMonitorCommand struct {
+	Meta
+}
+
+func (c *DeploymentMonitorCommand) Help() string {
+	helpText := `
+Usage: nomad deployment monitor [options] <deployment id>
+
+Monitor is used to follow the progress of a deployment. Each change in the
+state of the deployment, such as canaries being placed or promoted and
+allocations becoming healthy, is printed until the deployment completes.
+
+The exit code is 0 if the deployment is successful, 2 if the deployment failed
+or was cancelled and 1 on any other error.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Monitor Options:
+
+  -fail-fast
+    Exit with code 2 as soon as any task group has an unhealthy allocation,
+    rather than waiting for the deployment to be marked as failed.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *DeploymentMonitorCommand) Synopsis() string {
+	return "Monitor a deployment until it completes"
+}
+
+func (c *DeploymentMonitorCommand) Run(args []string) int {
+	var verbose, failFast bool
+
+	flags := c.Meta.FlagSet("deployment monitor", FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&failFast, "fail-fast", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error(c.Help())
+		return 1
+	}
+
+	dID := args[0]
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Do a prefix lookup
+	deploy, possible, err := getDeployment(client.Deployments(), dID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving deployment: %s", err))
+		return 1
+	}
+
+	if len(possible) != 0 {
+		c.Ui.Output(fmt.Sprintf("Prefix matched multiple deployments\n\n%s", formatDeployments(possible, length)))
+		return 0
+	}
+
+	return monitorDeploymentID(c.Ui, client.Deployments(), deploy.ID, length, failFast)
+}
+
+// monitorDeploymentID monitors the deployment with the given ID by querying
+// the API and returns the exit code of monitorDeployment.
+func monitorDeploymentID(ui cli.Ui, client *api.Deployments, deployID string, length int, failFast bool) int {
+	next := func(waitIndex uint64) (*api.Deployment, uint64, error) {
+		d, meta, err := client.Info(deployID, &api.QueryOptions{WaitIndex: waitIndex})
+		if err != nil {
+			return nil, 0, err
+		}
+		return d, meta.LastIndex, nil
+	}
+
+	pui := &cli.PrefixedUi{
+		InfoPrefix:   "==> ",
+		OutputPrefix: "    ",
+		ErrorPrefix:  "==> ",
+		Ui:           ui,
+	}
+	pui.Info(fmt.Sprintf("Monitoring deployment %q", limit(deployID, length)))
+	return monitorDeployment(pui, next, failFast)
+}
+
+// monitorDeployment prints the events of a deployment as they occur until the
+// deployment reaches a terminal status. The next function blocks until the
+// deployment changes after the given index and returns its new state and
+// index. The returned exit code reflects the final status of the deployment.
+// If failFast is set, monitoring stops as soon as a task group has an
+// unhealthy allocation, as if the deployment had failed.
+func monitorDeployment(ui cli.Ui, next func(waitIndex uint64) (*api.Deployment, uint64, error), failFast bool) int {
+	seen := make(map[deploymentEvent]struct{})
+	var index uint64
+	for {
+		d, lastIndex, err := next(index)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Error reading deployment: %s", err))
+			return 1
+		}
+		index = lastIndex
+
+		// Only print the events not printed for a previous state
+		for _, e := range deploymentEvents(d) {
+			key := deploymentEvent{Group: e.Group, Message: e.Message}
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			if e.Group != "" {
+				ui.Output(fmt.Sprintf("Task Group %q: %s", e.Group, e.Message))
+			} else {
+				ui.Output(e.Message)
+			}
+		}
+
+		switch d.Status {
+		case structs.DeploymentStatusRunning, structs.DeploymentStatusPaused:
+			if failFast {
+				if tg := failedDeploymentGroup(d); tg != "" {
+					ui.Info(fmt.Sprintf("Task group %q of deployment %q failed", tg, d.ID))
+					return 2
+				}
+			}
+			continue
+		case structs.DeploymentStatusSuccessful:
+			ui.Info(fmt.Sprintf("Deployment %q successful", d.ID))
+			return 0
+		default:
+			ui.Info(fmt.Sprintf("Deployment %q finished with status %q", d.ID, d.Status))
+			return 2
+		}
+	}
+}
+
+// failedDeploymentGroup returns the first task group of the deployment, by
+// name, that has an unhealthy allocation or an empty string if there is none.
+func failedDeploymentGroup(d *api.Deployment) string {
+	groups := make([]string, 0, len(d.TaskGroups))
+	for tg, state := range d.TaskGroups {
+		if state.UnhealthyAllocs > 0 {
+			groups = append(groups, tg)
+		}
+	}
+	if len(groups) == 0 {
+		return ""
+	}
+	sort.Strings(groups)
+	return groups[0]
+}
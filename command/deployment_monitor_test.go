@@ -0,0 +1,183 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+func TestDeploymentMonitorCommand_Implements(t *testing.T) {
+	var _ cli.Command = &DeploymentMonitorCommand{}
+}
+
+func TestDeploymentMonitorCommand_Fails(t *testing.T) {
+	ui := new(cli.MockUi)
+	cmd := &DeploymentMonitorCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, cmd.Help()) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=nope", "12"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error retrieving deployment") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+}
+
+// testDeploymentProgression returns a function feeding the given states of a
+// deployment to monitorDeployment in order.
+func testDeploymentProgression(t *testing.T, states ...*api.Deployment) func(uint64) (*api.Deployment, uint64, error) {
+	i := 0
+	return func(waitIndex uint64) (*api.Deployment, uint64, error) {
+		if i >= len(states) {
+			t.Fatalf("unexpected query after terminal state")
+		}
+		if expected := uint64(i * 10); waitIndex != expected {
+			t.Fatalf("expected wait index %d, got %d", expected, waitIndex)
+		}
+		d := states[i]
+		i++
+		return d, uint64(i * 10), nil
+	}
+}
+
+func TestDeploymentMonitorCommand_Monitor(t *testing.T) {
+	state := func(status string, canaries int, promoted bool, healthy int) *api.Deployment {
+		return &api.Deployment{
+			ID:                "11111111-2222-3333-4444-555555555555",
+			JobVersion:        1,
+			Status:            status,
+			StatusDescription: "Deployment " + status,
+			TaskGroups: map[string]*api.DeploymentState{
+				"web": {
+					PlacedCanaries:  make([]string, canaries),
+					DesiredCanaries: 1,
+					Promoted:        promoted,
+					DesiredTotal:    2,
+					HealthyAllocs:   healthy,
+				},
+			},
+		}
+	}
+
+	ui := new(cli.MockUi)
+	next := testDeploymentProgression(t,
+		state("running", 0, false, 0),
+		state("running", 1, false, 0),
+		state("running", 1, false, 0),
+		state("running", 1, true, 1),
+		state("successful", 1, true, 2),
+	)
+	if code := monitorDeployment(ui, next, false); code != 0 {
+		t.Fatalf("expected exit code 0, got: %d", code)
+	}
+
+	expected := []string{
+		"Deployment created for job version 1",
+		`Task Group "web": 1 of 1 canaries placed`,
+		`Task Group "web": Canaries promoted`,
+		`Task Group "web": 1 of 2 allocations healthy`,
+		`Task Group "web": 2 of 2 allocations healthy`,
+		"Deployment successful: Deployment successful",
+		`Deployment "11111111-2222-3333-4444-555555555555" successful`,
+	}
+	lines := strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got:\n%s", len(expected), ui.OutputWriter.String())
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Fatalf("line %d: expected %q, got %q", i, expected[i], line)
+		}
+	}
+
+	// Failed deployments exit with 2
+	ui = new(cli.MockUi)
+	next = testDeploymentProgression(t,
+		state("running", 1, false, 0),
+		state("failed", 1, false, 0),
+	)
+	if code := monitorDeployment(ui, next, false); code != 2 {
+		t.Fatalf("expected exit code 2, got: %d", code)
+	}
+	if out := ui.OutputWriter.String(); !strings.Contains(out, "Deployment failed") || !strings.Contains(out, `status "failed"`) {
+		t.Fatalf("expected failure output, got: %s", out)
+	}
+
+	// Query errors exit with 1
+	ui = new(cli.MockUi)
+	next = func(uint64) (*api.Deployment, uint64, error) {
+		return nil, 0, fmt.Errorf("connection refused")
+	}
+	if code := monitorDeployment(ui, next, false); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error reading deployment") {
+		t.Fatalf("expected read error, got: %s", out)
+	}
+}
+
+func TestDeploymentMonitorCommand_FailFast(t *testing.T) {
+	state := func(status string, unhealthy int) *api.Deployment {
+		return &api.Deployment{
+			ID:                "11111111-2222-3333-4444-555555555555",
+			JobVersion:        1,
+			Status:            status,
+			StatusDescription: "Deployment " + status,
+			TaskGroups: map[string]*api.DeploymentState{
+				"web": {
+					DesiredTotal:  2,
+					PlacedAllocs:  2,
+					HealthyAllocs: 1,
+				},
+				"cache": {
+					DesiredTotal:    1,
+					PlacedAllocs:    1,
+					UnhealthyAllocs: unhealthy,
+				},
+			},
+		}
+	}
+
+	// The states after the group fails are never queried
+	ui := new(cli.MockUi)
+	next := testDeploymentProgression(t,
+		state("running", 0),
+		state("running", 1),
+	)
+	if code := monitorDeployment(ui, next, true); code != 2 {
+		t.Fatalf("expected exit code 2, got: %d", code)
+	}
+	out := ui.OutputWriter.String()
+	if !strings.Contains(out, `Task Group "cache": 1 allocations unhealthy`) {
+		t.Fatalf("expected unhealthy event, got: %s", out)
+	}
+	if !strings.Contains(out, `Task group "cache" of deployment "11111111-2222-3333-4444-555555555555" failed`) {
+		t.Fatalf("expected failed group output, got: %s", out)
+	}
+
+	// Without -fail-fast the deployment is followed until it fails
+	ui = new(cli.MockUi)
+	next = testDeploymentProgression(t,
+		state("running", 0),
+		state("running", 1),
+		state("failed", 1),
+	)
+	if code := monitorDeployment(ui, next, false); code != 2 {
+		t.Fatalf("expected exit code 2, got: %d", code)
+	}
+	if out := ui.OutputWriter.String(); !strings.Contains(out, `status "failed"`) {
+		t.Fatalf("expected failure output, got: %s", out)
+	}
+}
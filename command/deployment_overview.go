@@ -0,0 +1,179 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+type DeploymentOverviewCommand struct {
+	Meta
+}
+
+// jobDeploymentOverview is a job and its latest deployment, which is nil if
+// the job has never been deployed.
+type jobDeploymentOverview struct {
+	JobID      string
+	Deployment *api.Deployment
+}
+
+func (c *DeploymentOverviewCommand) Help() string {
+	helpText := `
+Usage: nomad deployment overview [options]
+
+Overview lists the jobs along with the status and health of their latest
+deployment, one job per line. Jobs that have never been deployed, such as batch
+jobs, are listed without a deployment.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Overview Options:
+
+  -prefix
+    Only list the jobs whose ID starts with the given prefix.
+
+  -json
+    Output the jobs and their latest deployment in a JSON format. They are
+    wrapped in an object whose "SchemaVersion" field is incremented on every
+    breaking change to the output and whose "Data" field holds them.
+
+  -json-raw
+    Output the JSON without the envelope recording its schema version. Must be
+    used with -json.
+
+  -t
+    Format and display the jobs and their latest deployment using a Go
+    template.
+
+  -stale
+    Allow the jobs and deployments to be read from any server rather than only
+    the leader, reducing the load on the leader at the risk of reading
+    deployments that are slightly out of date.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *DeploymentOverviewCommand) Synopsis() string {
+	return "Display the latest deployment of every job"
+}
+
+func (c *DeploymentOverviewCommand) Run(args []string) int {
+	var json, rawJSON, stale, verbose bool
+	var prefix, tmpl string
+
+	flags := c.Meta.FlagSet("deployment overview", FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&stale, "stale", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.BoolVar(&rawJSON, "json-raw", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.StringVar(&prefix, "prefix", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error(c.Help())
+		return 1
+	}
+
+	if rawJSON && !json {
+		c.Ui.Error("The -json-raw flag can only be used with -json")
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	jobs, _, err := client.Jobs().List(&api.QueryOptions{AllowStale: stale, Prefix: prefix})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving jobs: %s", err))
+		return 1
+	}
+
+	jobIDs := make([]string, len(jobs))
+	for i, job := range jobs {
+		jobIDs[i] = job.ID
+	}
+	sort.Strings(jobIDs)
+
+	q := &api.QueryOptions{AllowStale: stale}
+	overview := make([]*jobDeploymentOverview, len(jobIDs))
+	for i, jobID := range jobIDs {
+		deploy, _, err := client.Jobs().LatestDeployment(jobID, q)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error retrieving latest deployment of job %q: %s", jobID, err))
+			return 1
+		}
+		overview[i] = &jobDeploymentOverview{JobID: jobID, Deployment: deploy}
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, versionedData(json, rawJSON, overview))
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatDeploymentOverview(overview, length))
+	return 0
+}
+
+// formatDeploymentOverview formats the latest deployment of each job on a
+// line, along with the number of healthy allocations out of those desired
+// across its task groups.
+func formatDeploymentOverview(overview []*jobDeploymentOverview, uuidLength int) string {
+	if len(overview) == 0 {
+		return "No jobs found"
+	}
+
+	rows := make([]string, len(overview)+1)
+	rows[0] = "Job ID|Deployment ID|Job Version|Status|Healthy|Description"
+	for i, o := range overview {
+		d := o.Deployment
+		if d == nil {
+			rows[i+1] = fmt.Sprintf("%s|<none>|-|-|-|-", o.JobID)
+			continue
+		}
+
+		healthy, desired := 0, 0
+		for _, state := range d.TaskGroups {
+			healthy += state.HealthyAllocs
+			desired += state.DesiredTotal
+		}
+		rows[i+1] = fmt.Sprintf("%s|%s|%d|%s|%d/%d|%s",
+			o.JobID,
+			limit(d.ID, uuidLength),
+			d.JobVersion,
+			d.Status,
+			healthy,
+			desired,
+			d.StatusDescription)
+	}
+	return formatList(rows)
+}
@@ -0,0 +1,101 @@
+package command
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestDeploymentOverviewCommand_Implements(t *testing.T) {
+	var _ cli.Command = &DeploymentOverviewCommand{}
+}
+
+func TestDeploymentOverviewCommand_Fails(t *testing.T) {
+	ui := new(cli.MockUi)
+	cmd := &DeploymentOverviewCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, cmd.Help()) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-json-raw"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-json-raw") {
+		t.Fatalf("expected -json-raw error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=nope"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error retrieving jobs") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+}
+
+func TestDeploymentOverviewCommand_Run(t *testing.T) {
+	srv, queried := testStaleServer(t, map[string]string{
+		"/v1/jobs": `[{"ID": "web"}, {"ID": "api"}, {"ID": "backup"}]`,
+		"/v1/job/web/deployment": `{"ID": "11111111-2222-3333-4444-555555555555", "JobID": "web", "JobVersion": 3,
+			"Status": "running", "StatusDescription": "Deployment is running",
+			"TaskGroups": {"web": {"DesiredTotal": 3, "HealthyAllocs": 1}, "cache": {"DesiredTotal": 1, "HealthyAllocs": 1}}}`,
+		"/v1/job/api/deployment": `{"ID": "22222222-2222-3333-4444-555555555555", "JobID": "api", "JobVersion": 1,
+			"Status": "failed", "StatusDescription": "Failed due to unhealthy allocations",
+			"TaskGroups": {"api": {"DesiredTotal": 2, "HealthyAllocs": 0, "UnhealthyAllocs": 2}}}`,
+		"/v1/job/backup/deployment": `null`,
+	})
+	defer srv.Close()
+
+	ui := new(cli.MockUi)
+	cmd := &DeploymentOverviewCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + srv.URL, "-stale"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and a line per job, got: %v", lines)
+	}
+	expected := [][]string{
+		{"api", "22222222", "1", "failed", "0/2", "Failed due to unhealthy allocations"},
+		{"backup", "<none>"},
+		{"web", "11111111", "3", "running", "2/4", "Deployment is running"},
+	}
+	for i, fields := range expected {
+		for _, f := range fields {
+			if !strings.Contains(lines[i+1], f) {
+				t.Fatalf("expected line %q to contain %q", lines[i+1], f)
+			}
+		}
+	}
+
+	for path, stale := range queried() {
+		if !stale {
+			t.Fatalf("expected stale query of %s", path)
+		}
+	}
+	// The JSON output is wrapped in the versioned envelope
+	ui = new(cli.MockUi)
+	cmd = &DeploymentOverviewCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + srv.URL, "-json"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	var out struct {
+		SchemaVersion int
+		Data          []*jobDeploymentOverview
+	}
+	if err := json.Unmarshal([]byte(ui.OutputWriter.String()), &out); err != nil {
+		t.Fatalf("invalid JSON output: %v: %s", err, ui.OutputWriter.String())
+	}
+	if out.SchemaVersion != jsonSchemaVersion || len(out.Data) != 3 || out.Data[0].JobID != "api" {
+		t.Fatalf("unexpected JSON output: %s", ui.OutputWriter.String())
+	}
+}
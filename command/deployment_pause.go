@@ -22,6 +22,11 @@ General Options:
 
 Pause Options:
 
+  -detach
+    Return immediately instead of entering monitor mode. If pausing the
+    deployment creates an evaluation, its ID will be printed to the screen,
+    which can be used to examine the evaluation using the eval-status command.
+
   -verbose
     Display full information.
 `
@@ -33,10 +38,11 @@ func (c *DeploymentPauseCommand) Synopsis() string {
 }
 
 func (c *DeploymentPauseCommand) Run(args []string) int {
-	var verbose bool
+	var detach, verbose bool
 
 	flags := c.Meta.FlagSet("deployment pause", FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&detach, "detach", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 
 	if err := flags.Parse(args); err != nil {
@@ -77,11 +83,15 @@ func (c *DeploymentPauseCommand) Run(args []string) int {
 		return 0
 	}
 
-	if _, _, err := client.Deployments().Pause(deploy.ID, true, nil); err != nil {
+	u, _, err := client.Deployments().Pause(deploy.ID, true, nil)
+	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error pausing deployment: %s", err))
 		return 1
 	}
 
 	c.Ui.Output(fmt.Sprintf("Deployment %q paused", deploy.ID))
-	return 0
+	return monitorDeploymentEval(c.Ui, u.EvalID, detach, func(evalID string) int {
+		mon := newMonitor(c.Ui, client, length)
+		return mon.monitor(evalID, false)
+	})
 }
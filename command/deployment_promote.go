@@ -3,9 +3,18 @@ package command
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+)
+
+const (
+	// defaultWaitHealthyTimeout is how long -wait-healthy waits for the
+	// promoted task groups to become healthy unless -timeout is given.
+	defaultWaitHealthyTimeout = 5 * time.Minute
 )
 
 type DeploymentPromoteCommand struct {
@@ -42,6 +51,27 @@ Promote Options:
 	resume, the evaluation ID will be printed to the screen, which can be used
 	to examine the evaluation using the eval-status command.
 
+  -wait-healthy
+    Wait for all allocations of the promoted task groups to be healthy after
+    the promotion. The exit code is 0 if they become healthy, 2 if the
+    deployment fails or the timeout is reached first and 1 on any other error.
+    Can not be used with -json or -t.
+
+  -timeout
+    The maximum time to wait with -wait-healthy. Defaults to 5m.
+
+  -json
+    Output a confirmation of the deployment update in a JSON format instead of
+    monitoring its evaluation. The confirmation holds the "DeploymentID", the
+    "EvalID" and "Evaluation" created, if any, and the "Action" taken, "promote".
+    It is wrapped in an object whose "SchemaVersion" field is incremented on
+    every breaking change to the output and whose "Data" field holds the
+    confirmation.
+
+  -t
+    Format and display the confirmation of the deployment update using a Go
+    template instead of monitoring its evaluation.
+
   -verbose
     Display full information.
 `
@@ -53,15 +83,21 @@ func (c *DeploymentPromoteCommand) Synopsis() string {
 }
 
 func (c *DeploymentPromoteCommand) Run(args []string) int {
-	var all, detach, verbose bool
+	var all, detach, verbose, json, waitHealthy bool
 	var groups []string
+	var tmpl string
+	var timeout time.Duration
 
 	flags := c.Meta.FlagSet("deployment resume", FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&all, "all", false, "")
 	flags.BoolVar(&detach, "detach", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
 	flags.Var((*flaghelper.StringFlag)(&groups), "group", "")
+	flags.BoolVar(&waitHealthy, "wait-healthy", false, "")
+	flags.DurationVar(&timeout, "timeout", defaultWaitHealthyTimeout, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -77,6 +113,14 @@ func (c *DeploymentPromoteCommand) Run(args []string) int {
 		c.Ui.Error("Either -all or one or more -group flags must be specified.")
 		return 1
 	}
+	if waitHealthy && (json || len(tmpl) > 0) {
+		c.Ui.Error("The -wait-healthy flag can not be used with -json or -t")
+		return 1
+	}
+	if timeout <= 0 {
+		c.Ui.Error("The -timeout flag must be a positive duration")
+		return 1
+	}
 	dID := args[0]
 
 	// Truncate the id unless full length is requested
@@ -116,12 +160,112 @@ func (c *DeploymentPromoteCommand) Run(args []string) int {
 		return 1
 	}
 
-	// Nothing to do
+	if json || len(tmpl) > 0 {
+		return outputDeploymentUpdate(c.Ui, deploy.ID, deploymentActionPromote, u.EvalID, json, tmpl, func(evalID string) (*api.Evaluation, error) {
+			eval, _, err := client.Evaluations().Info(evalID, nil)
+			return eval, err
+		})
+	}
+
+	// Monitor the evaluation before waiting on the allocations it places
 	evalCreated := u.EvalID != ""
-	if detach || !evalCreated {
+	if !detach && evalCreated {
+		mon := newMonitor(c.Ui, client, length)
+		if code := mon.monitor(u.EvalID, false); code != 0 || !waitHealthy {
+			return code
+		}
+	}
+	if !waitHealthy {
 		return 0
 	}
 
-	mon := newMonitor(c.Ui, client, length)
-	return mon.monitor(u.EvalID, false)
+	if all {
+		groups = nil
+	}
+	next := func(waitIndex uint64, waitTime time.Duration) (*api.Deployment, uint64, error) {
+		d, meta, err := client.Deployments().Info(deploy.ID, &api.QueryOptions{WaitIndex: waitIndex, WaitTime: waitTime})
+		if err != nil {
+			return nil, 0, err
+		}
+		return d, meta.LastIndex, nil
+	}
+
+	ui := &cli.PrefixedUi{
+		InfoPrefix:   "==> ",
+		OutputPrefix: "    ",
+		ErrorPrefix:  "==> ",
+		Ui:           c.Ui,
+	}
+	ui.Info(fmt.Sprintf("Waiting for the promoted task groups of deployment %q to be healthy", limit(deploy.ID, length)))
+	return waitPromotedHealthy(ui, groups, timeout, next)
+}
+
+// waitPromotedHealthy blocks until every allocation of the promoted task
+// groups is healthy, the deployment reaches a terminal status or the timeout
+// is reached. If groups is empty, every task group with canaries is waited
+// on. The next function blocks for at most the wait time until the deployment
+// changes after the given index and returns its new state and index.
+func waitPromotedHealthy(ui cli.Ui, groups []string, timeout time.Duration,
+	next func(waitIndex uint64, waitTime time.Duration) (*api.Deployment, uint64, error)) int {
+
+	deadline := time.Now().Add(timeout)
+	var index uint64
+	for {
+		remaining := deadline.Sub(time.Now())
+		if remaining <= 0 {
+			ui.Error(fmt.Sprintf("Timed out after %s waiting for the promoted task groups to be healthy", timeout))
+			return 2
+		}
+
+		d, lastIndex, err := next(index, remaining)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Error reading deployment: %s", err))
+			return 1
+		}
+		index = lastIndex
+
+		switch d.Status {
+		case structs.DeploymentStatusSuccessful:
+			ui.Info(fmt.Sprintf("Deployment %q successful", d.ID))
+			return 0
+		case structs.DeploymentStatusRunning, structs.DeploymentStatusPaused:
+		default:
+			ui.Error(fmt.Sprintf("Deployment %q finished with status %q", d.ID, d.Status))
+			return 2
+		}
+
+		healthy, err := promotedGroupsHealthy(d, groups)
+		if err != nil {
+			ui.Error(err.Error())
+			return 1
+		}
+		if healthy {
+			ui.Info("Promoted task groups are healthy")
+			return 0
+		}
+	}
+}
+
+// promotedGroupsHealthy returns whether the given task groups of the deployment
+// are promoted and all of their allocations are healthy. If groups is empty,
+// every task group with canaries is checked.
+func promotedGroupsHealthy(d *api.Deployment, groups []string) (bool, error) {
+	if len(groups) == 0 {
+		for tg, state := range d.TaskGroups {
+			if state.DesiredCanaries > 0 {
+				groups = append(groups, tg)
+			}
+		}
+	}
+
+	for _, tg := range groups {
+		state, ok := d.TaskGroups[tg]
+		if !ok {
+			return false, fmt.Errorf("Deployment %q has no task group %q", d.ID, tg)
+		}
+		if !state.Promoted || state.HealthyAllocs < state.DesiredTotal {
+			return false, nil
+		}
+	}
+	return true, nil
 }
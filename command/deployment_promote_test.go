@@ -1,9 +1,13 @@
 package command
 
 import (
+	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/mitchellh/cli"
 )
 
@@ -40,3 +44,132 @@ func TestDeploymentPromoteCommand_Fails(t *testing.T) {
 	}
 	ui.ErrorWriter.Reset()
 }
+
+func TestDeploymentPromoteCommand_UpdateJSON(t *testing.T) {
+	deployID := "6d8a9c1e-2b3f-4a5d-8e7f-0a1b2c3d4e5f"
+	evalID := "0f4e5b1b-6f2c-4b7a-9d0e-1c2b3a4d5e6f"
+	info := func(id string) (*api.Evaluation, error) {
+		if id != evalID {
+			return nil, fmt.Errorf("unknown eval %q", id)
+		}
+		return &api.Evaluation{ID: evalID, Status: "complete", TriggeredBy: "deployment-watcher"}, nil
+	}
+
+	ui := new(cli.MockUi)
+	if code := outputDeploymentUpdate(ui, deployID, deploymentActionPromote, evalID, true, "", info); code != 0 {
+		t.Fatalf("expected exit code 0, got: %d: %s", code, ui.ErrorWriter.String())
+	}
+
+	var out struct {
+		SchemaVersion int
+		Data          struct {
+			DeploymentID string
+			EvalID       string
+			Action       string
+			Evaluation   *api.Evaluation
+		}
+	}
+	if err := json.Unmarshal([]byte(ui.OutputWriter.String()), &out); err != nil {
+		t.Fatalf("invalid JSON output: %v: %s", err, ui.OutputWriter.String())
+	}
+	if out.SchemaVersion != 1 || out.Data.DeploymentID != deployID || out.Data.EvalID != evalID || out.Data.Action != "promote" {
+		t.Fatalf("unexpected output: %s", ui.OutputWriter.String())
+	}
+	if out.Data.Evaluation == nil || out.Data.Evaluation.TriggeredBy != "deployment-watcher" {
+		t.Fatalf("expected the evaluation in the output: %s", ui.OutputWriter.String())
+	}
+
+	// Templates are rendered against the confirmation
+	ui = new(cli.MockUi)
+	if code := outputDeploymentUpdate(ui, deployID, deploymentActionPromote, evalID, false, "{{.Action}} {{.Evaluation.Status}}", info); code != 0 {
+		t.Fatalf("expected exit code 0, got: %d: %s", code, ui.ErrorWriter.String())
+	}
+	if out := strings.TrimSpace(ui.OutputWriter.String()); out != "promote complete" {
+		t.Fatalf("expected template output %q, got: %q", "promote complete", out)
+	}
+
+	// Errors looking up the evaluation are reported
+	ui = new(cli.MockUi)
+	if code := outputDeploymentUpdate(ui, deployID, deploymentActionPromote, "missing", true, "", info); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error retrieving evaluation") {
+		t.Fatalf("expected eval lookup error, got: %s", out)
+	}
+}
+
+func TestDeploymentPromoteCommand_WaitHealthy(t *testing.T) {
+	deployment := func(status string, promoted bool, healthy int) *api.Deployment {
+		return &api.Deployment{
+			ID:     "0f4e5b1b-6f2c-4b7a-9d0e-1c2b3a4d5e6f",
+			Status: status,
+			TaskGroups: map[string]*api.DeploymentState{
+				"web": {Promoted: promoted, DesiredCanaries: 1, DesiredTotal: 3, HealthyAllocs: healthy},
+				"db":  {DesiredTotal: 1, HealthyAllocs: 0},
+			},
+		}
+	}
+
+	// The promoted groups become healthy after a few updates
+	states := []*api.Deployment{
+		deployment("running", false, 1),
+		deployment("running", true, 1),
+		deployment("running", true, 3),
+	}
+	var indexes []uint64
+	next := func(waitIndex uint64, waitTime time.Duration) (*api.Deployment, uint64, error) {
+		indexes = append(indexes, waitIndex)
+		d := states[0]
+		if len(states) > 1 {
+			states = states[1:]
+		}
+		return d, waitIndex + 1, nil
+	}
+
+	ui := new(cli.MockUi)
+	if code := waitPromotedHealthy(ui, nil, time.Minute, next); code != 0 {
+		t.Fatalf("expected exit code 0, got: %d: %s", code, ui.ErrorWriter.String())
+	}
+	if len(indexes) != 3 || indexes[2] != 2 {
+		t.Fatalf("expected three blocking queries, got indexes %v", indexes)
+	}
+	if out := ui.OutputWriter.String(); !strings.Contains(out, "healthy") {
+		t.Fatalf("expected healthy message, got: %s", out)
+	}
+
+	// The deployment never becomes healthy so the wait times out
+	next = func(waitIndex uint64, waitTime time.Duration) (*api.Deployment, uint64, error) {
+		if waitIndex > 0 {
+			time.Sleep(waitTime)
+		}
+		return deployment("running", true, 1), waitIndex + 1, nil
+	}
+	ui = new(cli.MockUi)
+	if code := waitPromotedHealthy(ui, []string{"web"}, 50*time.Millisecond, next); code != 2 {
+		t.Fatalf("expected exit code 2, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Timed out") {
+		t.Fatalf("expected timeout error, got: %s", out)
+	}
+
+	// A failed deployment stops the wait
+	next = func(waitIndex uint64, waitTime time.Duration) (*api.Deployment, uint64, error) {
+		return deployment("failed", true, 1), waitIndex + 1, nil
+	}
+	ui = new(cli.MockUi)
+	if code := waitPromotedHealthy(ui, []string{"web"}, time.Minute, next); code != 2 {
+		t.Fatalf("expected exit code 2, got: %d", code)
+	}
+
+	// Unknown groups are an error
+	next = func(waitIndex uint64, waitTime time.Duration) (*api.Deployment, uint64, error) {
+		return deployment("running", true, 3), waitIndex + 1, nil
+	}
+	ui = new(cli.MockUi)
+	if code := waitPromotedHealthy(ui, []string{"cache"}, time.Minute, next); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, `no task group "cache"`) {
+		t.Fatalf("expected unknown group error, got: %s", out)
+	}
+}
@@ -0,0 +1,187 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// rerunMetaKey is the job meta key set to the time of the rerun. Changing
+	// the job's meta is what causes its allocations to be replaced.
+	rerunMetaKey = "nomad_rerun"
+)
+
+type DeploymentRerunCommand struct {
+	Meta
+}
+
+func (c *DeploymentRerunCommand) Help() string {
+	helpText := `
+Usage: nomad deployment rerun [options] <job id>
+
+Rerun is used to deploy the current version of a job again, replacing all of
+its allocations, such as to pull a new image published under the same tag.
+
+The job specification is resubmitted as is, other than its "nomad_rerun" meta
+key being set to the time of the rerun. No other field of the job is changed,
+but as the meta of a job is part of its tasks the scheduler replaces every
+allocation, following the update strategy of the job, and the job version is
+incremented.
+
+Note that this modifies the job specification stored by Nomad: the
+"nomad_rerun" meta key remains set until the job is next submitted without it.
+Until then, planning the job file it was submitted from shows the key being
+removed, and running that file replaces all allocations again. Add the key to
+the job file to avoid this.
+
+Once the evaluation of the rerun completes, the ID of the deployment it created
+is output. Jobs without an update strategy have their allocations replaced
+without a deployment.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Rerun Options:
+
+  -detach
+    Return immediately with the evaluation ID instead of waiting for the
+    deployment to be created.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *DeploymentRerunCommand) Synopsis() string {
+	return "Deploy the current version of a job again"
+}
+
+func (c *DeploymentRerunCommand) Run(args []string) int {
+	var detach, verbose bool
+
+	flags := c.Meta.FlagSet("deployment rerun", FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&detach, "detach", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error(c.Help())
+		return 1
+	}
+
+	jobID := args[0]
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Check if the job exists
+	jobs, _, err := client.Jobs().PrefixList(jobID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listing jobs: %s", err))
+		return 1
+	}
+	id, ambiguous, err := resolveJobPrefix(jobs, jobID, false)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if ambiguous {
+		c.Ui.Output(fmt.Sprintf("Prefix matched multiple jobs\n\n%s", createStatusListOutput(jobs)))
+		return ambiguousPrefixExitCode
+	}
+
+	// Prefix lookup matched a single job
+	job, _, err := client.Jobs().Info(id, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving job: %s", err))
+		return 1
+	}
+
+	// Resubmit the job, failing if it was modified since it was read
+	modifyIndex := *job.JobModifyIndex
+	setRerunMeta(job, time.Now())
+	resp, _, err := client.Jobs().EnforceRegister(job, modifyIndex, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error rerunning job: %s", err))
+		return 1
+	}
+
+	if detach || resp.EvalID == "" {
+		if resp.EvalID != "" {
+			c.Ui.Output("Evaluation ID: " + resp.EvalID)
+		}
+		return 0
+	}
+
+	deploy, err := waitForRerunDeployment(client, resp.EvalID, *job.ID, resp.JobModifyIndex)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if deploy == nil {
+		c.Ui.Output(fmt.Sprintf("No deployment was created for job %q; its allocations are replaced without one", *job.ID))
+		return 0
+	}
+
+	c.Ui.Output(fmt.Sprintf("Deployment ID: %s", limit(deploy.ID, length)))
+	return 0
+}
+
+// setRerunMeta sets the rerun meta key of the job to the time of the rerun
+func setRerunMeta(job *api.Job, now time.Time) {
+	if job.Meta == nil {
+		job.Meta = make(map[string]string)
+	}
+	job.Meta[rerunMetaKey] = now.UTC().Format(time.RFC3339Nano)
+}
+
+// waitForRerunDeployment blocks until the evaluation of the rerun completes
+// and returns the deployment it created for the given job modify index, or nil
+// if no deployment was created.
+func waitForRerunDeployment(client *api.Client, evalID, jobID string, jobModifyIndex uint64) (*api.Deployment, error) {
+	var index uint64
+	for {
+		eval, meta, err := client.Evaluations().Info(evalID, &api.QueryOptions{WaitIndex: index})
+		if err != nil {
+			return nil, fmt.Errorf("Error reading evaluation: %s", err)
+		}
+		if eval.Status == structs.EvalStatusFailed || eval.Status == structs.EvalStatusCancelled {
+			return nil, fmt.Errorf("Evaluation %q finished with status %q: %s", evalID, eval.Status, eval.StatusDescription)
+		}
+		if eval.Status != structs.EvalStatusPending {
+			break
+		}
+		index = meta.LastIndex
+	}
+
+	deploy, _, err := client.Jobs().LatestDeployment(jobID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving deployment: %s", err)
+	}
+	if deploy == nil || deploy.JobModifyIndex != jobModifyIndex {
+		return nil, nil
+	}
+	return deploy, nil
+}
@@ -0,0 +1,129 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+func TestDeploymentRerunCommand_Implements(t *testing.T) {
+	var _ cli.Command = &DeploymentRerunCommand{}
+}
+
+func TestDeploymentRerunCommand_Fails(t *testing.T) {
+	ui := new(cli.MockUi)
+	cmd := &DeploymentRerunCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, cmd.Help()) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=nope", "example"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error listing jobs") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+}
+
+func TestDeploymentRerunCommand_Run(t *testing.T) {
+	const (
+		evalID   = "aaaaaaaa-2222-3333-4444-555555555555"
+		deployID = "dddddddd-2222-3333-4444-555555555555"
+	)
+
+	var lock sync.Mutex
+	var registered *api.RegisterJobRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Nomad-Index", "60")
+		w.Header().Set("X-Nomad-LastContact", "0")
+		w.Header().Set("X-Nomad-KnownLeader", "true")
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v1/jobs":
+			fmt.Fprint(w, `[{"ID":"web","Name":"web","Type":"service","Status":"running"}]`)
+		case r.Method == "GET" && r.URL.Path == "/v1/job/web":
+			fmt.Fprint(w, `{"ID":"web","Name":"web","Type":"service","Version":3,"JobModifyIndex":40,"Meta":{"owner":"ops"}}`)
+		case r.Method == "PUT" && r.URL.Path == "/v1/jobs":
+			var req api.RegisterJobRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("err: %v", err)
+			}
+			lock.Lock()
+			registered = &req
+			lock.Unlock()
+			fmt.Fprintf(w, `{"EvalID":%q,"EvalCreateIndex":50,"JobModifyIndex":50}`, evalID)
+		case r.Method == "GET" && r.URL.Path == "/v1/evaluation/"+evalID:
+			fmt.Fprintf(w, `{"ID":%q,"JobID":"web","Status":"complete"}`, evalID)
+		case r.Method == "GET" && r.URL.Path == "/v1/job/web/deployment":
+			fmt.Fprintf(w, `{"ID":%q,"JobID":"web","JobVersion":4,"JobModifyIndex":50,"Status":"running"}`, deployID)
+		default:
+			t.Errorf("unexpected %s of %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ui := new(cli.MockUi)
+	cmd := &DeploymentRerunCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + srv.URL, "-verbose", "we"}); code != 0 {
+		t.Fatalf("expected exit code 0, got: %d: %s", code, ui.ErrorWriter.String())
+	}
+
+	// The job is resubmitted unchanged other than the rerun meta key
+	lock.Lock()
+	defer lock.Unlock()
+	if registered == nil {
+		t.Fatalf("expected the job to be registered")
+	}
+	if !registered.EnforceIndex || registered.JobModifyIndex != 40 {
+		t.Fatalf("expected enforced index 40, got %v %d", registered.EnforceIndex, registered.JobModifyIndex)
+	}
+	job := registered.Job
+	if job.Meta["owner"] != "ops" || job.Meta[rerunMetaKey] == "" {
+		t.Fatalf("unexpected meta: %v", job.Meta)
+	}
+
+	// The new deployment is output
+	if out := ui.OutputWriter.String(); !strings.Contains(out, "Deployment ID: "+deployID) {
+		t.Fatalf("expected new deployment, got: %s", out)
+	}
+}
+
+func TestDeploymentRerunCommand_AmbiguousPrefix(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Nomad-Index", "60")
+		w.Header().Set("X-Nomad-LastContact", "0")
+		w.Header().Set("X-Nomad-KnownLeader", "true")
+
+		if r.Method != "GET" || r.URL.Path != "/v1/jobs" {
+			t.Errorf("unexpected %s of %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `[{"ID":"web","Name":"web","Type":"service","Status":"running"},{"ID":"web-canary","Name":"web-canary","Type":"service","Status":"running"}]`)
+	}))
+	defer srv.Close()
+
+	ui := new(cli.MockUi)
+	cmd := &DeploymentRerunCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + srv.URL, "we"}); code != ambiguousPrefixExitCode {
+		t.Fatalf("expected exit code %d, got: %d", ambiguousPrefixExitCode, code)
+	}
+	if out := ui.OutputWriter.String(); !strings.Contains(out, "Prefix matched multiple jobs") {
+		t.Fatalf("expected multiple jobs output, got: %s", out)
+	}
+}
@@ -23,9 +23,9 @@ General Options:
 Resume Options:
 
   -detach
-	Return immediately instead of entering monitor mode. After deployment
-	resume, the evaluation ID will be printed to the screen, which can be used
-	to examine the evaluation using the eval-status command.
+    Return immediately instead of entering monitor mode. After deployment
+    resume, the evaluation ID will be printed to the screen, which can be used
+    to examine the evaluation using the eval-status command.
 
   -verbose
     Display full information.
@@ -90,14 +90,8 @@ func (c *DeploymentResumeCommand) Run(args []string) int {
 	}
 
 	c.Ui.Output(fmt.Sprintf("Deployment %q resumed", deploy.ID))
-	evalCreated := u.EvalID != ""
-
-	// Nothing to do
-	if detach || !evalCreated {
-		return 0
-	}
-
-	c.Ui.Output("")
-	mon := newMonitor(c.Ui, client, length)
-	return mon.monitor(u.EvalID, false)
+	return monitorDeploymentEval(c.Ui, u.EvalID, detach, func(evalID string) int {
+		mon := newMonitor(c.Ui, client, length)
+		return mon.monitor(evalID, false)
+	})
 }
@@ -32,3 +32,39 @@ func TestDeploymentResumeCommand_Fails(t *testing.T) {
 	}
 	ui.ErrorWriter.Reset()
 }
+
+func TestDeploymentResumeCommand_Detach(t *testing.T) {
+	ui := new(cli.MockUi)
+	monitor := func(evalID string) int {
+		t.Fatalf("unexpected monitor of eval %q", evalID)
+		return 1
+	}
+
+	evalID := "0f4e5b1b-6f2c-4b7a-9d0e-1c2b3a4d5e6f"
+	if code := monitorDeploymentEval(ui, evalID, true, monitor); code != 0 {
+		t.Fatalf("expected exit code 0, got: %d", code)
+	}
+	if out := ui.OutputWriter.String(); !strings.Contains(out, "Evaluation ID: "+evalID) {
+		t.Fatalf("expected eval ID output, got: %s", out)
+	}
+	ui.OutputWriter.Reset()
+
+	// Monitors when not detached
+	monitored := ""
+	monitor = func(evalID string) int {
+		monitored = evalID
+		return 2
+	}
+	if code := monitorDeploymentEval(ui, evalID, false, monitor); code != 2 {
+		t.Fatalf("expected monitor exit code 2, got: %d", code)
+	}
+	if monitored != evalID {
+		t.Fatalf("expected eval %q to be monitored, got %q", evalID, monitored)
+	}
+
+	// Nothing to do without an eval
+	monitored = ""
+	if code := monitorDeploymentEval(ui, "", false, monitor); code != 0 || monitored != "" {
+		t.Fatalf("unexpected monitor of empty eval; exit code %d", code)
+	}
+}
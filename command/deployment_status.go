@@ -2,9 +2,11 @@ package command
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 type DeploymentStatusCommand struct {
@@ -27,11 +29,35 @@ Status Options:
   -verbose
     Display full information.
 
+  -events
+    Display a chronological list of events derived from the state of the
+    deployment's task groups.
+
   -json
-    Output the deployment in its JSON format.
+    Output the deployment in its JSON format. The deployment is wrapped in an
+    object whose "SchemaVersion" field is incremented on every breaking change
+    to the output and whose "Data" field holds the deployment.
+
+  -json-raw
+    Output the JSON without the envelope recording its schema version, as it
+    was output before the envelope was introduced. Must be used with -json.
+
+  -stale
+    Allow the deployment to be read from any server rather than only the
+    leader, reducing the load on the leader at the risk of reading a deployment
+    that is slightly out of date.
 
   -t
     Format and display deployment using a Go template.
+
+  -t-dir
+    Format and display deployment using each Go template in the given
+    directory. A directory is given with this flag rather than as "-t @<dir>"
+    so that inline templates starting with "@" are not mistaken for one.
+
+  -out-dir
+    Write each template rendered from a template directory to a file of the
+    same name in the given directory instead of displaying them.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -41,14 +67,19 @@ func (c *DeploymentStatusCommand) Synopsis() string {
 }
 
 func (c *DeploymentStatusCommand) Run(args []string) int {
-	var json, verbose bool
-	var tmpl string
+	var json, rawJSON, verbose, events, stale bool
+	var tmpl, tmplDir, outDir string
 
 	flags := c.Meta.FlagSet("deployment status", FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&stale, "stale", false, "")
+	flags.BoolVar(&events, "events", false, "")
 	flags.BoolVar(&json, "json", false, "")
+	flags.BoolVar(&rawJSON, "json-raw", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
+	flags.StringVar(&tmplDir, "t-dir", "", "")
+	flags.StringVar(&outDir, "out-dir", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -61,6 +92,11 @@ func (c *DeploymentStatusCommand) Run(args []string) int {
 		return 1
 	}
 
+	if rawJSON && !json {
+		c.Ui.Error("The -json-raw flag can only be used with -json")
+		return 1
+	}
+
 	dID := args[0]
 
 	// Truncate the id unless full length is requested
@@ -77,7 +113,7 @@ func (c *DeploymentStatusCommand) Run(args []string) int {
 	}
 
 	// Do a prefix lookup
-	deploy, possible, err := getDeployment(client.Deployments(), dID)
+	deploy, possible, err := getDeploymentWithOptions(client.Deployments(), dID, &api.QueryOptions{AllowStale: stale})
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error retrieving deployment: %s", err))
 		return 1
@@ -88,8 +124,8 @@ func (c *DeploymentStatusCommand) Run(args []string) int {
 		return 0
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, deploy)
+	if json || len(tmpl) > 0 || tmplDir != "" || outDir != "" {
+		out, err := formatData(json, tmpl, tmplDir, outDir, versionedData(json, rawJSON, deploy))
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -100,13 +136,24 @@ func (c *DeploymentStatusCommand) Run(args []string) int {
 	}
 
 	c.Ui.Output(c.Colorize().Color(formatDeployment(deploy, length)))
+
+	if events {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Events[reset]"))
+		c.Ui.Output(formatDeploymentEvents(deploy))
+	}
 	return 0
 }
 
 func getDeployment(client *api.Deployments, dID string) (match *api.Deployment, possible []*api.Deployment, err error) {
+	return getDeploymentWithOptions(client, dID, nil)
+}
+
+// getDeploymentWithOptions is like getDeployment but issues the lookups with
+// the given query options, such as to allow stale reads.
+func getDeploymentWithOptions(client *api.Deployments, dID string, q *api.QueryOptions) (match *api.Deployment, possible []*api.Deployment, err error) {
 	// First attempt an immediate lookup if we have a proper length
 	if len(dID) == 36 {
-		d, _, err := client.Info(dID, nil)
+		d, _, err := client.Info(dID, q)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -125,7 +172,12 @@ func getDeployment(client *api.Deployments, dID string) (match *api.Deployment,
 	}
 
 	// Have to do a prefix lookup
-	deploys, _, err := client.PrefixList(dID)
+	prefixQ := &api.QueryOptions{}
+	if q != nil {
+		*prefixQ = *q
+	}
+	prefixQ.Prefix = dID
+	deploys, _, err := client.List(prefixQ)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -203,3 +255,80 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 
 	return formatList(rows)
 }
+
+// deploymentEvent is a single step in the progression of a deployment.
+type deploymentEvent struct {
+	// Index is the Raft index at which the event was last observed. The
+	// deployment only records when it was created and last modified, so
+	// events derived from task group state use the modify index.
+	Index uint64
+
+	// Group is the task group the event applies to, if any
+	Group string
+
+	Message string
+}
+
+// deploymentEvents derives the ordered list of events a deployment has gone
+// through from its task group states. The deployment is created first, then
+// each task group progresses from placing canaries to being healthy, and
+// finally the deployment reaches its current status.
+func deploymentEvents(d *api.Deployment) []*deploymentEvent {
+	events := []*deploymentEvent{{
+		Index:   d.CreateIndex,
+		Message: fmt.Sprintf("Deployment created for job version %d", d.JobVersion),
+	}}
+
+	groups := make([]string, 0, len(d.TaskGroups))
+	for tg := range d.TaskGroups {
+		groups = append(groups, tg)
+	}
+	sort.Strings(groups)
+
+	for _, tg := range groups {
+		state := d.TaskGroups[tg]
+		add := func(format string, a ...interface{}) {
+			events = append(events, &deploymentEvent{
+				Index:   d.ModifyIndex,
+				Group:   tg,
+				Message: fmt.Sprintf(format, a...),
+			})
+		}
+
+		if placed := len(state.PlacedCanaries); placed > 0 {
+			add("%d of %d canaries placed", placed, state.DesiredCanaries)
+		}
+		if state.Promoted {
+			add("Canaries promoted")
+		}
+		if state.PlacedAllocs > 0 {
+			add("%d of %d allocations placed", state.PlacedAllocs, state.DesiredTotal)
+		}
+		if state.HealthyAllocs > 0 {
+			add("%d of %d allocations healthy", state.HealthyAllocs, state.DesiredTotal)
+		}
+		if state.UnhealthyAllocs > 0 {
+			add("%d allocations unhealthy", state.UnhealthyAllocs)
+		}
+	}
+
+	if d.Status != structs.DeploymentStatusRunning {
+		events = append(events, &deploymentEvent{
+			Index:   d.ModifyIndex,
+			Message: fmt.Sprintf("Deployment %s: %s", d.Status, d.StatusDescription),
+		})
+	}
+
+	return events
+}
+
+// formatDeploymentEvents formats the derived events of a deployment as a list.
+func formatDeploymentEvents(d *api.Deployment) string {
+	events := deploymentEvents(d)
+	rows := make([]string, len(events)+1)
+	rows[0] = "Index|Task Group|Event"
+	for i, e := range events {
+		rows[i+1] = fmt.Sprintf("%d|%s|%s", e.Index, e.Group, e.Message)
+	}
+	return formatList(rows)
+}
@@ -1,9 +1,11 @@
 package command
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/mitchellh/cli"
 )
 
@@ -32,3 +34,83 @@ func TestDeploymentStatusCommand_Fails(t *testing.T) {
 	}
 	ui.ErrorWriter.Reset()
 }
+
+func TestDeploymentStatusCommand_Events(t *testing.T) {
+	d := &api.Deployment{
+		ID:                "abc",
+		JobVersion:        2,
+		Status:            "successful",
+		StatusDescription: "Deployment completed successfully",
+		CreateIndex:       10,
+		ModifyIndex:       20,
+		TaskGroups: map[string]*api.DeploymentState{
+			"web": {
+				PlacedCanaries:  []string{"a", "b"},
+				DesiredCanaries: 2,
+				Promoted:        true,
+				DesiredTotal:    3,
+				PlacedAllocs:    3,
+				HealthyAllocs:   3,
+			},
+			"cache": {
+				DesiredTotal:    1,
+				PlacedAllocs:    1,
+				HealthyAllocs:   0,
+				UnhealthyAllocs: 1,
+			},
+		},
+	}
+
+	var got []string
+	for _, e := range deploymentEvents(d) {
+		got = append(got, e.Group+": "+e.Message)
+	}
+
+	expected := []string{
+		": Deployment created for job version 2",
+		"cache: 1 of 1 allocations placed",
+		"cache: 1 allocations unhealthy",
+		"web: 2 of 2 canaries placed",
+		"web: Canaries promoted",
+		"web: 3 of 3 allocations placed",
+		"web: 3 of 3 allocations healthy",
+		": Deployment successful: Deployment completed successfully",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("bad events:\ngot: %#v\nexpected: %#v", got, expected)
+	}
+
+	out := formatDeploymentEvents(d)
+	if !strings.HasPrefix(out, "Index") || !strings.Contains(out, "Canaries promoted") {
+		t.Fatalf("bad formatted events: %s", out)
+	}
+}
+
+func TestDeploymentStatusCommand_Stale(t *testing.T) {
+	srv, queried := testStaleServer(t, map[string]string{
+		"/v1/deployment/11111111-2222-3333-4444-555555555555": `{"ID": "11111111-2222-3333-4444-555555555555", "JobID": "web", "Status": "running", "TaskGroups": {}}`,
+	})
+	defer srv.Close()
+
+	for _, stale := range []bool{false, true} {
+		ui := new(cli.MockUi)
+		cmd := &DeploymentStatusCommand{Meta: Meta{Ui: ui}}
+		args := []string{"-address=" + srv.URL}
+		if stale {
+			args = append(args, "-stale")
+		}
+		if code := cmd.Run(append(args, "11111111-2222-3333-4444-555555555555")); code != 0 {
+			t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+		}
+
+		q := queried()
+		if len(q) != 1 {
+			t.Fatalf("unexpected queries: %v", q)
+		}
+		for path, allowStale := range q {
+			if allowStale != stale {
+				t.Fatalf("expected stale=%v for query of %s", stale, path)
+			}
+		}
+	}
+}
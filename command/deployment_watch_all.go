@@ -0,0 +1,209 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+)
+
+type DeploymentWatchAllCommand struct {
+	Meta
+}
+
+func (c *DeploymentWatchAllCommand) Help() string {
+	helpText := `
+Usage: nomad deployment watch-all [options]
+
+Watch-all follows the active deployments of every job in the cluster. Each
+time the deployments change, the deployments that started or completed are
+printed followed by a table of the deployments still running or paused. The
+command runs until interrupted.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Watch-all Options:
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *DeploymentWatchAllCommand) Synopsis() string {
+	return "Watch the active deployments of the cluster"
+}
+
+func (c *DeploymentWatchAllCommand) Run(args []string) int {
+	var verbose bool
+
+	flags := c.Meta.FlagSet("deployment watch-all", FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error(c.Help())
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	stop := make(chan struct{})
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signalCh
+		close(stop)
+	}()
+
+	next := func(waitIndex uint64) ([]*api.Deployment, uint64, error) {
+		deploys, meta, err := client.Deployments().List(&api.QueryOptions{WaitIndex: waitIndex})
+		if err != nil {
+			return nil, 0, err
+		}
+		return deploys, meta.LastIndex, nil
+	}
+
+	ui := &cli.PrefixedUi{
+		InfoPrefix:   "==> ",
+		OutputPrefix: "    ",
+		ErrorPrefix:  "==> ",
+		Ui:           c.Ui,
+	}
+	return watchDeployments(ui, next, stop, length)
+}
+
+// watchDeployments prints the active deployments each time the list of
+// deployments changes until stop is closed. The next function blocks until the
+// deployments change after the given index and returns them and the new
+// index. The deployments that became active or stopped being active since the
+// previous list are printed before the table of active deployments.
+func watchDeployments(ui cli.Ui, next func(waitIndex uint64) ([]*api.Deployment, uint64, error), stop <-chan struct{}, uuidLength int) int {
+	type result struct {
+		deploys []*api.Deployment
+		index   uint64
+		err     error
+	}
+
+	var active map[string]*api.Deployment
+	var index uint64
+	for {
+		resultCh := make(chan result, 1)
+		go func(waitIndex uint64) {
+			deploys, index, err := next(waitIndex)
+			resultCh <- result{deploys, index, err}
+		}(index)
+
+		var r result
+		select {
+		case <-stop:
+			return 0
+		case r = <-resultCh:
+		}
+		if r.err != nil {
+			ui.Error(fmt.Sprintf("Error reading deployments: %s", r.err))
+			return 1
+		}
+
+		// The index goes backwards if the servers lose their state, in which
+		// case the next query must not block on the stale index.
+		if r.index < index {
+			index = 0
+		} else {
+			index = r.index
+		}
+
+		current, events := diffActiveDeployments(active, r.deploys, uuidLength)
+		if active != nil && len(events) == 0 {
+			continue
+		}
+		active = current
+
+		for _, e := range events {
+			ui.Output(e)
+		}
+		ui.Info(formatActiveDeployments(active, uuidLength))
+	}
+}
+
+// diffActiveDeployments returns the running and paused deployments of the
+// list by ID, and a message for each deployment that became active, changed
+// status or is no longer active compared to the previously active
+// deployments.
+func diffActiveDeployments(previous map[string]*api.Deployment, deploys []*api.Deployment, uuidLength int) (map[string]*api.Deployment, []string) {
+	active := make(map[string]*api.Deployment)
+	listed := make(map[string]*api.Deployment, len(deploys))
+	for _, d := range deploys {
+		listed[d.ID] = d
+		switch d.Status {
+		case structs.DeploymentStatusRunning, structs.DeploymentStatusPaused:
+			active[d.ID] = d
+		}
+	}
+
+	var events []string
+	for _, d := range sortedDeployments(active) {
+		prev, ok := previous[d.ID]
+		switch {
+		case !ok:
+			events = append(events, fmt.Sprintf("Deployment %q of job %q started", limit(d.ID, uuidLength), d.JobID))
+		case prev.Status != d.Status:
+			events = append(events, fmt.Sprintf("Deployment %q of job %q is %s", limit(d.ID, uuidLength), d.JobID, d.Status))
+		}
+	}
+	for _, d := range sortedDeployments(previous) {
+		if _, ok := active[d.ID]; ok {
+			continue
+		}
+		if l, ok := listed[d.ID]; ok {
+			events = append(events, fmt.Sprintf("Deployment %q of job %q finished with status %q", limit(d.ID, uuidLength), d.JobID, l.Status))
+		} else {
+			events = append(events, fmt.Sprintf("Deployment %q of job %q is no longer listed", limit(d.ID, uuidLength), d.JobID))
+		}
+	}
+	return active, events
+}
+
+// formatActiveDeployments formats the active deployments as a table, most
+// recent first.
+func formatActiveDeployments(active map[string]*api.Deployment, uuidLength int) string {
+	if len(active) == 0 {
+		return "No active deployments"
+	}
+	return fmt.Sprintf("Active deployments\n\n%s", formatDeployments(sortedDeployments(active), uuidLength))
+}
+
+// sortedDeployments returns the deployments most recent first
+func sortedDeployments(deploys map[string]*api.Deployment) []*api.Deployment {
+	sorted := make([]*api.Deployment, 0, len(deploys))
+	for _, d := range deploys {
+		sorted = append(sorted, d)
+	}
+	sort.Sort(api.DeploymentIndexSort(sorted))
+	return sorted
+}
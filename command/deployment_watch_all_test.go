@@ -0,0 +1,116 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+func TestDeploymentWatchAllCommand_Implements(t *testing.T) {
+	var _ cli.Command = &DeploymentWatchAllCommand{}
+}
+
+func TestDeploymentWatchAllCommand_Fails(t *testing.T) {
+	ui := new(cli.MockUi)
+	cmd := &DeploymentWatchAllCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, cmd.Help()) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=nope"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error reading deployments") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+}
+
+func TestDeploymentWatchAllCommand_Watch(t *testing.T) {
+	deploy := func(id, job, status string, index uint64) *api.Deployment {
+		return &api.Deployment{ID: id, JobID: job, Status: status, CreateIndex: index}
+	}
+	web := "11111111-2222-3333-4444-555555555555"
+	api1 := "22222222-2222-3333-4444-555555555555"
+	cache := "33333333-2222-3333-4444-555555555555"
+
+	snapshots := []struct {
+		deploys []*api.Deployment
+		index   uint64
+	}{
+		{[]*api.Deployment{deploy(web, "web", "running", 10), deploy(cache, "cache", "successful", 5)}, 10},
+		{[]*api.Deployment{deploy(web, "web", "running", 10), deploy(api1, "api", "running", 20), deploy(cache, "cache", "successful", 5)}, 20},
+		// A change to a terminal deployment alone is not printed
+		{[]*api.Deployment{deploy(web, "web", "running", 10), deploy(api1, "api", "running", 20)}, 25},
+		{[]*api.Deployment{deploy(web, "web", "successful", 10), deploy(api1, "api", "paused", 20)}, 30},
+		// The index going backwards resets the wait index
+		{[]*api.Deployment{}, 3},
+	}
+	waitIndexes := []uint64{0, 10, 20, 25, 30, 0}
+
+	stop := make(chan struct{})
+	i := 0
+	next := func(waitIndex uint64) ([]*api.Deployment, uint64, error) {
+		if waitIndex != waitIndexes[i] {
+			t.Errorf("query %d: expected wait index %d, got %d", i, waitIndexes[i], waitIndex)
+		}
+		if i == len(snapshots) {
+			// Interrupt the watch while blocked on the next change
+			close(stop)
+			select {}
+		}
+		s := snapshots[i]
+		i++
+		return s.deploys, s.index, nil
+	}
+
+	ui := new(cli.MockUi)
+	if code := watchDeployments(ui, next, stop, shortId); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+
+	out := ui.OutputWriter.String()
+	expected := []string{
+		`Deployment "11111111" of job "web" started`,
+		`Deployment "22222222" of job "api" started`,
+		`Deployment "22222222" of job "api" is paused`,
+		`Deployment "11111111" of job "web" finished with status "successful"`,
+		`Deployment "22222222" of job "api" is no longer listed`,
+		"No active deployments",
+	}
+	last := -1
+	for _, e := range expected {
+		idx := strings.Index(out, e)
+		if idx <= last {
+			t.Fatalf("expected %q in order in output:\n%s", e, out)
+		}
+		last = idx
+	}
+	if n := strings.Count(out, "Active deployments"); n != 3 {
+		t.Fatalf("expected the table to be printed 3 times, got %d:\n%s", n, out)
+	}
+	if strings.Contains(out, "33333333") {
+		t.Fatalf("unexpected terminal deployment in output:\n%s", out)
+	}
+}
+
+func TestDeploymentWatchAllCommand_Error(t *testing.T) {
+	ui := new(cli.MockUi)
+	next := func(uint64) ([]*api.Deployment, uint64, error) {
+		return nil, 0, fmt.Errorf("no leader")
+	}
+	if code := watchDeployments(ui, next, make(chan struct{}), shortId); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "no leader") {
+		t.Fatalf("expected query error, got: %s", out)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	gg "github.com/hashicorp/go-getter"
@@ -69,6 +70,29 @@ func formatTimeDifference(first, second time.Time, d time.Duration) string {
 	return second.Truncate(d).Sub(first.Truncate(d)).String()
 }
 
+// retryBaseBackoff is the delay before the first retry of retryAPICall. It is
+// doubled before every subsequent retry.
+var retryBaseBackoff = 500 * time.Millisecond
+
+// retryAPICall calls f up to attempts times until it succeeds, backing off
+// exponentially between the calls, and returns the last error. Errors for a
+// 4xx response code are not transient and are returned without retrying.
+func retryAPICall(attempts int, f func() error) error {
+	backoff := retryBaseBackoff
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		if err = f(); err == nil || strings.HasPrefix(err.Error(), "Unexpected response code: 4") {
+			return err
+		}
+	}
+	return err
+}
+
 // getLocalNodeID returns the node ID of the local Nomad Client and an error if
 // it couldn't be determined or the Agent is not running in Client mode.
 func getLocalNodeID(client *api.Client) (string, error) {
@@ -58,6 +58,62 @@ func TestHelpers_NodeID(t *testing.T) {
 	}
 }
 
+// flakyDeploymentsClient is a mock of the deployments API of a job that fails
+// a number of times before succeeding.
+type flakyDeploymentsClient struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (c *flakyDeploymentsClient) Deployments(jobID string) ([]*api.Deployment, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, c.err
+	}
+	return []*api.Deployment{{ID: "foo", JobID: jobID}}, nil
+}
+
+func TestHelpers_RetryAPICall(t *testing.T) {
+	defer func(backoff time.Duration) { retryBaseBackoff = backoff }(retryBaseBackoff)
+	retryBaseBackoff = time.Millisecond
+
+	// Transient errors are retried until the call succeeds
+	client := &flakyDeploymentsClient{failures: 2, err: fmt.Errorf("connection refused")}
+	var deploys []*api.Deployment
+	err := retryAPICall(3, func() error {
+		var err error
+		deploys, err = client.Deployments("example")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.calls != 3 || len(deploys) != 1 || deploys[0].JobID != "example" {
+		t.Fatalf("expected deployments after 3 calls, got %v after %d calls", deploys, client.calls)
+	}
+
+	// The last error is returned once the attempts are exhausted
+	client = &flakyDeploymentsClient{failures: 2, err: fmt.Errorf("connection refused")}
+	err = retryAPICall(2, func() error {
+		_, err := client.Deployments("example")
+		return err
+	})
+	if err == nil || client.calls != 2 {
+		t.Fatalf("expected error after 2 calls, got %v after %d calls", err, client.calls)
+	}
+
+	// Client errors are not retried
+	client = &flakyDeploymentsClient{failures: 2, err: fmt.Errorf("Unexpected response code: 404 (job not found)")}
+	err = retryAPICall(3, func() error {
+		_, err := client.Deployments("example")
+		return err
+	})
+	if err == nil || client.calls != 1 {
+		t.Fatalf("expected error after 1 call, got %v after %d calls", err, client.calls)
+	}
+}
+
 func TestHelpers_LineLimitReader_NoTimeLimit(t *testing.T) {
 	helloString := `hello
 world
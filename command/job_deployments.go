@@ -1,19 +1,70 @@
 package command
 
 import (
+	"bytes"
+	"encoding/csv"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+)
+
+const (
+	// failActionNone and failActionRevert are the supported values of the
+	// -fail-action flag.
+	failActionNone   = "none"
+	failActionRevert = "revert"
+
+	// ambiguousPrefixExitCode is the exit code when the job prefix matches
+	// multiple jobs, distinguishing it from both success and errors.
+	ambiguousPrefixExitCode = 3
+
+	// defaultDeploymentColumns are the columns displayed when -columns is not
+	// set.
+	defaultDeploymentColumns = "id,job,version,status,description"
+
+	// verboseDeploymentColumns are the columns displayed when -columns is not
+	// set and -verbose is.
+	verboseDeploymentColumns = defaultDeploymentColumns + ",canaries"
+
+	// defaultDeploymentCSVColumns are the columns output by -csv when
+	// -columns is not set.
+	defaultDeploymentCSVColumns = "id,status,description,version,created"
 )
 
+// deploymentColumns maps the names accepted by -columns to the header and
+// value of the column.
+var deploymentColumns = map[string]struct {
+	header string
+	value  func(d *api.Deployment, uuidLength int) string
+}{
+	"id":          {"ID", func(d *api.Deployment, l int) string { return limit(d.ID, l) }},
+	"job":         {"Job ID", func(d *api.Deployment, _ int) string { return d.JobID }},
+	"version":     {"Job Version", func(d *api.Deployment, _ int) string { return fmt.Sprintf("%d", d.JobVersion) }},
+	"status":      {"Status", func(d *api.Deployment, _ int) string { return d.Status }},
+	"description": {"Description", func(d *api.Deployment, _ int) string { return d.StatusDescription }},
+	"created":     {"Create Index", func(d *api.Deployment, _ int) string { return fmt.Sprintf("%d", d.CreateIndex) }},
+	"canaries":    {"Canaries", func(d *api.Deployment, _ int) string { return formatDeploymentCanaries(d) }},
+}
+
 type JobDeploymentsCommand struct {
 	Meta
 }
 
 func (c *JobDeploymentsCommand) Help() string {
 	helpText := `
-Usage: nomad job deployments [options] <job>
+Usage: nomad job deployments [options] <job> [<job>...]
+
+Deployments is used to display the deployments for a particular job. If
+several jobs are given, or -prefix matches several jobs, the deployments are
+displayed grouped by job.
 
-Deployments is used to display the deployments for a particular job.
+If the job prefix matches multiple jobs, the matching jobs are listed and the
+exit code is 3.
 
 General Options:
 
@@ -22,16 +73,121 @@ General Options:
 Deployments Options:
 
   -json
-    Output the deployments in a JSON format.
+    Output the deployments in a JSON format. The deployments are wrapped in an
+    object whose "SchemaVersion" field is incremented on every breaking change
+    to the output and whose "Data" field holds the deployments.
+
+  -json-raw
+    Output the JSON without the envelope recording its schema version, as it
+    was output before the envelope was introduced. Must be used with -json.
 
   -t
     Format and display deployments using a Go template.
 
+  -out
+    Write the output of -json or -t to the given file rather than displaying
+    it, creating the parent directories of the file as needed. Must be used
+    with -json or -t.
+
   -latest
-    Display the latest deployment only.
+    Display the latest deployment only. If the job has no deployments, a
+    message is displayed and the exit code is 0 unless -strict is set.
+
+  -summary
+    Display one line per task group of the latest deployment with its healthy
+    and desired allocations, and whether its canaries were promoted. Must be
+    used with -latest.
+
+  -graph
+    Output the task groups of the latest deployment and the progress of their
+    canaries, promotion and health as a Graphviz graph, which can be rendered
+    by piping it to "dot". Must be used with -latest and can not be used with
+    -summary, -wait, -json or -t.
+
+  -explain
+    Display why allocations of the latest deployment could not be placed, such
+    as constraints filtering out nodes or resources being exhausted, as
+    reported by the latest evaluation of the deployment's job version that
+    failed to place allocations. Nothing is displayed once the blocked
+    evaluation waiting to place them has completed. Must be used with -latest
+    and can not be used with -graph, -monitor, -json or -t.
+
+  -strict
+    Exit with a non-zero code if -latest finds no deployment for the job.
+
+  -job-version
+    Display only the deployments of the given version of the job. An error is
+    returned if the job has no such version. Can not be used with -latest.
+
+  -since-version
+    Display only the deployments of the given version of the job or later,
+    such as when investigating a regression introduced by a version. Can not
+    be used with -latest.
+
+  -filter
+    Display only the deployments for which the given Go template evaluates to
+    "true", such as '{{eq .Status "failed"}}'. The template is evaluated
+    against each deployment in the same way as the -t flag. Can not be used
+    with -latest.
+
+  -job-modify-index
+    If set, the latest deployment is only displayed if it was created for the
+    passed job modify index. If the deployment has been superseded by a newer
+    version of the job, an error is returned instead. Must be used with -latest.
+
+  -wait
+    Wait for the latest deployment to complete before displaying it. The exit
+    code is non-zero if the deployment did not complete successfully. Must be
+    used with -latest.
+
+  -monitor
+    Monitor the progress of the latest deployment until it completes, as
+    "nomad deployment monitor" does. The exit code is 0 if the deployment is
+    successful and 2 if it failed or was cancelled. Must be used with -latest
+    and can not be used with -summary, -graph, -wait, -json or -t.
+
+  -fail-action
+    The action to take if the deployment being waited on fails. Either "none"
+    or "revert". When set to "revert", the job is reverted to the most recent
+    stable version prior to the failed deployment. Defaults to "none".
+
+  -columns
+    A comma separated list of the columns to display, in order. The columns
+    are "id", "job", "version", "status", "description", "created", which
+    is the Raft index at which the deployment was created, and "canaries",
+    which is the placed and desired canaries of each task group with canaries.
+    Defaults to "id,job,version,status,description", followed by "canaries"
+    if -verbose is set. Can not be used with -latest, -json or -t.
+
+  -csv
+    Output the deployments as CSV, with a header row naming the columns
+    followed by a row for each deployment. IDs are never truncated. The
+    columns are chosen with -columns and default to
+    "id,status,description,version,created". Can not be used with -latest,
+    -json or -t.
+
+  -prefix
+    Display the deployments of every job whose ID starts with the given
+    prefix rather than requiring the prefix to match a single job. Can not be
+    used with -exact.
+
+  -exact
+    Require the job to be given by its exact ID rather than a prefix. An error
+    is returned if no job has the ID, even if the ID is a prefix of other jobs.
+
+  -stale
+    Allow the deployments to be read from any server rather than only the
+    leader, reducing the load on the leader at the risk of reading deployments
+    that are slightly out of date.
+
+  -retry
+    The number of attempts made for each query to the API before giving up,
+    backing off exponentially between the attempts. Queries are not retried
+    on client errors such as the job not being found. Defaults to 1.
 
   -verbose
-    Display full information.
+    Display full information, including the placed and desired canaries of
+    each task group.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -41,51 +197,225 @@ func (c *JobDeploymentsCommand) Synopsis() string {
 }
 
 func (c *JobDeploymentsCommand) Run(args []string) int {
-	var json, latest, verbose bool
-	var tmpl string
+	var json, rawJSON, latest, verbose, wait, strict, summary, exact, graph, stale, prefix, csvOut, monitor, explain bool
+	var tmpl, outFile, jobModifyIndexStr, failAction, jobVersionStr, sinceVersionStr, filter, columnsStr string
+	var retry int
 
 	flags := c.Meta.FlagSet("job deployments", FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&latest, "latest", false, "")
+	flags.BoolVar(&strict, "strict", false, "")
+	flags.BoolVar(&summary, "summary", false, "")
+	flags.BoolVar(&graph, "graph", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.BoolVar(&json, "json", false, "")
+	flags.BoolVar(&rawJSON, "json-raw", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
+	flags.StringVar(&outFile, "out", "", "")
+	flags.StringVar(&jobModifyIndexStr, "job-modify-index", "", "")
+	flags.BoolVar(&wait, "wait", false, "")
+	flags.BoolVar(&monitor, "monitor", false, "")
+	flags.BoolVar(&explain, "explain", false, "")
+	flags.StringVar(&failAction, "fail-action", failActionNone, "")
+	flags.StringVar(&jobVersionStr, "job-version", "", "")
+	flags.StringVar(&sinceVersionStr, "since-version", "", "")
+	flags.StringVar(&filter, "filter", "", "")
+	flags.StringVar(&columnsStr, "columns", "", "")
+	flags.BoolVar(&exact, "exact", false, "")
+	flags.BoolVar(&prefix, "prefix", false, "")
+	flags.BoolVar(&csvOut, "csv", false, "")
+	flags.BoolVar(&stale, "stale", false, "")
+	flags.IntVar(&retry, "retry", 1, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
-	// Check that we got exactly one node
+	// Check that we got at least one job
 	args = flags.Args()
-	if l := len(args); l != 1 {
+	if l := len(args); l < 1 {
 		c.Ui.Error(c.Help())
 		return 1
 	}
+	multiple := len(args) > 1 || prefix
 
-	// Get the HTTP client
-	client, err := c.Meta.Client()
-	if err != nil {
-		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+	if retry < 1 {
+		c.Ui.Error("The -retry flag must be at least 1")
+		return 1
+	}
+	if rawJSON && !json {
+		c.Ui.Error("The -json-raw flag can only be used with -json")
+		return 1
+	}
+	if outFile != "" && !json && len(tmpl) == 0 {
+		c.Ui.Error("The -out flag can only be used with -json or -t")
 		return 1
 	}
 
-	jobID := args[0]
-
-	// Check if the job exists
-	jobs, _, err := client.Jobs().PrefixList(jobID)
+	// Parse the job-modify-index
+	jobModifyIndex, enforce, err := parseCheckIndex(jobModifyIndexStr)
 	if err != nil {
-		c.Ui.Error(fmt.Sprintf("Error listing jobs: %s", err))
+		c.Ui.Error(fmt.Sprintf("Error parsing job-modify-index value %q: %v", jobModifyIndexStr, err))
 		return 1
 	}
-	if len(jobs) == 0 {
-		c.Ui.Error(fmt.Sprintf("No job(s) with prefix or id %q found", jobID))
+	if enforce && !latest {
+		c.Ui.Error("The -job-modify-index flag can only be used with -latest")
 		return 1
 	}
-	if len(jobs) > 1 && strings.TrimSpace(jobID) != jobs[0].ID {
-		c.Ui.Output(fmt.Sprintf("Prefix matched multiple jobs\n\n%s", createStatusListOutput(jobs)))
-		return 0
+	jobVersion, filterVersion, err := parseCheckIndex(jobVersionStr)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing job-version value %q: %v", jobVersionStr, err))
+		return 1
+	}
+	if filterVersion && latest {
+		c.Ui.Error("The -job-version flag can not be used with -latest")
+		return 1
+	}
+	sinceVersion, filterSince, err := parseCheckIndex(sinceVersionStr)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing since-version value %q: %v", sinceVersionStr, err))
+		return 1
+	}
+	if filterSince && latest {
+		c.Ui.Error("The -since-version flag can not be used with -latest")
+		return 1
+	}
+	if filter != "" && latest {
+		c.Ui.Error("The -filter flag can not be used with -latest")
+		return 1
+	}
+	if columnsStr != "" && (latest || json || len(tmpl) > 0) {
+		c.Ui.Error("The -columns flag can not be used with -latest, -json or -t")
+		return 1
+	}
+	if csvOut && (latest || json || len(tmpl) > 0) {
+		c.Ui.Error("The -csv flag can not be used with -latest, -json or -t")
+		return 1
+	}
+	if columnsStr == "" {
+		columnsStr = defaultDeploymentColumns
+		if verbose {
+			columnsStr = verboseDeploymentColumns
+		}
+		if csvOut {
+			columnsStr = defaultDeploymentCSVColumns
+		}
+	}
+	columns, err := parseDeploymentColumns(columnsStr)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if prefix && exact {
+		c.Ui.Error("The -prefix flag can not be used with -exact")
+		return 1
+	}
+	if multiple && (latest || filterVersion) {
+		c.Ui.Error("The -latest and -job-version flags can not be used with multiple jobs or -prefix")
+		return 1
+	}
+	if summary && !latest {
+		c.Ui.Error("The -summary flag can only be used with -latest")
+		return 1
 	}
-	jobID = jobs[0].ID
+	if summary && (json || len(tmpl) > 0) {
+		c.Ui.Error("The -summary flag can not be used with -json or -t")
+		return 1
+	}
+	if graph && !latest {
+		c.Ui.Error("The -graph flag can only be used with -latest")
+		return 1
+	}
+	if graph && (summary || wait || json || len(tmpl) > 0) {
+		c.Ui.Error("The -graph flag can not be used with -summary, -wait, -json or -t")
+		return 1
+	}
+	if wait && !latest {
+		c.Ui.Error("The -wait flag can only be used with -latest")
+		return 1
+	}
+	if monitor && !latest {
+		c.Ui.Error("The -monitor flag can only be used with -latest")
+		return 1
+	}
+	if monitor && (summary || graph || wait || json || len(tmpl) > 0) {
+		c.Ui.Error("The -monitor flag can not be used with -summary, -graph, -wait, -json or -t")
+		return 1
+	}
+	if explain && !latest {
+		c.Ui.Error("The -explain flag can only be used with -latest")
+		return 1
+	}
+	if explain && (graph || monitor || json || len(tmpl) > 0) {
+		c.Ui.Error("The -explain flag can not be used with -graph, -monitor, -json or -t")
+		return 1
+	}
+	switch failAction {
+	case failActionNone:
+	case failActionRevert:
+		if !wait {
+			c.Ui.Error("The -fail-action flag can only be used with -wait")
+			return 1
+		}
+	default:
+		c.Ui.Error(fmt.Sprintf("Invalid -fail-action %q; must be %q or %q", failAction, failActionNone, failActionRevert))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	q := &api.QueryOptions{AllowStale: stale}
+
+	// Check if the jobs exist
+	var jobIDs []string
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		var jobs []*api.JobListStub
+		err = retryAPICall(retry, func() error {
+			var err error
+			jobs, _, err = client.Jobs().List(&api.QueryOptions{Prefix: arg, AllowStale: stale})
+			return err
+		})
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error listing jobs: %s", err))
+			return 1
+		}
+
+		var ids []string
+		if prefix {
+			if len(jobs) == 0 {
+				c.Ui.Error(fmt.Sprintf("No job(s) with prefix %q found", arg))
+				return 1
+			}
+			for _, j := range jobs {
+				ids = append(ids, j.ID)
+			}
+		} else {
+			id, ambiguous, err := resolveJobPrefix(jobs, arg, exact)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+			if ambiguous {
+				c.Ui.Output(fmt.Sprintf("Prefix matched multiple jobs\n\n%s", createStatusListOutput(jobs)))
+				return ambiguousPrefixExitCode
+			}
+			ids = append(ids, id)
+		}
+
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				jobIDs = append(jobIDs, id)
+			}
+		}
+	}
+	jobID := jobIDs[0]
 
 	// Truncate the id unless full length is requested
 	length := shortId
@@ -93,23 +423,631 @@ func (c *JobDeploymentsCommand) Run(args []string) int {
 		length = fullId
 	}
 
+	if multiple {
+		grouped := make(map[string][]*api.Deployment, len(jobIDs))
+		for _, id := range jobIDs {
+			var deploys []*api.Deployment
+			err := retryAPICall(retry, func() error {
+				var err error
+				deploys, _, err = client.Jobs().Deployments(id, q)
+				return err
+			})
+			if err != nil {
+				c.Ui.Error(fmt.Sprintf("Error retrieving deployments of job %q: %s", id, err))
+				return 1
+			}
+
+			if filterSince {
+				deploys = filterDeploymentsSinceVersion(deploys, sinceVersion)
+			}
+			if filter != "" {
+				deploys, err = filterDeployments(deploys, filter)
+				if err != nil {
+					c.Ui.Error(err.Error())
+					return 1
+				}
+			}
+			grouped[id] = deploys
+		}
+
+		if csvOut {
+			var all []*api.Deployment
+			for _, id := range jobIDs {
+				all = append(all, grouped[id]...)
+			}
+			return c.outputDeploymentsCSV(all, columns)
+		}
+
+		if !c.outputDeployments(json, rawJSON, tmpl, outFile, grouped, formatGroupedDeployments(jobIDs, grouped, columns, length)) {
+			return 1
+		}
+		return 0
+	}
+
 	if latest {
-		deploy, _, err := client.Jobs().LatestDeployment(jobID, nil)
+		var deploy *api.Deployment
+		err := retryAPICall(retry, func() error {
+			var err error
+			deploy, _, err = client.Jobs().LatestDeployment(jobID, q)
+			return err
+		})
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error retrieving deployments: %s", err))
 			return 1
 		}
 
-		c.Ui.Output(c.Colorize().Color(formatDeployment(deploy, length)))
-		return 0
+		if enforce {
+			if err := checkDeploymentJobModifyIndex(deploy, jobModifyIndex); err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+		}
+
+		if deploy == nil {
+			// There is nothing to wait on without a deployment
+			return noLatestDeployment(c.Ui, jobID, strict || wait)
+		}
+
+		if monitor {
+			return monitorDeploymentID(c.Ui, client.Deployments(), deploy.ID, length, false)
+		}
+
+		// The graph is output as is so that it can be piped to dot
+		if graph {
+			c.Ui.Output(formatDeploymentGraph(deploy))
+			return 0
+		}
+
+		format := formatDeployment
+		if summary {
+			format = func(d *api.Deployment, _ int) string { return formatDeploymentSummary(d) }
+		}
+
+		// explainFailures outputs the placement failures of the deployment
+		// if they were requested
+		explainFailures := func(d *api.Deployment) bool {
+			if !explain {
+				return true
+			}
+
+			var evals []*api.Evaluation
+			err := retryAPICall(retry, func() error {
+				var err error
+				evals, _, err = client.Jobs().Evaluations(jobID, q)
+				return err
+			})
+			if err != nil {
+				c.Ui.Error(fmt.Sprintf("Error retrieving evaluations: %s", err))
+				return false
+			}
+			c.Ui.Output(c.Colorize().Color("\n[bold]Placement Failures[reset]"))
+			c.Ui.Output(formatDeploymentPlacementFailures(d, evals, length))
+			return true
+		}
+
+		if !wait {
+			if !c.outputDeployments(json, rawJSON, tmpl, outFile, deploy, format(deploy, length)) {
+				return 1
+			}
+			if !explainFailures(deploy) {
+				return 1
+			}
+			return 0
+		}
+
+		deploy, err = waitForDeployment(client.Deployments(), deploy.ID)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error waiting for deployment: %s", err))
+			return 1
+		}
+		if !c.outputDeployments(json, rawJSON, tmpl, outFile, deploy, format(deploy, length)) {
+			return 1
+		}
+		if !explainFailures(deploy) {
+			return 1
+		}
+
+		if deploy.Status == structs.DeploymentStatusSuccessful {
+			return 0
+		}
+
+		revert := func(d *api.Deployment) (uint64, error) {
+			return revertDeployment(client.Jobs(), d)
+		}
+		if out, err := applyFailAction(deploy, failAction, revert); err != nil {
+			c.Ui.Error(err.Error())
+		} else if out != "" {
+			c.Ui.Output(out)
+		}
+		return 1
 	}
 
-	deploys, _, err := client.Jobs().Deployments(jobID, nil)
+	var deploys []*api.Deployment
+	err = retryAPICall(retry, func() error {
+		var err error
+		deploys, _, err = client.Jobs().Deployments(jobID, q)
+		return err
+	})
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error retrieving deployments: %s", err))
 		return 1
 	}
 
-	c.Ui.Output(formatDeployments(deploys, length))
+	if filterVersion {
+		var versions []*api.Job
+		err := retryAPICall(retry, func() error {
+			var err error
+			versions, _, _, err = client.Jobs().Versions(jobID, false, q)
+			return err
+		})
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error retrieving job versions: %s", err))
+			return 1
+		}
+
+		deploys, err = filterDeploymentsByJobVersion(deploys, versions, jobVersion)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	if filterSince {
+		deploys = filterDeploymentsSinceVersion(deploys, sinceVersion)
+	}
+
+	if filter != "" {
+		deploys, err = filterDeployments(deploys, filter)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	if csvOut {
+		return c.outputDeploymentsCSV(deploys, columns)
+	}
+
+	if !c.outputDeployments(json, rawJSON, tmpl, outFile, deploys, formatDeploymentColumns(deploys, columns, length)) {
+		return 1
+	}
+	return 0
+}
+
+// outputDeploymentsCSV outputs the deployments as CSV and returns the exit
+// code.
+func (c *JobDeploymentsCommand) outputDeploymentsCSV(deploys []*api.Deployment, columns []string) int {
+	out, err := formatDeploymentsCSV(deploys, columns)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error formatting deployments as CSV: %s", err))
+		return 1
+	}
+	c.Ui.Output(out)
+	return 0
+}
+
+// outputDeployments outputs the data as JSON or using the template if either
+// was requested and the already formatted text otherwise. The JSON or template
+// output is written to outFile instead if it is set. It returns false if the
+// data could not be formatted or written.
+func (c *JobDeploymentsCommand) outputDeployments(json, rawJSON bool, tmpl, outFile string, data interface{}, text string) bool {
+	if !json && len(tmpl) == 0 {
+		c.Ui.Output(c.Colorize().Color(text))
+		return true
+	}
+
+	out, err := Format(json, tmpl, versionedData(json, rawJSON, data))
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return false
+	}
+
+	if outFile != "" {
+		if err := WriteFormatted(outFile, out); err != nil {
+			c.Ui.Error(err.Error())
+			return false
+		}
+		c.Ui.Output(fmt.Sprintf("Wrote %s", outFile))
+		return true
+	}
+	c.Ui.Output(out)
+	return true
+}
+
+// parseDeploymentColumns parses the comma separated list of column names
+// passed to -columns. An error is returned for unknown columns.
+func parseDeploymentColumns(s string) ([]string, error) {
+	var columns []string
+	for _, c := range strings.Split(s, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if _, ok := deploymentColumns[c]; !ok {
+			return nil, fmt.Errorf("Unknown column %q; must be one of %s", c, strings.Replace(defaultDeploymentColumns+",created,canaries", ",", ", ", -1))
+		}
+		columns = append(columns, c)
+	}
+	return columns, nil
+}
+
+// formatDeploymentColumns formats the deployments as a table of the given
+// columns.
+func formatDeploymentColumns(deploys []*api.Deployment, columns []string, uuidLength int) string {
+	if len(deploys) == 0 {
+		return "No deployments found"
+	}
+
+	rows := make([]string, len(deploys)+1)
+	values := make([]string, len(columns))
+	for i, c := range columns {
+		values[i] = deploymentColumns[c].header
+	}
+	rows[0] = strings.Join(values, "|")
+	for i, d := range deploys {
+		for j, c := range columns {
+			values[j] = deploymentColumns[c].value(d, uuidLength)
+		}
+		rows[i+1] = strings.Join(values, "|")
+	}
+	return formatList(rows)
+}
+
+// formatDeploymentCanaries returns the placed and desired canaries of each
+// task group of the deployment that has canaries, sorted by name, in the form
+// "group: placed/desired".
+func formatDeploymentCanaries(d *api.Deployment) string {
+	groups := make([]string, 0, len(d.TaskGroups))
+	for tg, state := range d.TaskGroups {
+		if state.DesiredCanaries > 0 {
+			groups = append(groups, tg)
+		}
+	}
+	sort.Strings(groups)
+
+	canaries := make([]string, len(groups))
+	for i, tg := range groups {
+		state := d.TaskGroups[tg]
+		canaries[i] = fmt.Sprintf("%s: %d/%d", tg, len(state.PlacedCanaries), state.DesiredCanaries)
+	}
+	return strings.Join(canaries, ", ")
+}
+
+// formatDeploymentsCSV formats the deployments as CSV with a header row of the
+// column names followed by a row of the given columns for each deployment.
+// Fields are quoted as needed, such as descriptions containing commas.
+func formatDeploymentsCSV(deploys []*api.Deployment, columns []string) (string, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if err := w.Write(columns); err != nil {
+		return "", err
+	}
+
+	values := make([]string, len(columns))
+	for _, d := range deploys {
+		for i, c := range columns {
+			values[i] = deploymentColumns[c].value(d, fullId)
+		}
+		if err := w.Write(values); err != nil {
+			return "", err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// formatGroupedDeployments formats the deployments of each job as a table of
+// the given columns under a header naming the job, in the order of jobIDs.
+func formatGroupedDeployments(jobIDs []string, deploys map[string][]*api.Deployment, columns []string, uuidLength int) string {
+	groups := make([]string, len(jobIDs))
+	for i, id := range jobIDs {
+		groups[i] = fmt.Sprintf("[bold]Job %q[reset]\n%s", id, formatDeploymentColumns(deploys[id], columns, uuidLength))
+	}
+	return strings.Join(groups, "\n\n")
+}
+
+// resolveJobPrefix returns the ID of the job matched by the prefix lookup of
+// jobID. ambiguous is true if the prefix matches multiple jobs and none of them
+// exactly. If exact is set, only a job whose ID is jobID is matched.
+func resolveJobPrefix(jobs []*api.JobListStub, jobID string, exact bool) (id string, ambiguous bool, err error) {
+	jobID = strings.TrimSpace(jobID)
+	if exact {
+		for _, j := range jobs {
+			if j.ID == jobID {
+				return j.ID, false, nil
+			}
+		}
+		return "", false, fmt.Errorf("No job with id %q found", jobID)
+	}
+
+	if len(jobs) == 0 {
+		return "", false, fmt.Errorf("No job(s) with prefix or id %q found", jobID)
+	}
+	if len(jobs) > 1 && jobID != jobs[0].ID {
+		return "", true, nil
+	}
+	return jobs[0].ID, false, nil
+}
+
+// filterDeploymentsByJobVersion returns the deployments of the given job
+// version. An error is returned if the version is not one of the job's
+// versions.
+func filterDeploymentsByJobVersion(deploys []*api.Deployment, versions []*api.Job, version uint64) ([]*api.Deployment, error) {
+	found := false
+	for _, job := range versions {
+		if job.Version != nil && *job.Version == version {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("Job version %d not found", version)
+	}
+
+	var filtered []*api.Deployment
+	for _, d := range deploys {
+		if d.JobVersion == version {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered, nil
+}
+
+// filterDeploymentsSinceVersion returns the deployments of the given job
+// version or later, in their original order.
+func filterDeploymentsSinceVersion(deploys []*api.Deployment, version uint64) []*api.Deployment {
+	var filtered []*api.Deployment
+	for _, d := range deploys {
+		if d.JobVersion >= version {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
+// filterDeployments returns the deployments for which the filter template
+// evaluates to "true". An error is returned if the template fails to evaluate
+// or evaluates to anything other than "true" or "false".
+func filterDeployments(deploys []*api.Deployment, filter string) ([]*api.Deployment, error) {
+	var filtered []*api.Deployment
+	for _, d := range deploys {
+		out, err := Format(false, filter, d)
+		if err != nil {
+			return nil, fmt.Errorf("Error evaluating filter: %s", err)
+		}
+
+		switch strings.TrimSpace(out) {
+		case "true":
+			filtered = append(filtered, d)
+		case "false":
+		default:
+			return nil, fmt.Errorf("Filter must evaluate to true or false, got %q", out)
+		}
+	}
+	return filtered, nil
+}
+
+// formatDeploymentSummary returns one line per task group of the deployment,
+// sorted by name, in the form "group: healthy/desired" followed by whether the
+// canaries were promoted if the group has any.
+func formatDeploymentSummary(d *api.Deployment) string {
+	groups := make([]string, 0, len(d.TaskGroups))
+	for tg := range d.TaskGroups {
+		groups = append(groups, tg)
+	}
+	sort.Strings(groups)
+
+	lines := make([]string, len(groups))
+	for i, tg := range groups {
+		state := d.TaskGroups[tg]
+		line := fmt.Sprintf("%s: %d/%d", tg, state.HealthyAllocs, state.DesiredTotal)
+		if state.DesiredCanaries > 0 {
+			if state.Promoted {
+				line += " (canaries promoted)"
+			} else {
+				line += " (canaries not promoted)"
+			}
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatDeploymentGraph returns a Graphviz graph of the deployment with a
+// cluster per task group, sorted by name. Each cluster chains the stages of the
+// group's rollout: its canaries and their promotion if it has any, followed by
+// the placement and health of its allocations. Completed stages are filled
+// green and the health stage is filled red if any allocation is unhealthy.
+func formatDeploymentGraph(d *api.Deployment) string {
+	groups := make([]string, 0, len(d.TaskGroups))
+	for tg := range d.TaskGroups {
+		groups = append(groups, tg)
+	}
+	sort.Strings(groups)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "digraph %s {\n", strconv.Quote("deployment "+d.ID))
+	fmt.Fprintf(&b, "  label=%s;\n", strconv.Quote(fmt.Sprintf("Deployment %s of job %s (%s)", limit(d.ID, shortId), d.JobID, d.Status)))
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for i, tg := range groups {
+		state := d.TaskGroups[tg]
+
+		type stage struct {
+			name, label, color string
+		}
+		done := func(ok bool) string {
+			if ok {
+				return "palegreen"
+			}
+			return ""
+		}
+
+		var stages []stage
+		if state.DesiredCanaries > 0 {
+			stages = append(stages,
+				stage{"canaries", fmt.Sprintf("canaries %d/%d", len(state.PlacedCanaries), state.DesiredCanaries), done(len(state.PlacedCanaries) >= state.DesiredCanaries)},
+				stage{"promoted", "promoted", done(state.Promoted)})
+		}
+		healthy := stage{"healthy", fmt.Sprintf("healthy %d/%d", state.HealthyAllocs, state.DesiredTotal), done(state.HealthyAllocs >= state.DesiredTotal)}
+		if state.UnhealthyAllocs > 0 {
+			healthy.label += fmt.Sprintf("\n%d unhealthy", state.UnhealthyAllocs)
+			healthy.color = "lightcoral"
+		}
+		stages = append(stages,
+			stage{"placed", fmt.Sprintf("placed %d/%d", state.PlacedAllocs, state.DesiredTotal), done(state.PlacedAllocs >= state.DesiredTotal)},
+			healthy)
+
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "    label=%s;\n", strconv.Quote(tg))
+		ids := make([]string, len(stages))
+		for j, s := range stages {
+			ids[j] = strconv.Quote(tg + "." + s.name)
+			attrs := "label=" + strconv.Quote(s.label)
+			if s.color != "" {
+				attrs += ", style=filled, fillcolor=" + s.color
+			}
+			fmt.Fprintf(&b, "    %s [%s];\n", ids[j], attrs)
+		}
+		fmt.Fprintf(&b, "    %s;\n", strings.Join(ids, " -> "))
+		b.WriteString("  }\n")
+	}
+
+	b.WriteString("}")
+	return b.String()
+}
+
+// formatDeploymentPlacementFailures returns the placement failures of each
+// task group reported by the latest evaluation of the deployment's job version
+// that failed to place allocations, as "nomad eval-status" displays them. The
+// failures are only returned while an evaluation of the job version is
+// blocked waiting to place the allocations; evals must be sorted newest first.
+func formatDeploymentPlacementFailures(d *api.Deployment, evals []*api.Evaluation, uuidLength int) string {
+	var failed *api.Evaluation
+	blocked := false
+	for _, eval := range evals {
+		if eval.DeploymentID != d.ID && eval.JobModifyIndex != d.JobModifyIndex {
+			continue
+		}
+		if eval.Status == structs.EvalStatusBlocked {
+			blocked = true
+		}
+		if failed == nil && len(eval.FailedTGAllocs) != 0 {
+			failed = eval
+		}
+	}
+	if failed == nil || !blocked {
+		return "No placement failures found for the deployment"
+	}
+
+	var lines []string
+	for _, tg := range sortedTaskGroupFromMetrics(failed.FailedTGAllocs) {
+		metrics := failed.FailedTGAllocs[tg]
+
+		noun := "allocation"
+		if metrics.CoalescedFailures > 0 {
+			noun += "s"
+		}
+		lines = append(lines, fmt.Sprintf("Task Group %q (failed to place %d %s):", tg, metrics.CoalescedFailures+1, noun))
+		if out := formatAllocMetrics(metrics, false, "  "); out != "" {
+			lines = append(lines, out)
+		}
+	}
+	if failed.BlockedEval != "" {
+		lines = append(lines, fmt.Sprintf("Evaluation %q waiting for additional capacity to place remainder",
+			limit(failed.BlockedEval, uuidLength)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// noLatestDeployment reports that the job has no deployments and returns the
+// exit code, which is only non-zero if strict.
+func noLatestDeployment(ui cli.Ui, jobID string, strict bool) int {
+	msg := fmt.Sprintf("No deployments found for job %q", jobID)
+	if strict {
+		ui.Error(msg)
+		return 1
+	}
+	ui.Output(msg)
 	return 0
 }
+
+// checkDeploymentJobModifyIndex returns an error if the deployment doesn't exist
+// or was not created for the given job modify index.
+func checkDeploymentJobModifyIndex(d *api.Deployment, jobModifyIndex uint64) error {
+	if d == nil {
+		return fmt.Errorf("No deployment found to check against job modify index %d", jobModifyIndex)
+	}
+	if d.JobModifyIndex != jobModifyIndex {
+		return fmt.Errorf("Latest deployment %q is for job modify index %d, not %d; it has been superseded",
+			d.ID, d.JobModifyIndex, jobModifyIndex)
+	}
+	return nil
+}
+
+// waitForDeployment blocks until the deployment reaches a terminal status and
+// returns the final state of the deployment.
+func waitForDeployment(client *api.Deployments, deployID string) (*api.Deployment, error) {
+	q := &api.QueryOptions{}
+	for {
+		d, meta, err := client.Info(deployID, q)
+		if err != nil {
+			return nil, err
+		}
+
+		switch d.Status {
+		case structs.DeploymentStatusRunning, structs.DeploymentStatusPaused:
+			q.WaitIndex = meta.LastIndex
+		default:
+			return d, nil
+		}
+	}
+}
+
+// applyFailAction runs the fail action for a deployment that did not complete
+// successfully and returns a message describing the result. The revert
+// function is only invoked if the deployment failed and the action is revert.
+func applyFailAction(d *api.Deployment, action string, revert func(*api.Deployment) (uint64, error)) (string, error) {
+	if d.Status != structs.DeploymentStatusFailed || action != failActionRevert {
+		return "", nil
+	}
+
+	version, err := revert(d)
+	if err != nil {
+		return "", fmt.Errorf("Error reverting job %q after failed deployment: %s", d.JobID, err)
+	}
+	return fmt.Sprintf("Deployment failed; reverted job %q to version %d", d.JobID, version), nil
+}
+
+// revertDeployment reverts the job of a failed deployment to the most recent
+// stable version prior to the deployed version and returns that version. The
+// revert is only applied if the job is still at the deployed version.
+func revertDeployment(client *api.Jobs, d *api.Deployment) (uint64, error) {
+	versions, _, _, err := client.Versions(d.JobID, false, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	found := false
+	var version uint64
+	for _, job := range versions {
+		if job.Version == nil || job.Stable == nil || !*job.Stable || *job.Version >= d.JobVersion {
+			continue
+		}
+		if !found || *job.Version > version {
+			version = *job.Version
+			found = true
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("no stable version prior to version %d", d.JobVersion)
+	}
+
+	prior := d.JobVersion
+	if _, _, err := client.Revert(d.JobID, version, &prior, nil); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
@@ -1,9 +1,17 @@
 package command
 
 import (
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/mitchellh/cli"
 )
 
@@ -16,7 +24,7 @@ func TestJobDeploymentsCommand_Fails(t *testing.T) {
 	cmd := &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
 
 	// Fails on misuse
-	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+	if code := cmd.Run([]string{}); code != 1 {
 		t.Fatalf("expected exit code 1, got: %d", code)
 	}
 	if out := ui.ErrorWriter.String(); !strings.Contains(out, cmd.Help()) {
@@ -31,4 +39,712 @@ func TestJobDeploymentsCommand_Fails(t *testing.T) {
 		t.Fatalf("expected failed query error, got: %s", out)
 	}
 	ui.ErrorWriter.Reset()
+
+	// Fails when the job modify index is set without -latest
+	if code := cmd.Run([]string{"-job-modify-index=10", "foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-latest") {
+		t.Fatalf("expected -latest error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails when the job version is combined with -latest
+	if code := cmd.Run([]string{"-latest", "-job-version=1", "foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-job-version") {
+		t.Fatalf("expected -job-version error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-summary", "foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-summary") {
+		t.Fatalf("expected -summary error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-json-raw", "foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-json-raw") {
+		t.Fatalf("expected -json-raw error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on an invalid fail action
+	if code := cmd.Run([]string{"-latest", "-wait", "-fail-action=foo", "foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Invalid -fail-action") {
+		t.Fatalf("expected invalid fail action error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails when the fail action is set without -wait
+	if code := cmd.Run([]string{"-latest", "-fail-action=revert", "foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-wait") {
+		t.Fatalf("expected -wait error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+}
+
+func TestJobDeploymentsCommand_ResolveJobPrefix(t *testing.T) {
+	jobs := []*api.JobListStub{{ID: "web"}, {ID: "web-canary"}}
+
+	// An exact match wins over the other jobs with the prefix
+	for _, exact := range []bool{false, true} {
+		id, ambiguous, err := resolveJobPrefix(jobs, "web", exact)
+		if err != nil || ambiguous || id != "web" {
+			t.Fatalf("exact=%v: expected web, got %q %v %v", exact, id, ambiguous, err)
+		}
+	}
+
+	// A unique prefix matches unless -exact is set
+	id, ambiguous, err := resolveJobPrefix(jobs[1:], "web-c", false)
+	if err != nil || ambiguous || id != "web-canary" {
+		t.Fatalf("expected web-canary, got %q %v %v", id, ambiguous, err)
+	}
+	if _, _, err := resolveJobPrefix(jobs[1:], "web-c", true); err == nil || !strings.Contains(err.Error(), "No job with id") {
+		t.Fatalf("expected exact match error, got %v", err)
+	}
+
+	// An ambiguous prefix is reported, or is an error with -exact
+	if _, ambiguous, err := resolveJobPrefix(jobs, "we", false); err != nil || !ambiguous {
+		t.Fatalf("expected ambiguous prefix, got %v %v", ambiguous, err)
+	}
+	if _, ambiguous, err := resolveJobPrefix(jobs, "we", true); err == nil || ambiguous {
+		t.Fatalf("expected exact match error, got %v %v", ambiguous, err)
+	}
+
+	if _, _, err := resolveJobPrefix(nil, "api", false); err == nil || !strings.Contains(err.Error(), "No job(s)") {
+		t.Fatalf("expected missing job error, got %v", err)
+	}
+}
+
+func TestJobDeploymentsCommand_Columns(t *testing.T) {
+	deploys := []*api.Deployment{
+		{ID: "11111111-2222-3333-4444-555555555555", JobID: "web", JobVersion: 2, Status: "running", StatusDescription: "Deployment is running", CreateIndex: 40},
+	}
+
+	// The default columns match the deployment list
+	columns, err := parseDeploymentColumns(defaultDeploymentColumns)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out, expected := formatDeploymentColumns(deploys, columns, shortId), formatDeployments(deploys, shortId); out != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out)
+	}
+
+	// Columns are selected and ordered as given
+	columns, err = parseDeploymentColumns("status, ID,created")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	lines := strings.Split(formatDeploymentColumns(deploys, columns, shortId), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("unexpected output: %v", lines)
+	}
+	if fields := strings.Fields(lines[0]); !reflect.DeepEqual(fields, []string{"Status", "ID", "Create", "Index"}) {
+		t.Fatalf("unexpected header: %q", lines[0])
+	}
+	if fields := strings.Fields(lines[1]); !reflect.DeepEqual(fields, []string{"running", "11111111", "40"}) {
+		t.Fatalf("unexpected row: %q", lines[1])
+	}
+
+	if _, err := parseDeploymentColumns("id,bogus"); err == nil || !strings.Contains(err.Error(), `Unknown column "bogus"`) {
+		t.Fatalf("expected unknown column error, got %v", err)
+	}
+}
+
+func TestJobDeploymentsCommand_CheckJobModifyIndex(t *testing.T) {
+	d := &api.Deployment{
+		ID:             "foo",
+		JobModifyIndex: 10,
+	}
+
+	if err := checkDeploymentJobModifyIndex(d, 10); err != nil {
+		t.Fatalf("unexpected error for matching index: %v", err)
+	}
+	if err := checkDeploymentJobModifyIndex(d, 20); err == nil || !strings.Contains(err.Error(), "superseded") {
+		t.Fatalf("expected superseded error, got: %v", err)
+	}
+	if err := checkDeploymentJobModifyIndex(nil, 10); err == nil {
+		t.Fatalf("expected error for missing deployment")
+	}
+}
+
+func TestJobDeploymentsCommand_Summary(t *testing.T) {
+	d := &api.Deployment{
+		ID: "11111111-2222-3333-4444-555555555555",
+		TaskGroups: map[string]*api.DeploymentState{
+			"web": {
+				DesiredTotal:    5,
+				DesiredCanaries: 1,
+				Promoted:        true,
+				HealthyAllocs:   4,
+			},
+			"cache": {
+				DesiredTotal:  3,
+				HealthyAllocs: 3,
+			},
+			"api": {
+				DesiredTotal:    2,
+				DesiredCanaries: 2,
+				HealthyAllocs:   1,
+			},
+		},
+	}
+
+	expected := `api: 1/2 (canaries not promoted)
+cache: 3/3
+web: 4/5 (canaries promoted)`
+	if out := formatDeploymentSummary(d); out != expected {
+		t.Fatalf("expected summary:\n%s\nactual:\n%s", expected, out)
+	}
+}
+
+func TestJobDeploymentsCommand_Graph(t *testing.T) {
+	d := &api.Deployment{
+		ID:     "11111111-2222-3333-4444-555555555555",
+		JobID:  "web",
+		Status: "running",
+		TaskGroups: map[string]*api.DeploymentState{
+			"web": {
+				DesiredCanaries: 1,
+				PlacedCanaries:  []string{"a"},
+				DesiredTotal:    3,
+				PlacedAllocs:    1,
+				HealthyAllocs:   1,
+			},
+			"cache": {
+				DesiredTotal:    2,
+				PlacedAllocs:    2,
+				HealthyAllocs:   1,
+				UnhealthyAllocs: 1,
+			},
+		},
+	}
+
+	expected := `digraph "deployment 11111111-2222-3333-4444-555555555555" {
+  label="Deployment 11111111 of job web (running)";
+  rankdir=LR;
+  node [shape=box];
+  subgraph cluster_0 {
+    label="cache";
+    "cache.placed" [label="placed 2/2", style=filled, fillcolor=palegreen];
+    "cache.healthy" [label="healthy 1/2\n1 unhealthy", style=filled, fillcolor=lightcoral];
+    "cache.placed" -> "cache.healthy";
+  }
+  subgraph cluster_1 {
+    label="web";
+    "web.canaries" [label="canaries 1/1", style=filled, fillcolor=palegreen];
+    "web.promoted" [label="promoted"];
+    "web.placed" [label="placed 1/3"];
+    "web.healthy" [label="healthy 1/3"];
+    "web.canaries" -> "web.promoted" -> "web.placed" -> "web.healthy";
+  }
+}`
+	if out := formatDeploymentGraph(d); out != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out)
+	}
+}
+
+func TestJobDeploymentsCommand_NoLatestDeployment(t *testing.T) {
+	ui := new(cli.MockUi)
+	if code := noLatestDeployment(ui, "example", false); code != 0 {
+		t.Fatalf("expected exit code 0, got: %d", code)
+	}
+	if out := ui.OutputWriter.String(); !strings.Contains(out, `No deployments found for job "example"`) {
+		t.Fatalf("expected no deployments message, got: %s", out)
+	}
+
+	ui = new(cli.MockUi)
+	if code := noLatestDeployment(ui, "example", true); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, `No deployments found for job "example"`) {
+		t.Fatalf("expected no deployments error, got: %s", out)
+	}
+}
+
+func TestJobDeploymentsCommand_FilterJobVersion(t *testing.T) {
+	versions := make([]*api.Job, 3)
+	for i := range versions {
+		versions[i] = &api.Job{Version: helper.Uint64ToPtr(uint64(2 - i))}
+	}
+	deploys := []*api.Deployment{
+		{ID: "c", JobVersion: 2},
+		{ID: "b", JobVersion: 1},
+		{ID: "a", JobVersion: 1},
+	}
+
+	filtered, err := filterDeploymentsByJobVersion(deploys, versions, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(filtered) != 2 || filtered[0].ID != "b" || filtered[1].ID != "a" {
+		t.Fatalf("expected deployments b and a, got %v", filtered)
+	}
+
+	filtered, err = filterDeploymentsByJobVersion(deploys, versions, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(filtered) != 0 {
+		t.Fatalf("expected no deployments, got %v", filtered)
+	}
+
+	if _, err := filterDeploymentsByJobVersion(deploys, versions, 5); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected missing version error, got %v", err)
+	}
+}
+
+func TestJobDeploymentsCommand_Filter(t *testing.T) {
+	deploys := []*api.Deployment{
+		{ID: "c", JobVersion: 2, Status: "running"},
+		{ID: "b", JobVersion: 1, Status: "failed"},
+		{ID: "a", JobVersion: 0, Status: "failed"},
+	}
+
+	cases := []struct {
+		filter   string
+		expected []string
+	}{
+		{`{{eq .Status "failed"}}`, []string{"b", "a"}},
+		{`{{and (eq .Status "failed") (gt .JobVersion 0)}}`, []string{"b"}},
+		{`{{eq .Status "successful"}}`, nil},
+	}
+
+	for _, c := range cases {
+		filtered, err := filterDeployments(deploys, c.filter)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.filter, err)
+		}
+
+		var ids []string
+		for _, d := range filtered {
+			ids = append(ids, d.ID)
+		}
+		if !reflect.DeepEqual(ids, c.expected) {
+			t.Fatalf("%s: expected deployments %v, got %v", c.filter, c.expected, ids)
+		}
+	}
+
+	if _, err := filterDeployments(deploys, "{{.Status}}"); err == nil || !strings.Contains(err.Error(), "true or false") {
+		t.Fatalf("expected non-boolean filter error, got %v", err)
+	}
+	if _, err := filterDeployments(deploys, "{{.Missing}}"); err == nil || !strings.Contains(err.Error(), "Error evaluating filter") {
+		t.Fatalf("expected evaluation error, got %v", err)
+	}
+}
+
+func TestJobDeploymentsCommand_ApplyFailAction(t *testing.T) {
+	var reverted []string
+	revert := func(d *api.Deployment) (uint64, error) {
+		reverted = append(reverted, d.ID)
+		return d.JobVersion - 1, nil
+	}
+
+	cases := []struct {
+		status string
+		action string
+		revert bool
+	}{
+		{"failed", failActionRevert, true},
+		{"failed", failActionNone, false},
+		{"cancelled", failActionRevert, false},
+		{"successful", failActionRevert, false},
+	}
+
+	for _, c := range cases {
+		reverted = nil
+		d := &api.Deployment{
+			ID:         "foo",
+			JobID:      "example",
+			JobVersion: 3,
+			Status:     c.status,
+		}
+
+		out, err := applyFailAction(d, c.action, revert)
+		if err != nil {
+			t.Fatalf("%s/%s: unexpected error: %v", c.status, c.action, err)
+		}
+		if c.revert != (len(reverted) == 1) {
+			t.Fatalf("%s/%s: expected revert %v; got reverts %v", c.status, c.action, c.revert, reverted)
+		}
+		if c.revert && !strings.Contains(out, "version 2") {
+			t.Fatalf("%s/%s: expected revert output; got: %s", c.status, c.action, out)
+		}
+	}
+}
+
+func TestJobDeploymentsCommand_MultipleJobs(t *testing.T) {
+	srv, _ := testStaleServer(t, map[string]string{
+		"/v1/jobs":                    `[{"ID": "web-api"}, {"ID": "web-ui"}]`,
+		"/v1/job/web-api/deployments": `[{"ID": "11111111-2222-3333-4444-555555555555", "JobID": "web-api", "Status": "running"}]`,
+		"/v1/job/web-ui/deployments":  `[{"ID": "22222222-2222-3333-4444-555555555555", "JobID": "web-ui", "Status": "failed"}]`,
+	})
+	defer srv.Close()
+
+	ui := new(cli.MockUi)
+	cmd := &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + srv.URL, "-prefix", "web"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+
+	// The deployments are grouped under their job in order
+	out := ui.OutputWriter.String()
+	groups := strings.Split(strings.TrimSpace(out), "\n\n")
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got:\n%s", out)
+	}
+	for i, expected := range []struct{ job, deploy string }{{"web-api", "11111111"}, {"web-ui", "22222222"}} {
+		lines := strings.Split(groups[i], "\n")
+		if len(lines) != 3 || !strings.Contains(lines[0], fmt.Sprintf("Job %q", expected.job)) || !strings.HasPrefix(lines[2], expected.deploy) {
+			t.Fatalf("unexpected group %d:\n%s", i, groups[i])
+		}
+	}
+
+	// Flags only applying to a single job are rejected
+	ui.ErrorWriter.Reset()
+	if code := cmd.Run([]string{"-address=" + srv.URL, "-latest", "web", "api"}); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "can not be used with multiple jobs") {
+		t.Fatalf("expected multiple jobs error, got: %s", out)
+	}
+}
+
+func TestJobDeploymentsCommand_Stale(t *testing.T) {
+	srv, queried := testStaleServer(t, map[string]string{
+		"/v1/jobs":                `[{"ID": "web"}]`,
+		"/v1/job/web/deployments": `[{"ID": "11111111-2222-3333-4444-555555555555", "JobID": "web", "Status": "running", "TaskGroups": {}}]`,
+	})
+	defer srv.Close()
+
+	for _, stale := range []bool{false, true} {
+		ui := new(cli.MockUi)
+		cmd := &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+		args := []string{"-address=" + srv.URL}
+		if stale {
+			args = append(args, "-stale")
+		}
+		if code := cmd.Run(append(args, "web")); code != 0 {
+			t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+		}
+
+		q := queried()
+		if len(q) != 2 {
+			t.Fatalf("unexpected queries: %v", q)
+		}
+		for path, allowStale := range q {
+			if allowStale != stale {
+				t.Fatalf("expected stale=%v for query of %s", stale, path)
+			}
+		}
+	}
+}
+
+func TestJobDeploymentsCommand_CSV(t *testing.T) {
+	srv, _ := testStaleServer(t, map[string]string{
+		"/v1/jobs": `[{"ID": "web"}]`,
+		"/v1/job/web/deployments": `[
+			{"ID": "11111111-2222-3333-4444-555555555555", "JobID": "web", "JobVersion": 2, "Status": "failed",
+			 "StatusDescription": "Failed due to \"unhealthy\" allocations, rolling back", "CreateIndex": 30, "TaskGroups": {}},
+			{"ID": "22222222-2222-3333-4444-555555555555", "JobID": "web", "JobVersion": 1, "Status": "successful",
+			 "StatusDescription": "Deployment completed successfully", "CreateIndex": 20, "TaskGroups": {}}]`,
+	})
+	defer srv.Close()
+
+	ui := new(cli.MockUi)
+	cmd := &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + srv.URL, "-csv", "web"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+
+	records, err := csv.NewReader(strings.NewReader(ui.OutputWriter.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v\n%s", err, ui.OutputWriter.String())
+	}
+	expected := [][]string{
+		{"id", "status", "description", "version", "created"},
+		{"11111111-2222-3333-4444-555555555555", "failed", `Failed due to "unhealthy" allocations, rolling back`, "2", "30"},
+		{"22222222-2222-3333-4444-555555555555", "successful", "Deployment completed successfully", "1", "20"},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("expected records %v, got %v", expected, records)
+	}
+
+	// The columns can be chosen
+	ui = new(cli.MockUi)
+	cmd = &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + srv.URL, "-csv", "-columns=job,status", "web"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	if out := strings.TrimSpace(ui.OutputWriter.String()); out != "job,status\nweb,failed\nweb,successful" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	// Can not be combined with other output formats
+	ui = new(cli.MockUi)
+	cmd = &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + srv.URL, "-csv", "-json", "web"}); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-csv") {
+		t.Fatalf("expected -csv error, got: %s", out)
+	}
+}
+
+func TestJobDeploymentsCommand_SinceVersion(t *testing.T) {
+	deploys := []*api.Deployment{
+		{ID: "d", JobVersion: 3},
+		{ID: "c", JobVersion: 2},
+		{ID: "b", JobVersion: 1},
+		{ID: "a", JobVersion: 0},
+	}
+
+	cases := []struct {
+		version  uint64
+		expected []string
+	}{
+		{0, []string{"d", "c", "b", "a"}},
+		{2, []string{"d", "c"}},
+		{4, nil},
+	}
+	for _, c := range cases {
+		var ids []string
+		for _, d := range filterDeploymentsSinceVersion(deploys, c.version) {
+			ids = append(ids, d.ID)
+		}
+		if !reflect.DeepEqual(ids, c.expected) {
+			t.Fatalf("since %d: expected deployments %v, got %v", c.version, c.expected, ids)
+		}
+	}
+
+	srv, _ := testStaleServer(t, map[string]string{
+		"/v1/jobs": `[{"ID": "web"}]`,
+		"/v1/job/web/deployments": `[
+			{"ID": "33333333-2222-3333-4444-555555555555", "JobID": "web", "JobVersion": 3, "Status": "running", "TaskGroups": {}},
+			{"ID": "22222222-2222-3333-4444-555555555555", "JobID": "web", "JobVersion": 2, "Status": "failed", "TaskGroups": {}},
+			{"ID": "11111111-2222-3333-4444-555555555555", "JobID": "web", "JobVersion": 1, "Status": "successful", "TaskGroups": {}}]`,
+	})
+	defer srv.Close()
+
+	// Combines with the other filters
+	ui := new(cli.MockUi)
+	cmd := &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+	args := []string{"-address=" + srv.URL, "-since-version=2", "-filter={{ne .Status \"running\"}}", "-csv", "-columns=version,status", "web"}
+	if code := cmd.Run(args); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	if out := strings.TrimSpace(ui.OutputWriter.String()); out != "version,status\n2,failed" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	// Can not be used with -latest
+	ui = new(cli.MockUi)
+	cmd = &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + srv.URL, "-since-version=2", "-latest", "web"}); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-since-version") {
+		t.Fatalf("expected -since-version error, got: %s", out)
+	}
+}
+
+func TestJobDeploymentsCommand_Monitor(t *testing.T) {
+	for status, expected := range map[string]int{
+		"successful": 0,
+		"failed":     2,
+	} {
+		deploy := fmt.Sprintf(`{"ID": "11111111-2222-3333-4444-555555555555", "JobID": "web", "Status": %q, "TaskGroups": {}}`, status)
+		srv, queried := testStaleServer(t, map[string]string{
+			"/v1/jobs":               `[{"ID": "web"}]`,
+			"/v1/job/web/deployment": deploy,
+			"/v1/deployment/11111111-2222-3333-4444-555555555555": deploy,
+		})
+
+		ui := new(cli.MockUi)
+		cmd := &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+		if code := cmd.Run([]string{"-address=" + srv.URL, "-latest", "-monitor", "web"}); code != expected {
+			t.Fatalf("%s: expected exit code %d, got %d: %s", status, expected, code, ui.ErrorWriter.String())
+		}
+		if _, ok := queried()["/v1/deployment/11111111-2222-3333-4444-555555555555"]; !ok {
+			t.Fatalf("%s: expected the deployment to be monitored", status)
+		}
+		out := ui.OutputWriter.String()
+		if !strings.Contains(out, `Monitoring deployment "11111111"`) || !strings.Contains(out, status) {
+			t.Fatalf("%s: unexpected output: %s", status, out)
+		}
+		srv.Close()
+	}
+
+	// Must be used with -latest
+	ui := new(cli.MockUi)
+	cmd := &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-monitor", "web"}); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-monitor") {
+		t.Fatalf("expected -monitor error, got: %s", out)
+	}
+}
+
+func TestJobDeploymentsCommand_Canaries(t *testing.T) {
+	d := &api.Deployment{
+		ID: "11111111-2222-3333-4444-555555555555",
+		TaskGroups: map[string]*api.DeploymentState{
+			"web":    {DesiredCanaries: 2, PlacedCanaries: []string{"a"}},
+			"api":    {DesiredCanaries: 1, PlacedCanaries: []string{"b"}},
+			"worker": {DesiredTotal: 3},
+		},
+	}
+	if out := formatDeploymentCanaries(d); out != "api: 1/1, web: 1/2" {
+		t.Fatalf("unexpected canaries: %q", out)
+	}
+
+	srv, _ := testStaleServer(t, map[string]string{
+		"/v1/jobs": `[{"ID": "web"}]`,
+		"/v1/job/web/deployments": `[
+			{"ID": "11111111-2222-3333-4444-555555555555", "JobID": "web", "JobVersion": 2, "Status": "running",
+			 "TaskGroups": {"web": {"DesiredCanaries": 2, "PlacedCanaries": ["a"]}, "api": {"DesiredCanaries": 1, "PlacedCanaries": ["b"]}}}]`,
+	})
+	defer srv.Close()
+
+	// The canaries are only displayed with -verbose
+	ui := new(cli.MockUi)
+	cmd := &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + srv.URL, "web"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	if out := ui.OutputWriter.String(); strings.Contains(out, "Canaries") {
+		t.Fatalf("unexpected canaries: %s", out)
+	}
+
+	ui = new(cli.MockUi)
+	cmd = &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + srv.URL, "-verbose", "web"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	out := ui.OutputWriter.String()
+	if !strings.Contains(out, "Canaries") || !strings.Contains(out, "api: 1/1, web: 1/2") {
+		t.Fatalf("expected canaries, got: %s", out)
+	}
+}
+
+func TestJobDeploymentsCommand_Explain(t *testing.T) {
+	srv, _ := testStaleServer(t, map[string]string{
+		"/v1/jobs": `[{"ID": "web"}]`,
+		"/v1/job/web/deployment": `{"ID": "11111111-2222-3333-4444-555555555555", "JobID": "web", "JobModifyIndex": 40,
+			"Status": "running", "TaskGroups": {"cache": {"DesiredTotal": 3}}}`,
+		"/v1/job/web/evaluations": `[
+			{"ID": "bbbbbbbb-2222-3333-4444-555555555555", "JobID": "web", "JobModifyIndex": 40, "Status": "blocked", "CreateIndex": 42},
+			{"ID": "aaaaaaaa-2222-3333-4444-555555555555", "JobID": "web", "JobModifyIndex": 40, "Status": "complete",
+			 "BlockedEval": "bbbbbbbb-2222-3333-4444-555555555555", "CreateIndex": 41,
+			 "FailedTGAllocs": {"cache": {"NodesEvaluated": 3, "NodesFiltered": 2, "CoalescedFailures": 1,
+			  "ConstraintFiltered": {"${attr.kernel.name} = windows": 2}, "NodesExhausted": 1}}},
+			{"ID": "99999999-2222-3333-4444-555555555555", "JobID": "web", "JobModifyIndex": 30, "Status": "complete", "CreateIndex": 31,
+			 "FailedTGAllocs": {"cache": {"NodesEvaluated": 3, "DimensionExhausted": {"memory": 3}}}}]`,
+	})
+	defer srv.Close()
+
+	ui := new(cli.MockUi)
+	cmd := &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + srv.URL, "-latest", "-explain", "web"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	out := ui.OutputWriter.String()
+	for _, expected := range []string{
+		"Placement Failures",
+		`Task Group "cache" (failed to place 2 allocations):`,
+		`* Constraint "${attr.kernel.name} = windows" filtered 2 nodes`,
+		"* Resources exhausted on 1 nodes",
+		`Evaluation "bbbbbbbb" waiting for additional capacity to place remainder`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("expected %q in output:\n%s", expected, out)
+		}
+	}
+
+	// Failures of other job versions are not reported
+	if strings.Contains(out, `Dimension "memory"`) {
+		t.Fatalf("unexpected failures of another job version:\n%s", out)
+	}
+
+	// Resolved failures are not reported
+	d := &api.Deployment{ID: "11111111-2222-3333-4444-555555555555", JobModifyIndex: 30}
+	evals := []*api.Evaluation{{JobModifyIndex: 30, Status: "complete", FailedTGAllocs: map[string]*api.AllocationMetric{"cache": {}}}}
+	if out := formatDeploymentPlacementFailures(d, evals, shortId); out != "No placement failures found for the deployment" {
+		t.Fatalf("unexpected output: %s", out)
+	}
+
+	// Must be used with -latest
+	ui = new(cli.MockUi)
+	cmd = &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-explain", "web"}); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-explain") {
+		t.Fatalf("expected -explain error, got: %s", out)
+	}
+}
+
+func TestJobDeploymentsCommand_Out(t *testing.T) {
+	srv, _ := testStaleServer(t, map[string]string{
+		"/v1/jobs":                `[{"ID": "web"}]`,
+		"/v1/job/web/deployments": `[{"ID": "11111111-2222-3333-4444-555555555555", "JobID": "web", "Status": "running", "TaskGroups": {}}]`,
+	})
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "deployments")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The parent directories of the file are created
+	path := filepath.Join(dir, "reports", "web", "deployments.txt")
+	ui := new(cli.MockUi)
+	cmd := &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+	args := []string{"-address=" + srv.URL, "-t", "{{range .}}{{.ID}} {{.Status}}{{end}}", "-out", path, "web"}
+	if code := cmd.Run(args); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if string(contents) != "11111111-2222-3333-4444-555555555555 running" {
+		t.Fatalf("unexpected contents: %q", contents)
+	}
+	if out := ui.OutputWriter.String(); strings.Contains(out, "running") {
+		t.Fatalf("unexpected output: %s", out)
+	}
+
+	// Fails when the file can't be written
+	ui = new(cli.MockUi)
+	cmd = &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+	args = []string{"-address=" + srv.URL, "-json", "-out", filepath.Join(path, "nested.json"), "web"}
+	if code := cmd.Run(args); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error creating output directory") {
+		t.Fatalf("expected write error, got: %s", out)
+	}
+
+	// Must be used with -json or -t
+	ui = new(cli.MockUi)
+	cmd = &JobDeploymentsCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-out", path, "web"}); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-out") {
+		t.Fatalf("expected -out error, got: %s", out)
+	}
 }
@@ -0,0 +1,172 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+const (
+	// uniqueAttrPrefix is the prefix of attributes that are expected to differ
+	// between every node.
+	uniqueAttrPrefix = "unique."
+
+	// missingAttrValue is displayed for nodes missing an attribute
+	missingAttrValue = "<none>"
+)
+
+type NodeAttrDiffCommand struct {
+	Meta
+}
+
+func (c *NodeAttrDiffCommand) Help() string {
+	helpText := `
+Usage: nomad node attr-diff [options] -class <class>
+
+  Attr-diff compares the fingerprinted attributes of every node in a node class
+  and reports the attributes whose values differ between the nodes. This is
+  useful to detect nodes that fingerprinted differently from their peers.
+
+  Attributes prefixed with "unique." are expected to differ between nodes and
+  are not compared unless -unique is set.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Attr-diff Options:
+
+  -class
+    The node class of the nodes to compare. Required.
+
+  -unique
+    Include attributes prefixed with "unique." in the comparison.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeAttrDiffCommand) Synopsis() string {
+	return "Report attributes that differ between nodes of a class"
+}
+
+func (c *NodeAttrDiffCommand) Run(args []string) int {
+	var unique, verbose bool
+	var class string
+
+	flags := c.Meta.FlagSet("node attr-diff", FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&class, "class", "", "")
+	flags.BoolVar(&unique, "unique", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments and a class
+	args = flags.Args()
+	if l := len(args); l != 0 || class == "" {
+		c.Ui.Error(c.Help())
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	stubs, _, err := client.Nodes().List(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying nodes: %s", err))
+		return 1
+	}
+
+	var nodes []*api.Node
+	for _, stub := range stubs {
+		if stub.NodeClass != class {
+			continue
+		}
+
+		node, _, err := client.Nodes().Info(stub.ID, nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying node %s: %s", stub.ID, err))
+			return 1
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 0 {
+		c.Ui.Error(fmt.Sprintf("No nodes found in class %q", class))
+		return 1
+	}
+
+	c.Ui.Output(formatNodeAttrDiff(nodes, unique, length))
+	return 0
+}
+
+// nodeAttrDiff returns the sorted names of the attributes whose values are not
+// the same on every node. An attribute missing from some nodes is a difference.
+func nodeAttrDiff(nodes []*api.Node, unique bool) []string {
+	keys := make(map[string]struct{})
+	for _, node := range nodes {
+		for k := range node.Attributes {
+			if !unique && strings.HasPrefix(k, uniqueAttrPrefix) {
+				continue
+			}
+			keys[k] = struct{}{}
+		}
+	}
+
+	var diff []string
+	for k := range keys {
+		first, firstOK := nodes[0].Attributes[k]
+		for _, node := range nodes[1:] {
+			if v, ok := node.Attributes[k]; ok != firstOK || v != first {
+				diff = append(diff, k)
+				break
+			}
+		}
+	}
+
+	sort.Strings(diff)
+	return diff
+}
+
+// formatNodeAttrDiff formats the differing attributes with one row per node
+// for every attribute.
+func formatNodeAttrDiff(nodes []*api.Node, unique bool, uuidLength int) string {
+	diff := nodeAttrDiff(nodes, unique)
+	if len(diff) == 0 {
+		return fmt.Sprintf("No attribute differences found among %d nodes", len(nodes))
+	}
+
+	rows := make([]string, 1, len(diff)*len(nodes)+1)
+	rows[0] = "Attribute|Node ID|Node Name|Value"
+	for _, k := range diff {
+		for _, node := range nodes {
+			v, ok := node.Attributes[k]
+			if !ok {
+				v = missingAttrValue
+			}
+			rows = append(rows, fmt.Sprintf("%s|%s|%s|%s",
+				k,
+				limit(node.ID, uuidLength),
+				node.Name,
+				v))
+		}
+	}
+	return formatList(rows)
+}
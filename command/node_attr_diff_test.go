@@ -0,0 +1,93 @@
+package command
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+func TestNodeAttrDiffCommand_Implements(t *testing.T) {
+	var _ cli.Command = &NodeAttrDiffCommand{}
+}
+
+func TestNodeAttrDiffCommand_Fails(t *testing.T) {
+	ui := new(cli.MockUi)
+	cmd := &NodeAttrDiffCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, cmd.Help()) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails without a class
+	if code := cmd.Run([]string{"-address=nope"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, cmd.Help()) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=nope", "-class=foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error querying nodes") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+}
+
+func TestNodeAttrDiffCommand_Diff(t *testing.T) {
+	nodes := []*api.Node{
+		{
+			ID:   "11111111-2222-3333-4444-555555555555",
+			Name: "client-1",
+			Attributes: map[string]string{
+				"kernel.name":         "linux",
+				"driver.docker":       "1",
+				"unique.hostname":     "client-1",
+				"os.cgroups.pids.max": "max",
+			},
+		},
+		{
+			ID:   "66666666-7777-8888-9999-000000000000",
+			Name: "client-2",
+			Attributes: map[string]string{
+				"kernel.name":         "linux",
+				"unique.hostname":     "client-2",
+				"os.cgroups.pids.max": "max",
+			},
+		},
+	}
+
+	if diff := nodeAttrDiff(nodes, false); !reflect.DeepEqual(diff, []string{"driver.docker"}) {
+		t.Fatalf("unexpected diff: %v", diff)
+	}
+	if diff := nodeAttrDiff(nodes, true); !reflect.DeepEqual(diff, []string{"driver.docker", "unique.hostname"}) {
+		t.Fatalf("unexpected diff with unique attributes: %v", diff)
+	}
+
+	out := formatNodeAttrDiff(nodes, false, shortId)
+	for _, expected := range []string{"Attribute", "driver.docker", "11111111", "client-1", "66666666", missingAttrValue} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("expected %q in output:\n%s", expected, out)
+		}
+	}
+	for _, unexpected := range []string{"kernel.name", "unique.hostname"} {
+		if strings.Contains(out, unexpected) {
+			t.Fatalf("unexpected %q in output:\n%s", unexpected, out)
+		}
+	}
+
+	// Identical nodes report no differences
+	if out := formatNodeAttrDiff(nodes[:1], false, shortId); !strings.Contains(out, "No attribute differences") {
+		t.Fatalf("expected no differences, got:\n%s", out)
+	}
+}
@@ -0,0 +1,123 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+)
+
+type NodeFingerprintRefreshCommand struct {
+	Meta
+}
+
+func (c *NodeFingerprintRefreshCommand) Help() string {
+	helpText := `
+Usage: nomad node fingerprint-refresh [options] <node>
+
+  Fingerprint-refresh makes a client re-run its fingerprinters and driver
+  fingerprints immediately and update its node if any attribute changed. This
+  is useful after installing a driver or mounting a volume on a node, without
+  restarting the client.
+
+  The client must advertise its HTTP address to be reachable by this command.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Fingerprint-refresh Options:
+
+  -self
+    Refresh the fingerprint of the local node.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeFingerprintRefreshCommand) Synopsis() string {
+	return "Re-run the fingerprinters of a node"
+}
+
+func (c *NodeFingerprintRefreshCommand) Run(args []string) int {
+	var self bool
+
+	flags := c.Meta.FlagSet("node fingerprint-refresh", FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&self, "self", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got a node ID
+	args = flags.Args()
+	if l := len(args); self && l != 0 || !self && l != 1 {
+		c.Ui.Error(c.Help())
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// If -self flag is set then determine the current node.
+	nodeID := ""
+	if !self {
+		nodeID = args[0]
+	} else {
+		var err error
+		if nodeID, err = getLocalNodeID(client); err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	// Check if node exists
+	if len(nodeID) == 1 {
+		c.Ui.Error(fmt.Sprintf("Identifier must contain at least two characters."))
+		return 1
+	}
+	if len(nodeID)%2 == 1 {
+		// Identifiers must be of even length, so we strip off the last byte
+		// to provide a consistent user experience.
+		nodeID = nodeID[:len(nodeID)-1]
+	}
+
+	nodes, _, err := client.Nodes().PrefixList(nodeID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying node: %s", err))
+		return 1
+	}
+	// Return error if no nodes are found
+	if len(nodes) == 0 {
+		c.Ui.Error(fmt.Sprintf("No node(s) with prefix or id %q found", nodeID))
+		return 1
+	}
+	if len(nodes) > 1 {
+		// Format the nodes list that matches the prefix so that the user
+		// can create a more specific request
+		out := make([]string, len(nodes)+1)
+		out[0] = "ID|Datacenter|Name|Class|Drain|Status"
+		for i, node := range nodes {
+			out[i+1] = fmt.Sprintf("%s|%s|%s|%s|%v|%s",
+				node.ID,
+				node.Datacenter,
+				node.Name,
+				node.NodeClass,
+				node.Drain,
+				node.Status)
+		}
+		// Dump the output
+		c.Ui.Output(fmt.Sprintf("Prefix matched multiple nodes\n\n%s", formatList(out)))
+		return 0
+	}
+
+	if err := client.Nodes().RefreshFingerprints(nodes[0].ID, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error refreshing fingerprints: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Refreshed fingerprints of node %q", nodes[0].ID))
+	return 0
+}
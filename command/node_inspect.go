@@ -0,0 +1,334 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+type NodeInspectCommand struct {
+	Meta
+}
+
+func (c *NodeInspectCommand) Help() string {
+	helpText := `
+Usage: nomad node inspect [options] <node>
+
+  Inspect displays a single view of a node to help debug placements: its
+  fingerprinted attributes grouped by their prefix, its total, reserved and
+  available resources, its links and its current allocations.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Inspect Options:
+
+  -self
+    Inspect the local node.
+
+  -json
+    Output the node inspection in a JSON format. The inspection is wrapped in
+    an object whose "SchemaVersion" field is incremented on every breaking
+    change to the output and whose "Data" field holds the inspection.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeInspectCommand) Synopsis() string {
+	return "Display the attributes, resources and allocations of a node"
+}
+
+func (c *NodeInspectCommand) Run(args []string) int {
+	var self, json, verbose bool
+
+	flags := c.Meta.FlagSet("node inspect", FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&self, "self", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got a node ID
+	args = flags.Args()
+	if l := len(args); self && l != 0 || !self && l != 1 {
+		c.Ui.Error(c.Help())
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// If -self flag is set then determine the current node.
+	nodeID := ""
+	if !self {
+		nodeID = args[0]
+	} else {
+		var err error
+		if nodeID, err = getLocalNodeID(client); err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	// Check if node exists
+	if len(nodeID) == 1 {
+		c.Ui.Error(fmt.Sprintf("Identifier must contain at least two characters."))
+		return 1
+	}
+	if len(nodeID)%2 == 1 {
+		// Identifiers must be of even length, so we strip off the last byte
+		// to provide a consistent user experience.
+		nodeID = nodeID[:len(nodeID)-1]
+	}
+
+	nodes, _, err := client.Nodes().PrefixList(nodeID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying node: %s", err))
+		return 1
+	}
+	// Return error if no nodes are found
+	if len(nodes) == 0 {
+		c.Ui.Error(fmt.Sprintf("No node(s) with prefix or id %q found", nodeID))
+		return 1
+	}
+	if len(nodes) > 1 {
+		// Format the nodes list that matches the prefix so that the user
+		// can create a more specific request
+		out := make([]string, len(nodes)+1)
+		out[0] = "ID|DC|Name|Class|Drain|Status"
+		for i, node := range nodes {
+			out[i+1] = fmt.Sprintf("%s|%s|%s|%s|%v|%s",
+				limit(node.ID, length),
+				node.Datacenter,
+				node.Name,
+				node.NodeClass,
+				node.Drain,
+				node.Status)
+		}
+		c.Ui.Output(fmt.Sprintf("Prefix matched multiple nodes\n\n%s", formatList(out)))
+		return 0
+	}
+
+	node, _, err := client.Nodes().Info(nodes[0].ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying node info: %s", err))
+		return 1
+	}
+
+	allocs, _, err := client.Nodes().Allocations(node.ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying node allocations: %s", err))
+		return 1
+	}
+
+	inspection := inspectNode(node, allocs)
+	if json {
+		out, err := Format(true, "", versionedData(true, false, inspection))
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(formatNodeInspection(inspection, verbose, length)))
+	return 0
+}
+
+// nodeInspection is the composed view of a node displayed by node inspect
+type nodeInspection struct {
+	ID         string
+	Name       string
+	Class      string
+	Datacenter string
+	Status     string
+	Drain      bool
+
+	// Attributes are the node's attributes grouped by the segment of their
+	// name before the first dot.
+	Attributes map[string]map[string]string
+
+	// Total are the node's resources, Reserved the resources reserved for
+	// processes outside of Nomad and Available the resources that are
+	// neither reserved nor used by a non-terminal allocation.
+	Total     *api.Resources
+	Reserved  *api.Resources
+	Available *api.Resources
+
+	Links       map[string]string
+	Allocations []*api.Allocation
+}
+
+// inspectNode composes the inspection of the node and its allocations
+func inspectNode(node *api.Node, allocs []*api.Allocation) *nodeInspection {
+	i := &nodeInspection{
+		ID:          node.ID,
+		Name:        node.Name,
+		Class:       node.NodeClass,
+		Datacenter:  node.Datacenter,
+		Status:      node.Status,
+		Drain:       node.Drain,
+		Attributes:  make(map[string]map[string]string),
+		Total:       node.Resources,
+		Reserved:    node.Reserved,
+		Links:       node.Links,
+		Allocations: allocs,
+	}
+
+	for k, v := range node.Attributes {
+		group := strings.SplitN(k, ".", 2)[0]
+		if _, ok := i.Attributes[group]; !ok {
+			i.Attributes[group] = make(map[string]string)
+		}
+		i.Attributes[group][k] = v
+	}
+
+	if node.Resources == nil {
+		return i
+	}
+
+	// Subtract the reserved resources and those of the allocations from the
+	// node's resources.
+	used := []*api.Resources{node.Reserved}
+	for _, alloc := range allocs {
+		if allocUsesResources(alloc) {
+			used = append(used, alloc.Resources)
+		}
+	}
+
+	cpu, mem := derefInt(node.Resources.CPU), derefInt(node.Resources.MemoryMB)
+	disk, iops := derefInt(node.Resources.DiskMB), derefInt(node.Resources.IOPS)
+	for _, r := range used {
+		if r == nil {
+			continue
+		}
+		cpu -= derefInt(r.CPU)
+		mem -= derefInt(r.MemoryMB)
+		disk -= derefInt(r.DiskMB)
+		iops -= derefInt(r.IOPS)
+	}
+	i.Available = &api.Resources{
+		CPU:      helper.IntToPtr(cpu),
+		MemoryMB: helper.IntToPtr(mem),
+		DiskMB:   helper.IntToPtr(disk),
+		IOPS:     helper.IntToPtr(iops),
+	}
+	return i
+}
+
+// allocUsesResources returns whether the allocation holds resources on its
+// node, which is the case until it reaches a terminal status.
+func allocUsesResources(alloc *api.Allocation) bool {
+	if alloc.DesiredStatus != structs.AllocDesiredStatusRun {
+		return false
+	}
+	switch alloc.ClientStatus {
+	case structs.AllocClientStatusPending, structs.AllocClientStatusRunning:
+		return true
+	default:
+		return false
+	}
+}
+
+// derefInt returns the value of an optional int, or zero if it is not set
+func derefInt(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+// formatNodeInspection formats the inspection with a section per attribute
+// group followed by the resources, links and allocations of the node.
+func formatNodeInspection(i *nodeInspection, verbose bool, uuidLength int) string {
+	var out []string
+	out = append(out, formatKV([]string{
+		fmt.Sprintf("ID|%s", limit(i.ID, uuidLength)),
+		fmt.Sprintf("Name|%s", i.Name),
+		fmt.Sprintf("Class|%s", i.Class),
+		fmt.Sprintf("DC|%s", i.Datacenter),
+		fmt.Sprintf("Drain|%v", i.Drain),
+		fmt.Sprintf("Status|%s", i.Status),
+	}))
+
+	groups := make([]string, 0, len(i.Attributes))
+	for group := range i.Attributes {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		keys := make([]string, 0, len(i.Attributes[group]))
+		for k := range i.Attributes[group] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		attrs := make([]string, len(keys))
+		for j, k := range keys {
+			attrs[j] = fmt.Sprintf("%s|%s", k, i.Attributes[group][k])
+		}
+		out = append(out, fmt.Sprintf("\n[bold]Attributes: %s[reset]", group), formatKV(attrs))
+	}
+
+	if i.Total != nil {
+		resources := []string{"Resources|CPU|Memory MB|Disk MB|IOPS"}
+		for _, r := range []struct {
+			name string
+			res  *api.Resources
+		}{
+			{"Total", i.Total},
+			{"Reserved", i.Reserved},
+			{"Available", i.Available},
+		} {
+			if r.res == nil {
+				continue
+			}
+			resources = append(resources, fmt.Sprintf("%s|%d MHz|%d|%d|%d",
+				r.name,
+				derefInt(r.res.CPU),
+				derefInt(r.res.MemoryMB),
+				derefInt(r.res.DiskMB),
+				derefInt(r.res.IOPS)))
+		}
+		out = append(out, "\n[bold]Resources[reset]", formatList(resources))
+	}
+
+	if len(i.Links) != 0 {
+		keys := make([]string, 0, len(i.Links))
+		for k := range i.Links {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		links := make([]string, len(keys))
+		for j, k := range keys {
+			links[j] = fmt.Sprintf("%s|%s", k, i.Links[k])
+		}
+		out = append(out, "\n[bold]Links[reset]", formatKV(links))
+	}
+
+	out = append(out, "\n[bold]Allocations[reset]", formatAllocList(i.Allocations, verbose, uuidLength))
+	return strings.Join(out, "\n")
+}
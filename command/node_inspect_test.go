@@ -0,0 +1,142 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/mitchellh/cli"
+)
+
+func TestNodeInspectCommand_Implements(t *testing.T) {
+	var _ cli.Command = &NodeInspectCommand{}
+}
+
+func TestNodeInspectCommand_Fails(t *testing.T) {
+	ui := new(cli.MockUi)
+	cmd := &NodeInspectCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, cmd.Help()) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	if code := cmd.Run([]string{"-address=nope", "12345678-abcd-efab-cdef-123456789abc"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error querying node") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+}
+
+func TestNodeInspectCommand_Inspect(t *testing.T) {
+	resources := func(cpu, mem, disk, iops int) *api.Resources {
+		return &api.Resources{
+			CPU:      helper.IntToPtr(cpu),
+			MemoryMB: helper.IntToPtr(mem),
+			DiskMB:   helper.IntToPtr(disk),
+			IOPS:     helper.IntToPtr(iops),
+		}
+	}
+	node := &api.Node{
+		ID:         "11111111-2222-3333-4444-555555555555",
+		Name:       "client-1",
+		NodeClass:  "batch",
+		Datacenter: "dc1",
+		Status:     "ready",
+		Attributes: map[string]string{
+			"cpu.numcores":  "4",
+			"cpu.frequency": "2500",
+			"driver.docker": "1",
+			"kernel.name":   "linux",
+		},
+		Resources: resources(10000, 8192, 100000, 150),
+		Reserved:  resources(500, 512, 1000, 0),
+		Links: map[string]string{
+			"consul": "dc1.client-1",
+		},
+	}
+	job := &api.Job{Version: helper.Uint64ToPtr(2)}
+	allocs := []*api.Allocation{
+		{
+			ID:            "aaaaaaaa-2222-3333-4444-555555555555",
+			NodeID:        node.ID,
+			TaskGroup:     "web",
+			Job:           job,
+			DesiredStatus: "run",
+			ClientStatus:  "running",
+			Resources:     resources(1000, 1024, 300, 10),
+		},
+		{
+			ID:            "bbbbbbbb-2222-3333-4444-555555555555",
+			NodeID:        node.ID,
+			TaskGroup:     "web",
+			Job:           job,
+			DesiredStatus: "run",
+			ClientStatus:  "pending",
+			Resources:     resources(1000, 1024, 300, 10),
+		},
+		{
+			// Terminal allocations no longer use resources
+			ID:            "cccccccc-2222-3333-4444-555555555555",
+			NodeID:        node.ID,
+			TaskGroup:     "batch",
+			Job:           job,
+			DesiredStatus: "run",
+			ClientStatus:  "complete",
+			Resources:     resources(2000, 2048, 300, 10),
+		},
+	}
+
+	i := inspectNode(node, allocs)
+	if len(i.Attributes) != 3 || len(i.Attributes["cpu"]) != 2 || i.Attributes["driver"]["driver.docker"] != "1" {
+		t.Fatalf("unexpected attribute groups: %v", i.Attributes)
+	}
+	if a := i.Available; *a.CPU != 7500 || *a.MemoryMB != 5632 || *a.DiskMB != 98400 || *a.IOPS != 130 {
+		t.Fatalf("unexpected available resources: %d MHz, %d MB, %d MB, %d IOPS", *a.CPU, *a.MemoryMB, *a.DiskMB, *a.IOPS)
+	}
+	if len(i.Allocations) != 3 {
+		t.Fatalf("expected 3 allocations, got %d", len(i.Allocations))
+	}
+
+	out := formatNodeInspection(i, false, shortId)
+	for _, expected := range []string{
+		"client-1",
+		"Attributes: cpu",
+		"cpu.frequency",
+		"Attributes: driver",
+		"Attributes: kernel",
+		"Total      10000 MHz  8192",
+		"Reserved   500 MHz    512",
+		"Available  7500 MHz   5632",
+		"consul = dc1.client-1",
+		"aaaaaaaa",
+		"cccccccc",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("expected %q in output:\n%s", expected, out)
+		}
+	}
+
+	// Attribute groups are sorted
+	if strings.Index(out, "Attributes: cpu") > strings.Index(out, "Attributes: driver") {
+		t.Fatalf("expected sorted attribute groups:\n%s", out)
+	}
+
+	// The composed view is also output as JSON
+	js, err := Format(true, "", versionedData(true, false, i))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for _, expected := range []string{`"SchemaVersion": 1`, `"Available"`, `"driver.docker": "1"`, `"consul": "dc1.client-1"`} {
+		if !strings.Contains(js, expected) {
+			t.Fatalf("expected %q in JSON output:\n%s", expected, js)
+		}
+	}
+}
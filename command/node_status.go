@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -33,6 +34,7 @@ type NodeStatusCommand struct {
 	stats       bool
 	json        bool
 	tmpl        string
+	wide        bool
 }
 
 func (c *NodeStatusCommand) Help() string {
@@ -58,11 +60,17 @@ Node Status Options:
     Query the status of the local node.
 
   -stats 
-    Display detailed resource usage statistics.
+    Display detailed resource usage statistics and how long each of the
+    node's fingerprinters took to run.
 
   -allocs
     Display a count of running allocations for each node.
 
+  -wide
+    Display the cloud platform, instance type and number of GPUs of each node
+    when listing nodes, as fingerprinted from their attributes. Attributes a
+    node doesn't have are displayed as "-".
+
   -short
     Display short output. Used only when a single node is being
     queried, and drops verbose output about node allocations.
@@ -71,7 +79,8 @@ Node Status Options:
     Display full information.
 
   -json
-    Output the node in its JSON format.
+    Output the node in its JSON format. When querying a single node, the output
+    includes all fingerprinted attributes, resources and links.
 
   -t
     Format and display node using a Go template.
@@ -90,6 +99,7 @@ func (c *NodeStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.short, "short", false, "")
 	flags.BoolVar(&c.verbose, "verbose", false, "")
 	flags.BoolVar(&c.list_allocs, "allocs", false, "")
+	flags.BoolVar(&c.wide, "wide", false, "")
 	flags.BoolVar(&c.self, "self", false, "")
 	flags.BoolVar(&c.stats, "stats", false, "")
 	flags.BoolVar(&c.json, "json", false, "")
@@ -153,6 +163,9 @@ func (c *NodeStatusCommand) Run(args []string) int {
 		} else {
 			out[0] = "ID|DC|Name|Class|Drain|Status"
 		}
+		if c.wide {
+			out[0] += "|Cloud|Instance Type|GPUs"
+		}
 
 		for i, node := range nodes {
 			if c.list_allocs {
@@ -178,6 +191,16 @@ func (c *NodeStatusCommand) Run(args []string) int {
 					node.Drain,
 					node.Status)
 			}
+
+			if c.wide {
+				// The attributes are not part of the node list stubs
+				info, _, err := client.Nodes().Info(node.ID, nil)
+				if err != nil {
+					c.Ui.Error(fmt.Sprintf("Error querying node info: %s", err))
+					return 1
+				}
+				out[i+1] += "|" + strings.Join(nodeWideColumns(info), "|")
+			}
 		}
 
 		// Dump the output
@@ -256,6 +279,48 @@ func (c *NodeStatusCommand) Run(args []string) int {
 	return c.formatNode(client, node)
 }
 
+// nodeWideColumns returns the cloud platform, instance type and number of
+// GPUs of the node from its fingerprinted attributes, such as
+// platform.aws.instance-type and gpu.nvidia.count, displaying "-" for any the
+// node doesn't have. The GPUs of every vendor are counted, falling back to the
+// count of GPU device files if no vendor's GPUs were fingerprinted.
+func nodeWideColumns(n *api.Node) []string {
+	cloud, instanceType, gpus := "-", "-", "-"
+
+	names := make([]string, 0, len(n.Attributes))
+	for k := range n.Attributes {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	vendorGPUs, found := 0, false
+	for _, k := range names {
+		parts := strings.Split(k, ".")
+		if len(parts) != 3 {
+			continue
+		}
+
+		switch {
+		case parts[0] == "platform" && (parts[2] == "instance-type" || parts[2] == "machine-type"):
+			if cloud == "-" {
+				cloud, instanceType = parts[1], n.Attributes[k]
+			}
+		case parts[0] == "gpu" && parts[1] != "device" && parts[2] == "count":
+			if count, err := strconv.Atoi(n.Attributes[k]); err == nil {
+				vendorGPUs += count
+				found = true
+			}
+		}
+	}
+
+	if found {
+		gpus = strconv.Itoa(vendorGPUs)
+	} else if count, ok := n.Attributes["gpu.device.count"]; ok {
+		gpus = count
+	}
+	return []string{cloud, instanceType, gpus}
+}
+
 func nodeDrivers(n *api.Node) []string {
 	var drivers []string
 	for k, v := range n.Attributes {
@@ -337,6 +402,15 @@ func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 			c.printMemoryStats(hostStats)
 			c.Ui.Output(c.Colorize().Color("\n[bold]Disk Stats[reset]"))
 			c.printDiskStats(hostStats)
+
+			durations, err := client.Nodes().FingerprintDurations(node.ID, nil)
+			if err != nil {
+				c.Ui.Output("")
+				c.Ui.Error(fmt.Sprintf("error fetching fingerprint durations: %v", err))
+			} else {
+				c.Ui.Output(c.Colorize().Color("\n[bold]Fingerprint Durations[reset]"))
+				c.Ui.Output(formatList(formatFingerprintDurations(durations)))
+			}
 		}
 	}
 
@@ -436,6 +510,28 @@ func (c *NodeStatusCommand) printDiskStats(hostStats *api.HostStats) {
 	}
 }
 
+// formatFingerprintDurations returns the rows of a list of the fingerprint
+// durations, slowest first.
+func formatFingerprintDurations(durations map[string]time.Duration) []string {
+	names := make([]string, 0, len(durations))
+	for name := range durations {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if durations[names[i]] != durations[names[j]] {
+			return durations[names[i]] > durations[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	rows := make([]string, len(names)+1)
+	rows[0] = "Fingerprinter|Duration"
+	for i, name := range names {
+		rows[i+1] = fmt.Sprintf("%s|%s", name, durations[name])
+	}
+	return rows
+}
+
 // getRunningAllocs returns a slice of allocation id's running on the node
 func getRunningAllocs(client *api.Client, nodeID string) ([]*api.Allocation, error) {
 	var allocs []*api.Allocation
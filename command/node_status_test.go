@@ -2,9 +2,12 @@ package command
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/mitchellh/cli"
 )
@@ -157,6 +160,18 @@ func TestNodeStatusCommand_Run(t *testing.T) {
 	if !strings.Contains(out, "mynode") {
 		t.Fatalf("expect to find mynode, got: %s", out)
 	}
+	ui.OutputWriter.Reset()
+
+	// Query a single node as JSON, which includes the fingerprinted attributes
+	if code := cmd.Run([]string{"-address=" + url, "-json", nodeID}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d", code)
+	}
+	out = ui.OutputWriter.String()
+	for _, attr := range []string{`"Attributes"`, `"kernel.name"`, `"cpu.arch"`, `"Links"`} {
+		if !strings.Contains(out, attr) {
+			t.Fatalf("expected %s in JSON output, got: %s", attr, out)
+		}
+	}
 }
 
 func TestNodeStatusCommand_Fails(t *testing.T) {
@@ -210,3 +225,98 @@ func TestNodeStatusCommand_Fails(t *testing.T) {
 		t.Fatalf("expected getting formatter error, got: %s", out)
 	}
 }
+
+func TestNodeStatusCommand_FormatFingerprintDurations(t *testing.T) {
+	durations := map[string]time.Duration{
+		"cpu":     2 * time.Millisecond,
+		"env_aws": 2 * time.Second,
+		"arch":    2 * time.Millisecond,
+		"docker":  150 * time.Millisecond,
+	}
+
+	expected := []string{
+		"Fingerprinter|Duration",
+		"env_aws|2s",
+		"docker|150ms",
+		"arch|2ms",
+		"cpu|2ms",
+	}
+	if rows := formatFingerprintDurations(durations); !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected rows %v, got %v", expected, rows)
+	}
+}
+
+func TestNodeStatusCommand_Wide(t *testing.T) {
+	srv, _ := testStaleServer(t, map[string]string{
+		"/v1/nodes": `[{"ID": "11111111-2222-3333-4444-555555555555", "Datacenter": "dc1", "Name": "gpu-1", "Status": "ready"},
+			{"ID": "22222222-2222-3333-4444-555555555555", "Datacenter": "dc1", "Name": "bare-1", "Status": "ready"}]`,
+		"/v1/node/11111111-2222-3333-4444-555555555555": `{"ID": "11111111-2222-3333-4444-555555555555", "Name": "gpu-1",
+			"Attributes": {"platform.aws.instance-type": "p3.8xlarge", "gpu.nvidia.count": "4", "gpu.device.count": "4"}}`,
+		"/v1/node/22222222-2222-3333-4444-555555555555": `{"ID": "22222222-2222-3333-4444-555555555555", "Name": "bare-1",
+			"Attributes": {"kernel.name": "linux"}}`,
+	})
+	defer srv.Close()
+
+	ui := new(cli.MockUi)
+	cmd := &NodeStatusCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + srv.URL, "-wide"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and a line per node, got: %v", lines)
+	}
+	if !strings.HasSuffix(lines[0], "Cloud  Instance Type  GPUs") {
+		t.Fatalf("expected wide columns in header, got: %q", lines[0])
+	}
+	expected := map[string][]string{
+		"gpu-1":  {"aws", "p3.8xlarge", "4"},
+		"bare-1": {"-", "-", "-"},
+	}
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		columns, ok := expected[fields[2]]
+		if !ok {
+			t.Fatalf("unexpected line %q", line)
+		}
+		if wide := fields[len(fields)-3:]; !reflect.DeepEqual(wide, columns) {
+			t.Fatalf("expected wide columns %v, got %v", columns, wide)
+		}
+	}
+}
+
+func TestNodeStatusCommand_WideColumns(t *testing.T) {
+	cases := []struct {
+		attrs    map[string]string
+		expected []string
+	}{
+		{
+			attrs:    map[string]string{},
+			expected: []string{"-", "-", "-"},
+		},
+		{
+			attrs: map[string]string{
+				"platform.gce.machine-type":     "n1-standard-8",
+				"platform.gce.machine-type.cpu": "8",
+				"gpu.nvidia.count":              "2",
+				"gpu.amd.count":                 "1",
+				"gpu.nvidia.0.mig.enabled":      "false",
+			},
+			expected: []string{"gce", "n1-standard-8", "3"},
+		},
+		{
+			attrs: map[string]string{
+				"platform.aws.instance-type": "g4dn.xlarge",
+				"gpu.device.count":           "1",
+			},
+			expected: []string{"aws", "g4dn.xlarge", "1"},
+		},
+	}
+
+	for _, c := range cases {
+		if columns := nodeWideColumns(&api.Node{Attributes: c.attrs}); !reflect.DeepEqual(columns, c.expected) {
+			t.Fatalf("expected columns %v for %v, got %v", c.expected, c.attrs, columns)
+		}
+	}
+}
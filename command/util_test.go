@@ -1,6 +1,10 @@
 package command
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/hashicorp/nomad/api"
@@ -65,3 +69,32 @@ func testJob(jobID string) *api.Job {
 
 	return job
 }
+
+// testStaleServer starts an HTTP server serving the canned JSON responses by
+// path. The returned function returns the paths that were queried and whether
+// each query allowed stale reads.
+func testStaleServer(t *testing.T, responses map[string]string) (*httptest.Server, func() map[string]bool) {
+	var lock sync.Mutex
+	queried := make(map[string]bool)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, ok := responses[r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected query of %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		lock.Lock()
+		_, queried[r.URL.Path] = r.URL.Query()["stale"]
+		lock.Unlock()
+
+		w.Header().Set("X-Nomad-Index", "1")
+		fmt.Fprint(w, resp)
+	}))
+
+	return srv, func() map[string]bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return queried
+	}
+}
@@ -59,16 +59,41 @@ func Commands(metaPtr *command.Meta) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"deployment diff": func() (cli.Command, error) {
+			return &command.DeploymentDiffCommand{
+				Meta: meta,
+			}, nil
+		},
 		"deployment fail": func() (cli.Command, error) {
 			return &command.DeploymentFailCommand{
 				Meta: meta,
 			}, nil
 		},
+		"deployment gc-status": func() (cli.Command, error) {
+			return &command.DeploymentGCStatusCommand{
+				Meta: meta,
+			}, nil
+		},
 		"deployment list": func() (cli.Command, error) {
 			return &command.DeploymentListCommand{
 				Meta: meta,
 			}, nil
 		},
+		"deployment logs": func() (cli.Command, error) {
+			return &command.DeploymentLogsCommand{
+				Meta: meta,
+			}, nil
+		},
+		"deployment monitor": func() (cli.Command, error) {
+			return &command.DeploymentMonitorCommand{
+				Meta: meta,
+			}, nil
+		},
+		"deployment overview": func() (cli.Command, error) {
+			return &command.DeploymentOverviewCommand{
+				Meta: meta,
+			}, nil
+		},
 		"deployment pause": func() (cli.Command, error) {
 			return &command.DeploymentPauseCommand{
 				Meta: meta,
@@ -79,6 +104,11 @@ func Commands(metaPtr *command.Meta) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"deployment rerun": func() (cli.Command, error) {
+			return &command.DeploymentRerunCommand{
+				Meta: meta,
+			}, nil
+		},
 		"deployment resume": func() (cli.Command, error) {
 			return &command.DeploymentResumeCommand{
 				Meta: meta,
@@ -89,6 +119,11 @@ func Commands(metaPtr *command.Meta) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"deployment watch-all": func() (cli.Command, error) {
+			return &command.DeploymentWatchAllCommand{
+				Meta: meta,
+			}, nil
+		},
 		"eval-status": func() (cli.Command, error) {
 			return &command.EvalStatusCommand{
 				Meta: meta,
@@ -154,6 +189,26 @@ func Commands(metaPtr *command.Meta) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"node": func() (cli.Command, error) {
+			return &command.NodeCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node attr-diff": func() (cli.Command, error) {
+			return &command.NodeAttrDiffCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node fingerprint-refresh": func() (cli.Command, error) {
+			return &command.NodeFingerprintRefreshCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node inspect": func() (cli.Command, error) {
+			return &command.NodeInspectCommand{
+				Meta: meta,
+			}, nil
+		},
 		"node-drain": func() (cli.Command, error) {
 			return &command.NodeDrainCommand{
 				Meta: meta,
@@ -228,16 +228,28 @@ func (d *Deployment) List(args *structs.DeploymentListRequest, reply *structs.De
 				return err
 			}
 
+			// Deployments are iterated in ID order, so a page starts at the
+			// deployment whose ID is the next token and the token of the
+			// following page is the ID of the first deployment left over.
 			var deploys []*structs.Deployment
+			var nextToken string
 			for {
 				raw := iter.Next()
 				if raw == nil {
 					break
 				}
 				deploy := raw.(*structs.Deployment)
+				if deploy.ID < args.NextToken {
+					continue
+				}
+				if args.PerPage > 0 && len(deploys) == int(args.PerPage) {
+					nextToken = deploy.ID
+					break
+				}
 				deploys = append(deploys, deploy)
 			}
 			reply.Deployments = deploys
+			reply.NextToken = nextToken
 
 			// Use the last index that affected the jobs table
 			index, err := state.Index("deployment")
@@ -503,6 +503,49 @@ func TestDeploymentEndpoint_List(t *testing.T) {
 	assert.Equal(resp2.Deployments[0].ID, d.ID, "Deployment ID")
 }
 
+func TestDeploymentEndpoint_List_Paginated(t *testing.T) {
+	s1 := testServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	assert := assert.New(t)
+
+	// Create three deployments with ordered IDs
+	j := mock.Job()
+	state := s1.fsm.State()
+	assert.Nil(state.UpsertJob(999, j), "UpsertJob")
+	ids := []string{
+		"11111111-0000-0000-0000-000000000000",
+		"22222222-0000-0000-0000-000000000000",
+		"33333333-0000-0000-0000-000000000000",
+	}
+	for i, id := range ids {
+		d := mock.Deployment()
+		d.ID = id
+		d.JobID = j.ID
+		assert.Nil(state.UpsertDeployment(uint64(1000+i), d), "UpsertDeployment")
+	}
+
+	// Read the first page
+	get := &structs.DeploymentListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", PerPage: 2},
+	}
+	var resp structs.DeploymentListResponse
+	assert.Nil(msgpackrpc.CallWithCodec(codec, "Deployment.List", get, &resp), "RPC")
+	assert.Len(resp.Deployments, 2, "Deployments")
+	assert.Equal(ids[0], resp.Deployments[0].ID, "Deployment ID")
+	assert.Equal(ids[1], resp.Deployments[1].ID, "Deployment ID")
+	assert.Equal(ids[2], resp.NextToken, "NextToken")
+
+	// Read the last page from the token
+	get.NextToken = resp.NextToken
+	var resp2 structs.DeploymentListResponse
+	assert.Nil(msgpackrpc.CallWithCodec(codec, "Deployment.List", get, &resp2), "RPC")
+	assert.Len(resp2.Deployments, 1, "Deployments")
+	assert.Equal(ids[2], resp2.Deployments[0].ID, "Deployment ID")
+	assert.Empty(resp2.NextToken, "NextToken")
+}
+
 func TestDeploymentEndpoint_List_Blocking(t *testing.T) {
 	s1 := testServer(t, nil)
 	defer s1.Shutdown()
@@ -114,6 +114,15 @@ type QueryOptions struct {
 
 	// If set, used as prefix for resource list searches
 	Prefix string
+
+	// PerPage is the number of entries to be returned in queries that support
+	// paginated lists. Zero returns every entry.
+	PerPage int32
+
+	// NextToken is the token used to indicate where to start paging for
+	// queries that support paginated lists. It is the value of the NextToken
+	// returned by the previous page.
+	NextToken string
 }
 
 func (q QueryOptions) RequestRegion() string {
@@ -161,6 +170,10 @@ type QueryMeta struct {
 
 	// Used to indicate if there is a known leader node
 	KnownLeader bool
+
+	// NextToken is the token returned with queries that support paginated
+	// lists. It is set if there are more entries to be read.
+	NextToken string
 }
 
 // WriteMeta allows a write response to include potentially